@@ -28,9 +28,12 @@ import (
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/rand"
 	"github.com/microbus-io/fabric/service"
+	"github.com/nats-io/nats-server/v2/server"
 )
 
 // Application is a collection of microservices that run in a single process and share the same lifecycle.
+// If the MICROBUS_NATS environment variable is set to "embedded", the application runs an in-process NATS server
+// for the duration of its lifecycle, and connects its microservices to it.
 type Application struct {
 	initializer     func(service.Service)
 	groups          []group
@@ -40,6 +43,8 @@ type Application struct {
 	mux             sync.Mutex
 	startupTimeout  time.Duration
 	shutdownTimeout time.Duration
+	natsServer      *server.Server
+	natsStoreDir    string
 }
 
 // New creates a new application.
@@ -107,11 +112,23 @@ func (app *Application) AddAndStartup(services ...service.Service) (err error) {
 	}
 	g = append(g, services...)
 	app.groups = append(app.groups, g)
+	startedNATS := app.natsServer == nil
+	err = app.startEmbeddedNATS()
+	if err != nil {
+		app.mux.Unlock()
+		return errors.Trace(err)
+	}
+	app.connectToEmbeddedNATS(g...)
 	app.mux.Unlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), app.startupTimeout)
 	defer cancel()
 	err = g.Startup(ctx)
+	if err != nil && startedNATS {
+		app.mux.Lock()
+		app.abortEmbeddedNATS(g)
+		app.mux.Unlock()
+	}
 	return errors.Trace(err)
 }
 
@@ -155,17 +172,29 @@ func (app *Application) Init(initializer func(svc service.Service)) {
 // Otherwise, microservices are started sequentially in order of inclusion.
 // If an error is returned, there is no guarantee as to the state of the microservices:
 // some microservices may have been started while others not.
+// An embedded NATS server started by the call is shut down on failure, along with the microservices connected to it.
 func (app *Application) Startup() error {
 	app.mux.Lock()
 	defer app.mux.Unlock()
 
+	// Start the embedded NATS server, if so requested
+	startedNATS := app.natsServer == nil
+	err := app.startEmbeddedNATS()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), app.startupTimeout)
 	defer cancel()
 
 	// Start each of the groups sequentially
-	for _, g := range app.groups {
+	for i, g := range app.groups {
+		app.connectToEmbeddedNATS(g...)
 		err := g.Startup(ctx)
 		if err != nil {
+			if startedNATS {
+				app.abortEmbeddedNATS(app.groups[:i+1]...)
+			}
 			return err
 		}
 	}
@@ -189,14 +218,37 @@ func (app *Application) Shutdown() error {
 			return err
 		}
 	}
+
+	// Stop the embedded NATS server, if running
+	app.shutdownEmbeddedNATS()
 	return nil
 }
 
+// Drain drains all started microservices included in this app in preparation for their shutdown.
+// All microservices are drained in parallel. Each stops taking new work and completes its pending operations.
+func (app *Application) Drain() error {
+	app.mux.Lock()
+	defer app.mux.Unlock()
+
+	var all group
+	for _, g := range app.groups {
+		all = append(all, g...)
+	}
+	err := all.Drain()
+	return errors.Trace(err)
+}
+
 // WaitForInterrupt blocks until an interrupt is received through
 // a SIGTERM, SIGINT or a call to interrupt.
 func (app *Application) WaitForInterrupt() {
+	app.waitForSignal()
+}
+
+// waitForSignal blocks until an interrupt is received through
+// a SIGTERM, SIGINT or a call to interrupt, and returns the signal.
+func (app *Application) waitForSignal() os.Signal {
 	signal.Notify(app.sig, syscall.SIGINT, syscall.SIGTERM)
-	<-app.sig
+	return <-app.sig
 }
 
 // Interrupt the app.
@@ -205,13 +257,15 @@ func (app *Application) Interrupt() {
 }
 
 // Run starts up all microservices included in this app, waits for interrupt,
-// then shuts them down.
+// then shuts them down. On SIGTERM, the microservices are drained before being shut down.
 func (app *Application) Run() error {
 	err := app.Startup()
 	if err != nil {
 		return errors.Trace(err)
 	}
-	app.WaitForInterrupt()
+	if app.waitForSignal() == syscall.SIGTERM {
+		_ = app.Drain() // Proceed to shut down even if draining fails
+	}
 	err = app.Shutdown()
 	if err != nil {
 		return errors.Trace(err)
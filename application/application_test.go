@@ -20,11 +20,14 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/coreservices/configurator"
+	"github.com/microbus-io/fabric/env"
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/pub"
 	"github.com/microbus-io/testarossa"
@@ -261,3 +264,124 @@ func TestApplication_Run(t *testing.T) {
 	testarossa.False(t, con.IsStarted())
 	testarossa.False(t, config.IsStarted())
 }
+
+func TestApplication_RunDrain(t *testing.T) {
+	t.Parallel()
+
+	drained := false
+	con := connector.New("run.drain.application")
+	con.SetOnShutdown(func(ctx context.Context) error {
+		drained = con.IsDraining()
+		return nil
+	})
+	app := NewTesting()
+	app.Add(con)
+
+	go func() {
+		err := app.Run()
+		testarossa.NoError(t, err)
+	}()
+
+	time.Sleep(time.Second)
+	testarossa.True(t, con.IsStarted())
+
+	app.sig <- syscall.SIGTERM
+
+	time.Sleep(time.Second)
+	testarossa.False(t, con.IsStarted())
+	testarossa.True(t, drained)
+}
+
+func TestApplication_EmbeddedNATS(t *testing.T) {
+	// No parallel
+
+	env.Push("MICROBUS_NATS", "embedded")
+	defer env.Pop("MICROBUS_NATS")
+	env.Push("MICROBUS_SHORT_CIRCUIT", "false")
+	defer env.Pop("MICROBUS_SHORT_CIRCUIT")
+
+	ctx := context.Background()
+
+	alpha := connector.New("alpha.embedded.nats.application")
+	beta := connector.New("beta.embedded.nats.application")
+	beta.Subscribe("GET", "ping", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("pong"))
+		return nil
+	})
+	app := NewTesting()
+	app.Add(alpha)
+	app.Add(beta)
+
+	// The server starts with the app
+	testarossa.Nil(t, app.natsServer)
+	err := app.Startup()
+	testarossa.NoError(t, err)
+	if testarossa.NotNil(t, app.natsServer) {
+		testarossa.Equal(t, 2, app.natsServer.NumClients())
+		testarossa.True(t, app.natsServer.JetStreamEnabled())
+	}
+	storeDir := app.natsStoreDir
+	testarossa.NotEqual(t, "", storeDir)
+
+	// Microservices communicate over the embedded server
+	res, err := alpha.GET(ctx, "https://beta.embedded.nats.application/ping")
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "pong", string(b))
+	}
+
+	// Microservices added later are also connected to the embedded server
+	gamma := connector.New("gamma.embedded.nats.application")
+	err = app.AddAndStartup(gamma)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 3, app.natsServer.NumClients())
+	res, err = gamma.GET(ctx, "https://beta.embedded.nats.application/ping")
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "pong", string(b))
+	}
+
+	// The server shuts down with the app
+	err = app.Shutdown()
+	testarossa.NoError(t, err)
+	testarossa.Nil(t, app.natsServer)
+	_, err = os.Stat(storeDir)
+	testarossa.True(t, os.IsNotExist(err))
+}
+
+func TestApplication_EmbeddedNATSFailedStartup(t *testing.T) {
+	// No parallel
+
+	env.Push("MICROBUS_NATS", "embedded")
+	defer env.Pop("MICROBUS_NATS")
+	env.Push("MICROBUS_SHORT_CIRCUIT", "false")
+	defer env.Pop("MICROBUS_SHORT_CIRCUIT")
+
+	alpha := connector.New("alpha.embedded.nats.failed.startup.application")
+	beta := connector.New("beta.embedded.nats.failed.startup.application")
+	beta.SetOnStartup(func(ctx context.Context) error {
+		return errors.New("oops")
+	})
+	app := NewTesting()
+	app.startupTimeout = time.Second
+	app.Add(alpha)
+	app.Add(beta)
+
+	// The server and the microservices connected to it are shut down when the startup fails
+	err := app.Startup()
+	testarossa.Error(t, err)
+	testarossa.Nil(t, app.natsServer)
+	testarossa.Equal(t, "", app.natsStoreDir)
+	testarossa.False(t, alpha.IsStarted())
+	testarossa.False(t, beta.IsStarted())
+
+	// Same for microservices added and started later
+	gamma := connector.New("gamma.embedded.nats.failed.startup.application")
+	gamma.SetOnStartup(func(ctx context.Context) error {
+		return errors.New("oops")
+	})
+	err = app.AddAndStartup(gamma)
+	testarossa.Error(t, err)
+	testarossa.Nil(t, app.natsServer)
+	testarossa.False(t, gamma.IsStarted())
+}
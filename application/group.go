@@ -103,3 +103,29 @@ func (grp group) Shutdown(ctx context.Context) error {
 	}
 	return lastErr
 }
+
+// Drain drains a group of microservices in parallel in preparation for their shutdown.
+func (grp group) Drain() error {
+	// Drain the microservices in parallel
+	drainErrs := make(chan error, len(grp))
+	var wg sync.WaitGroup
+	for _, s := range grp {
+		if !s.IsStarted() {
+			continue
+		}
+		wg.Add(1)
+		go func(s service.Service) {
+			defer wg.Done()
+			drainErrs <- s.Drain()
+		}(s)
+	}
+	wg.Wait()
+	close(drainErrs)
+	var lastErr error
+	for e := range drainErrs {
+		if e != nil {
+			lastErr = e
+		}
+	}
+	return lastErr
+}
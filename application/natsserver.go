@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/service"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// startEmbeddedNATS starts an in-process NATS server on a random port if the MICROBUS_NATS environment variable
+// is set to "embedded" and the server is not already running.
+// JetStream is enabled with a temporary storage directory that is removed when the server shuts down.
+func (app *Application) startEmbeddedNATS() error {
+	if app.natsServer != nil || env.Get("MICROBUS_NATS") != "embedded" {
+		return nil
+	}
+	storeDir, err := os.MkdirTemp("", "microbus-nats-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  storeDir,
+	})
+	if err != nil {
+		os.RemoveAll(storeDir)
+		return errors.Trace(err)
+	}
+	ns.Start()
+	if !ns.ReadyForConnections(10 * time.Second) {
+		ns.Shutdown()
+		os.RemoveAll(storeDir)
+		return errors.New("embedded NATS server failed to start")
+	}
+	app.natsServer = ns
+	app.natsStoreDir = storeDir
+	return nil
+}
+
+// shutdownEmbeddedNATS shuts down the in-process NATS server, if it is running.
+func (app *Application) shutdownEmbeddedNATS() {
+	if app.natsServer == nil {
+		return
+	}
+	app.natsServer.Shutdown()
+	app.natsServer.WaitForShutdown()
+	app.natsServer = nil
+	os.RemoveAll(app.natsStoreDir)
+	app.natsStoreDir = ""
+}
+
+// abortEmbeddedNATS shuts down the in-process NATS server after a failed startup,
+// along with the microservices of the groups that were connected to it.
+func (app *Application) abortEmbeddedNATS(groups ...group) {
+	if app.natsServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), app.shutdownTimeout)
+	defer cancel()
+	for i := len(groups) - 1; i >= 0; i-- {
+		groups[i].Shutdown(ctx)
+	}
+	app.shutdownEmbeddedNATS()
+}
+
+// connectToEmbeddedNATS points the microservices at the in-process NATS server, if it is running.
+func (app *Application) connectToEmbeddedNATS(services ...service.Service) {
+	if app.natsServer == nil {
+		return
+	}
+	for _, s := range services {
+		if !s.IsStarted() {
+			s.SetNATSURL(app.natsServer.ClientURL())
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/microbus-io/fabric/cfg"
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/sub"
 	"github.com/microbus-io/fabric/utils"
 )
 
@@ -43,6 +44,21 @@ type Handler struct {
 	Queue       string     `yaml:"queue"`
 	OpenAPI     bool       `yaml:"openApi"`
 
+	// Function and web
+	MaxConcurrency int        `yaml:"maxConcurrency"`
+	MaxPending     int        `yaml:"maxPending"`
+	RateLimit      *RateLimit `yaml:"rateLimit"`
+	Actor          string     `yaml:"actor"`
+	Encrypted      bool       `yaml:"encrypted"`
+	AllowedCallers []string   `yaml:"allowedCallers"`
+
+	// Function
+	Idempotent bool   `yaml:"idempotent"`
+	Encoding   string `yaml:"encoding"`
+
+	// Event
+	Delivery string `yaml:"delivery"`
+
 	// Sink
 	Event   string `yaml:"event"`
 	Source  string `yaml:"source"`
@@ -61,6 +77,9 @@ type Handler struct {
 
 	// Ticker
 	Interval time.Duration `yaml:"interval"`
+
+	// Middleware
+	Route string `yaml:"route"`
 }
 
 // UnmarshalYAML parses the handler.
@@ -84,6 +103,14 @@ func (h *Handler) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if h.Queue == "" {
 		h.Queue = "default"
 	}
+	h.Delivery = strings.ToLower(h.Delivery)
+	if h.Delivery == "" {
+		h.Delivery = "default"
+	}
+	h.Encoding = strings.ToLower(h.Encoding)
+	if h.Encoding == "" {
+		h.Encoding = "json"
+	}
 	h.Method = strings.ToUpper(h.Method)
 	h.Kind = strings.ToLower(h.Kind)
 	if h.Kind == "" {
@@ -106,6 +133,18 @@ func (h *Handler) validate() error {
 	if h.Queue != "default" && h.Queue != "none" {
 		return errors.Newf("invalid queue '%s' in '%s'", h.Queue, h.Name())
 	}
+	if h.Delivery != "default" && h.Delivery != "durable" {
+		return errors.Newf("invalid delivery '%s' in '%s'", h.Delivery, h.Name())
+	}
+	if h.Encoding != "json" && h.Encoding != "cbor" {
+		return errors.Newf("invalid encoding '%s' in '%s'", h.Encoding, h.Name())
+	}
+	if h.MaxConcurrency < 0 {
+		return errors.Newf("negative max concurrency '%d' in '%s'", h.MaxConcurrency, h.Name())
+	}
+	if h.MaxPending < 0 {
+		return errors.Newf("negative max pending '%d' in '%s'", h.MaxPending, h.Name())
+	}
 	if h.Kind != "counter" && h.Kind != "gauge" && h.Kind != "histogram" {
 		return errors.Newf("invalid metric kind '%s' in '%s'", h.Kind, h.Name())
 	}
@@ -115,6 +154,27 @@ func (h *Handler) validate() error {
 	if strings.Contains(h.Path, "`") {
 		return errors.Newf("backquote not allowed in path '%s' in '%s'", h.Path, h.Name())
 	}
+	if strings.Contains(h.Route, "`") {
+		return errors.Newf("backquote not allowed in route '%s' in '%s'", h.Route, h.Name())
+	}
+	if h.Route != "" && !strings.HasPrefix(h.Route, ":") && !strings.HasPrefix(h.Route, "/") {
+		return errors.Newf("route '%s' in '%s' must start with a port or a path", h.Route, h.Name())
+	}
+	if strings.Contains(h.Actor, "`") {
+		return errors.Newf("backquote not allowed in actor '%s' in '%s'", h.Actor, h.Name())
+	}
+	if h.Actor != "" {
+		_, err := sub.ParseActorRequirement(h.Actor)
+		if err != nil {
+			return errors.Newf("invalid actor '%s' in '%s'", h.Actor, h.Name())
+		}
+	}
+	for _, caller := range h.AllowedCallers {
+		err := sub.ValidateCallerPattern(caller)
+		if err != nil {
+			return errors.Newf("invalid allowed caller '%s' in '%s'", caller, h.Name())
+		}
+	}
 	u, err := httpx.ParseURL(httpx.JoinHostAndPath("hostname", h.Path))
 	if err != nil {
 		return errors.Trace(err)
@@ -216,6 +276,10 @@ func (h *Handler) validate() error {
 		if h.Interval <= 0 {
 			return errors.Newf("non-positive interval '%v' in '%s'", h.Interval, h.Name())
 		}
+	case "middleware":
+		if len(h.Signature.InputArgs) != 0 || len(h.Signature.OutputArgs) != 0 {
+			return errors.Newf("arguments or return values not allowed in '%s'", h.Signature.OrigString)
+		}
 	case "function", "event", "sink":
 		for _, arg := range h.Signature.InputArgs {
 			if !h.MethodWithBody() && arg.Name == "httpRequestBody" {
@@ -251,6 +315,42 @@ func (h *Handler) validate() error {
 			return errors.Newf("event name '%s' must start with 'On' in '%s'", h.Event, h.Name())
 		}
 	}
+	if h.Type != "function" && h.Type != "web" {
+		if h.MaxConcurrency != 0 || h.MaxPending != 0 {
+			return errors.Newf("concurrency limits not allowed in '%s'", h.Name())
+		}
+		if h.RateLimit != nil {
+			return errors.Newf("rate limit not allowed in '%s'", h.Name())
+		}
+	}
+	if h.Actor != "" && h.Type != "function" && h.Type != "web" {
+		return errors.Newf("actor not allowed in '%s'", h.Name())
+	}
+	if h.Encrypted && h.Type != "function" && h.Type != "web" {
+		return errors.Newf("encryption not allowed in '%s'", h.Name())
+	}
+	if len(h.AllowedCallers) > 0 && h.Type != "function" && h.Type != "web" {
+		return errors.Newf("allowed callers not allowed in '%s'", h.Name())
+	}
+	if h.Route != "" && h.Type != "middleware" {
+		return errors.Newf("route not allowed in '%s'", h.Name())
+	}
+	if h.Encoding != "json" && h.Type != "function" {
+		return errors.Newf("encoding not allowed in '%s'", h.Name())
+	}
+	if h.Idempotent {
+		if h.Type != "function" {
+			return errors.Newf("idempotency not allowed in '%s'", h.Name())
+		}
+		if h.Queue == "none" {
+			return errors.Newf("idempotency not allowed with pervasive queue in '%s'", h.Name())
+		}
+	}
+	if h.Type == "event" && h.Delivery == "durable" {
+		if len(h.Signature.OutputArgs) != 0 {
+			return errors.Newf("return values not allowed in durable event '%s'", h.Signature.OrigString)
+		}
+	}
 	if h.Type == "sink" || h.Type == "event" {
 		if !startsWithOn.MatchString(h.Name()) {
 			return errors.Newf("function name must start with 'On' in '%s'", h.Signature.OrigString)
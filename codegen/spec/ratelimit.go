@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"math"
+
+	"github.com/microbus-io/fabric/errors"
+)
+
+// RateLimit is the rate limit of a function or web handler.
+type RateLimit struct {
+	Rate    float64 `yaml:"rate"`
+	Burst   int     `yaml:"burst"`
+	Cluster bool    `yaml:"cluster"`
+}
+
+// UnmarshalYAML parses and validates the YAML.
+func (rl *RateLimit) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// Unmarshal
+	type different RateLimit
+	var x different
+	err := unmarshal(&x)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	*rl = RateLimit(x)
+
+	// Post processing
+	if rl.Burst == 0 {
+		rl.Burst = int(math.Max(1, math.Ceil(rl.Rate)))
+	}
+
+	// Validate
+	err = rl.validate()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// validate validates the data after unmarshaling.
+func (rl *RateLimit) validate() error {
+	if rl.Rate <= 0 {
+		return errors.Newf("non-positive rate '%v'", rl.Rate)
+	}
+	if rl.Burst < 0 {
+		return errors.Newf("negative burst '%d'", rl.Burst)
+	}
+	return nil
+}
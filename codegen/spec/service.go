@@ -36,6 +36,8 @@ type Service struct {
 	Webs      []*Handler `yaml:"webs"`
 	Tickers   []*Handler `yaml:"tickers"`
 
+	Middleware []*Handler `yaml:"middleware"`
+
 	Types []*Type `yaml:"-"`
 
 	fullyQualified bool
@@ -160,6 +162,9 @@ func (s *Service) validate() error {
 	for _, w := range s.Metrics {
 		w.Type = "metric"
 	}
+	for _, w := range s.Middleware {
+		w.Type = "middleware"
+	}
 	for _, h := range s.AllHandlers() {
 		err := h.validate()
 		if err != nil {
@@ -215,5 +220,6 @@ func (s *Service) AllHandlers() []*Handler {
 	result = append(result, s.Sinks...)
 	result = append(result, s.Webs...)
 	result = append(result, s.Tickers...)
+	result = append(result, s.Middleware...)
 	return result
 }
@@ -52,6 +52,53 @@ functions:
     path: //bad.ho$t
 `), &svc)
 	testarossa.ErrorContains(t, err, "invalid hostname")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    maxConcurrency: -1
+`), &svc)
+	testarossa.ErrorContains(t, err, "negative max concurrency")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    maxConcurrency: 8
+    maxPending: -1
+`), &svc)
+	testarossa.ErrorContains(t, err, "negative max pending")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    rateLimit:
+      rate: 0
+`), &svc)
+	testarossa.ErrorContains(t, err, "non-positive rate")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    queue: none
+    idempotent: true
+`), &svc)
+	testarossa.ErrorContains(t, err, "idempotency not allowed with pervasive queue")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    encoding: xml
+`), &svc)
+	testarossa.ErrorContains(t, err, "invalid encoding")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func(s string)
+    rateLimit:
+      rate: 10
+      burst: -1
+`), &svc)
+	testarossa.ErrorContains(t, err, "negative burst")
 }
 
 func TestSpec_ErrorsInPathArguments(t *testing.T) {
@@ -134,6 +181,49 @@ events:
     path: :0/...
 `), &svc)
 	testarossa.ErrorContains(t, err, "invalid port")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int)
+    delivery: sometimes
+`), &svc)
+	testarossa.ErrorContains(t, err, "invalid delivery")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int) (ok bool)
+    delivery: durable
+`), &svc)
+	testarossa.ErrorContains(t, err, "return values not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int)
+    maxConcurrency: 8
+`), &svc)
+	testarossa.ErrorContains(t, err, "concurrency limits not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int)
+    rateLimit:
+      rate: 10
+`), &svc)
+	testarossa.ErrorContains(t, err, "rate limit not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int)
+    idempotent: true
+`), &svc)
+	testarossa.ErrorContains(t, err, "idempotency not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc(s []*int)
+    encoding: cbor
+`), &svc)
+	testarossa.ErrorContains(t, err, "encoding not allowed")
 }
 
 func TestSpec_ErrorsInSinks(t *testing.T) {
@@ -270,6 +360,132 @@ tickers:
 	testarossa.ErrorContains(t, err, "non-positive interval")
 }
 
+func TestSpec_ErrorsInMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var svc Service
+	general := `
+general:
+  host: ok.host
+`
+
+	err := yaml.Unmarshal([]byte(general+`
+middleware:
+  - signature: Func(x int)
+`), &svc)
+	testarossa.ErrorContains(t, err, "arguments or return values not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+middleware:
+  - signature: Func() (x int)
+`), &svc)
+	testarossa.ErrorContains(t, err, "arguments or return values not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+middleware:
+  - signature: Func()
+    route: admin
+`), &svc)
+	testarossa.ErrorContains(t, err, "must start with a port or a path")
+
+	err = yaml.Unmarshal([]byte(general+`
+middleware:
+  - signature: Func()
+    route: /admin/`+"`"+`
+`), &svc)
+	testarossa.ErrorContains(t, err, "backquote not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+webs:
+  - signature: Func()
+    route: /admin/
+`), &svc)
+	testarossa.ErrorContains(t, err, "route not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+middleware:
+  - signature: Func()
+    route: :443/admin/
+`), &svc)
+	testarossa.NoError(t, err)
+}
+
+func TestSpec_ErrorsInActor(t *testing.T) {
+	t.Parallel()
+
+	var svc Service
+	general := `
+general:
+  host: ok.host
+`
+
+	err := yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func()
+    actor: roles admin
+`), &svc)
+	testarossa.ErrorContains(t, err, "invalid actor")
+
+	err = yaml.Unmarshal([]byte(general+`
+webs:
+  - signature: Func()
+    actor: roles.admin`+"`"+`
+`), &svc)
+	testarossa.ErrorContains(t, err, "backquote not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc()
+    actor: roles.admin
+`), &svc)
+	testarossa.ErrorContains(t, err, "actor not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func()
+    actor: roles.admin || roles.manager && verified
+webs:
+  - signature: Web()
+    actor: roles.admin
+`), &svc)
+	testarossa.NoError(t, err)
+}
+
+func TestSpec_ErrorsInEncryption(t *testing.T) {
+	t.Parallel()
+
+	var svc Service
+	general := `
+general:
+  host: ok.host
+`
+
+	err := yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc()
+    encrypted: true
+`), &svc)
+	testarossa.ErrorContains(t, err, "encryption not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+tickers:
+  - signature: Tick()
+    interval: 1m
+    encrypted: true
+`), &svc)
+	testarossa.ErrorContains(t, err, "encryption not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func()
+    encrypted: true
+webs:
+  - signature: Web()
+    encrypted: true
+`), &svc)
+	testarossa.NoError(t, err)
+}
+
 func TestSpec_ErrorsInWebs(t *testing.T) {
 	t.Parallel()
 
@@ -310,6 +526,13 @@ webs:
     queue: skip
 `), &svc)
 	testarossa.ErrorContains(t, err, "invalid queue")
+
+	err = yaml.Unmarshal([]byte(general+`
+webs:
+  - signature: Func()
+    maxConcurrency: -1
+`), &svc)
+	testarossa.ErrorContains(t, err, "negative max concurrency")
 }
 
 func TestSpec_ErrorsInService(t *testing.T) {
@@ -368,3 +591,44 @@ functions:
 	testarossa.Equal(t, "Defined", svc.Functions[0].Signature.InputArgs[0].Type)
 	testarossa.Equal(t, "Imported", svc.Functions[0].Signature.OutputArgs[0].Type)
 }
+
+func TestSpec_ErrorsInAllowedCallers(t *testing.T) {
+	t.Parallel()
+
+	var svc Service
+	general := `
+general:
+  host: ok.host
+`
+
+	err := yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func()
+    allowedCallers: [billing.*]
+`), &svc)
+	testarossa.ErrorContains(t, err, "invalid allowed caller")
+
+	err = yaml.Unmarshal([]byte(general+`
+webs:
+  - signature: Web()
+    allowedCallers: ["bill ing.core"]
+`), &svc)
+	testarossa.ErrorContains(t, err, "invalid allowed caller")
+
+	err = yaml.Unmarshal([]byte(general+`
+events:
+  - signature: OnFunc()
+    allowedCallers: [billing.core]
+`), &svc)
+	testarossa.ErrorContains(t, err, "allowed callers not allowed")
+
+	err = yaml.Unmarshal([]byte(general+`
+functions:
+  - signature: Func()
+    allowedCallers: [billing.core, "*.admin"]
+webs:
+  - signature: Web()
+    allowedCallers: ["*"]
+`), &svc)
+	testarossa.NoError(t, err)
+}
@@ -872,6 +872,140 @@ func WhatTimeIsIt(testingT *testing.T, ctx context.Context) *WhatTimeIsItTestCas
 	return tc
 }
 
+// WhisperTestCase assists in asserting against the results of executing Whisper.
+type WhisperTestCase struct {
+	_t *testing.T
+	_dur time.Duration
+	echo string
+	err error
+}
+
+// Expect asserts no error and exact return values.
+func (_tc *WhisperTestCase) Expect(echo string) *WhisperTestCase {
+	if testarossa.NoError(_tc._t, _tc.err) {
+		testarossa.Equal(_tc._t, echo, _tc.echo)
+	}
+	return _tc
+}
+
+// Error asserts an error.
+func (tc *WhisperTestCase) Error(errContains string) *WhisperTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Contains(tc._t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *WhisperTestCase) ErrorCode(statusCode int) *WhisperTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Equal(tc._t, statusCode, errors.StatusCode(tc.err))
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *WhisperTestCase) NoError() *WhisperTestCase {
+	testarossa.NoError(tc._t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *WhisperTestCase) CompletedIn(threshold time.Duration) *WhisperTestCase {
+	testarossa.True(tc._t, tc._dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *WhisperTestCase) Assert(asserter func(t *testing.T, echo string, err error)) *WhisperTestCase {
+	asserter(tc._t, tc.echo, tc.err)
+	return tc
+}
+
+// Get returns the result of executing Whisper.
+func (tc *WhisperTestCase) Get() (echo string, err error) {
+	return tc.echo, tc.err
+}
+
+// Whisper executes the function and returns a corresponding test case.
+func Whisper(t *testing.T, ctx context.Context, secret string) *WhisperTestCase {
+	tc := &WhisperTestCase{_t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		tc.echo, tc.err = Svc.Whisper(ctx, secret)
+		return tc.err
+	})
+	tc._dur = time.Since(t0)
+	return tc
+}
+
+// OnlyFriendsTestCase assists in asserting against the results of executing OnlyFriends.
+type OnlyFriendsTestCase struct {
+	_t *testing.T
+	_dur time.Duration
+	ok bool
+	err error
+}
+
+// Expect asserts no error and exact return values.
+func (_tc *OnlyFriendsTestCase) Expect(ok bool) *OnlyFriendsTestCase {
+	if testarossa.NoError(_tc._t, _tc.err) {
+		testarossa.Equal(_tc._t, ok, _tc.ok)
+	}
+	return _tc
+}
+
+// Error asserts an error.
+func (tc *OnlyFriendsTestCase) Error(errContains string) *OnlyFriendsTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Contains(tc._t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *OnlyFriendsTestCase) ErrorCode(statusCode int) *OnlyFriendsTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Equal(tc._t, statusCode, errors.StatusCode(tc.err))
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *OnlyFriendsTestCase) NoError() *OnlyFriendsTestCase {
+	testarossa.NoError(tc._t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *OnlyFriendsTestCase) CompletedIn(threshold time.Duration) *OnlyFriendsTestCase {
+	testarossa.True(tc._t, tc._dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *OnlyFriendsTestCase) Assert(asserter func(t *testing.T, ok bool, err error)) *OnlyFriendsTestCase {
+	asserter(tc._t, tc.ok, tc.err)
+	return tc
+}
+
+// Get returns the result of executing OnlyFriends.
+func (tc *OnlyFriendsTestCase) Get() (ok bool, err error) {
+	return tc.ok, tc.err
+}
+
+// OnlyFriends executes the function and returns a corresponding test case.
+func OnlyFriends(t *testing.T, ctx context.Context) *OnlyFriendsTestCase {
+	tc := &OnlyFriendsTestCase{_t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		tc.ok, tc.err = Svc.OnlyFriends(ctx)
+		return tc.err
+	})
+	tc._dur = time.Since(t0)
+	return tc
+}
+
 // OnDiscoveredSinkTestCase assists in asserting against the results of executing OnDiscoveredSink.
 type OnDiscoveredSinkTestCase struct {
 	_t *testing.T
@@ -4616,3 +4750,570 @@ func Hello(t *testing.T, r *http.Request) *HelloTestCase {
 	tc.dur = time.Since(t0)
 	return tc
 }
+
+// WhoAmITestCase assists in asserting against the results of executing WhoAmI.
+type WhoAmITestCase struct {
+	t *testing.T
+	dur time.Duration
+	res *http.Response
+	err error
+}
+
+// StatusOK asserts no error and a status code 200.
+func (tc *WhoAmITestCase) StatusOK() *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Equal(tc.t, tc.res.StatusCode, http.StatusOK)
+	}
+	return tc
+}
+
+// StatusCode asserts no error and a status code.
+func (tc *WhoAmITestCase) StatusCode(statusCode int) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Equal(tc.t, tc.res.StatusCode, statusCode)
+	}
+	return tc
+}
+
+// BodyContains asserts no error and that the response body contains the string or byte array value.
+func (tc *WhoAmITestCase) BodyContains(value any) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		switch v := value.(type) {
+		case []byte:
+			testarossa.True(tc.t, bytes.Contains(body, v), "%v does not contain %v", body, v)
+		case string:
+			testarossa.Contains(tc.t, string(body), v)
+		default:
+			vv := fmt.Sprintf("%v", v)
+			testarossa.Contains(tc.t, string(body), vv)
+		}
+	}
+	return tc
+}
+
+// BodyNotContains asserts no error and that the response body does not contain the string or byte array value.
+func (tc *WhoAmITestCase) BodyNotContains(value any) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		switch v := value.(type) {
+		case []byte:
+			testarossa.False(tc.t, bytes.Contains(body, v), "%v contains %v", body, v)
+		case string:
+			testarossa.NotContains(tc.t, string(body), v)
+		default:
+			vv := fmt.Sprintf("%v", v)
+			testarossa.NotContains(tc.t, string(body), vv)
+		}
+	}
+	return tc
+}
+
+// HeaderContains asserts no error and that the named header contains the value.
+func (tc *WhoAmITestCase) HeaderContains(headerName string, value string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Contains(tc.t, tc.res.Header.Get(headerName), value)
+	}
+	return tc
+}
+
+// HeaderNotContains asserts no error and that the named header does not contain a string.
+func (tc *WhoAmITestCase) HeaderNotContains(headerName string, value string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.NotContains(tc.t, tc.res.Header.Get(headerName), value)
+	}
+	return tc
+}
+
+// HeaderEqual asserts no error and that the named header matches the value.
+func (tc *WhoAmITestCase) HeaderEqual(headerName string, value string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Equal(tc.t, value, tc.res.Header.Get(headerName))
+	}
+	return tc
+}
+
+// HeaderNotEqual asserts no error and that the named header does not matche the value.
+func (tc *WhoAmITestCase) HeaderNotEqual(headerName string, value string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.NotEqual(tc.t, value, tc.res.Header.Get(headerName))
+	}
+	return tc
+}
+
+// HeaderExists asserts no error and that the named header exists.
+func (tc *WhoAmITestCase) HeaderExists(headerName string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.NotEqual(tc.t, 0, len(tc.res.Header.Values(headerName)), "Header %s does not exist", headerName)
+	}
+	return tc
+}
+
+// HeaderNotExists asserts no error and that the named header does not exists.
+func (tc *WhoAmITestCase) HeaderNotExists(headerName string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Equal(tc.t, 0, len(tc.res.Header.Values(headerName)), "Header %s exists", headerName)
+	}
+	return tc
+}
+
+// ContentType asserts no error and that the Content-Type header matches the expected value.
+func (tc *WhoAmITestCase) ContentType(expected string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		testarossa.Equal(tc.t, expected, tc.res.Header.Get("Content-Type"))
+	}
+	return tc
+}
+
+/*
+TagExists asserts no error and that the at least one tag matches the CSS selector query.
+
+Examples:
+
+	TagExists(`TR > TD > A.expandable[href]`)
+	TagExists(`DIV#main_panel`)
+	TagExists(`TR TD INPUT[name="x"]`)
+*/
+func (tc *WhoAmITestCase) TagExists(cssSelectorQuery string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		testarossa.NotEqual(tc.t, 0, len(matches), "Found no tags matching %s", cssSelectorQuery)
+	}
+	return tc
+}
+
+/*
+TagNotExists asserts no error and that the no tag matches the CSS selector query.
+
+Example:
+
+	TagNotExists(`TR > TD > A.expandable[href]`)
+	TagNotExists(`DIV#main_panel`)
+	TagNotExists(`TR TD INPUT[name="x"]`)
+*/
+func (tc *WhoAmITestCase) TagNotExists(cssSelectorQuery string) *WhoAmITestCase {
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		testarossa.Equal(tc.t, 0, len(matches), "Found %d tag(s) matching %s", len(matches), cssSelectorQuery)
+	}
+	return tc
+}
+
+/*
+TagEqual asserts no error and that the at least one of the tags matching the CSS selector query
+either contains the exact text itself or has a descendant that does.
+
+Example:
+
+	TagEqual("TR > TD > A.expandable[href]", "Expand")
+	TagEqual("DIV#main_panel > SELECT > OPTION", "Red")
+*/
+func (tc *WhoAmITestCase) TagEqual(cssSelectorQuery string, value string) *WhoAmITestCase {
+	var textMatches func(n *html.Node) bool
+	textMatches = func(n *html.Node) bool {
+		for x := n.FirstChild; x != nil; x = x.NextSibling {
+			if x.Data == value || textMatches(x) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		if !testarossa.NotEqual(tc.t, 0, len(matches), "Selector %s does not match any tags", cssSelectorQuery) {
+			return tc
+		}
+		if value == "" {
+			return tc
+		}
+		found := false
+		for _, match := range matches {
+			if textMatches(match) {
+				found = true
+				break
+			}
+		}
+		testarossa.True(tc.t, found, "No tag matching %s contains %s", cssSelectorQuery, value)
+	}
+	return tc
+}
+
+/*
+TagContains asserts no error and that the at least one of the tags matching the CSS selector query
+either contains the text itself or has a descendant that does.
+
+Example:
+
+	TagContains("TR > TD > A.expandable[href]", "Expand")
+	TagContains("DIV#main_panel > SELECT > OPTION", "Red")
+*/
+func (tc *WhoAmITestCase) TagContains(cssSelectorQuery string, value string) *WhoAmITestCase {
+	var textMatches func(n *html.Node) bool
+	textMatches = func(n *html.Node) bool {
+		for x := n.FirstChild; x != nil; x = x.NextSibling {
+			if strings.Contains(x.Data, value) || textMatches(x) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		if !testarossa.NotEqual(tc.t, 0, len(matches), "Selector %s does not match any tags", cssSelectorQuery) {
+			return tc
+		}
+		if value == "" {
+			return tc
+		}
+		found := false
+		for _, match := range matches {
+			if textMatches(match) {
+				found = true
+				break
+			}
+		}
+		testarossa.True(tc.t, found, "No tag matching %s contains %s", cssSelectorQuery, value)
+	}
+	return tc
+}
+
+/*
+TagNotEqual asserts no error and that there is no tag matching the CSS selector that
+either contains the exact text itself or has a descendant that does.
+
+Example:
+
+	TagNotEqual("TR > TD > A[href]", "Harry Potter")
+	TagNotEqual("DIV#main_panel > SELECT > OPTION", "Red")
+*/
+func (tc *WhoAmITestCase) TagNotEqual(cssSelectorQuery string, value string) *WhoAmITestCase {
+	var textMatches func(n *html.Node) bool
+	textMatches = func(n *html.Node) bool {
+		for x := n.FirstChild; x != nil; x = x.NextSibling {
+			if x.Data == value || textMatches(x) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		if len(matches) == 0 {
+			return tc
+		}
+		if !testarossa.NotEqual(tc.t, "", value, "Found tag matching %s", cssSelectorQuery) {
+			return tc
+		}
+		found := false
+		for _, match := range matches {
+			if textMatches(match) {
+				found = true
+				break
+			}
+		}
+		testarossa.False(tc.t, found, "Found tag matching %s that contains %s", cssSelectorQuery, value)
+	}
+	return tc
+}
+
+/*
+TagNotContains asserts no error and that there is no tag matching the CSS selector that
+either contains the text itself or has a descendant that does.
+
+Example:
+
+	TagNotContains("TR > TD > A[href]", "Harry Potter")
+	TagNotContains("DIV#main_panel > SELECT > OPTION", "Red")
+*/
+func (tc *WhoAmITestCase) TagNotContains(cssSelectorQuery string, value string) *WhoAmITestCase {
+	var textMatches func(n *html.Node) bool
+	textMatches = func(n *html.Node) bool {
+		for x := n.FirstChild; x != nil; x = x.NextSibling {
+			if strings.Contains(x.Data, value) || textMatches(x) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if testarossa.NoError(tc.t, tc.err) {
+		selector, err := cascadia.Compile(cssSelectorQuery)
+		if !testarossa.NoError(tc.t, err, "Invalid selector %s", cssSelectorQuery) {
+			return tc
+		}
+		var body []byte
+		if br, ok := tc.res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(tc.res.Body)
+			if !testarossa.NoError(tc.t, err, "Failed to read body") {
+				return tc
+			}
+			tc.res.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if !testarossa.NoError(tc.t, err, "Failed to parse HTML") {
+			return tc
+		}
+		matches := selector.MatchAll(doc)
+		if len(matches) == 0 {
+			return tc
+		}
+		if !testarossa.NotEqual(tc.t, "", value, "Found tag matching %s", cssSelectorQuery) {
+			return tc
+		}
+		found := false
+		for _, match := range matches {
+			if textMatches(match) {
+				found = true
+				break
+			}
+		}
+		testarossa.False(tc.t, found, "Found tag matching %s that contains %s", cssSelectorQuery, value)
+	}
+	return tc
+}
+
+// Error asserts an error.
+func (tc *WhoAmITestCase) Error(errContains string) *WhoAmITestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Contains(tc.t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *WhoAmITestCase) ErrorCode(statusCode int) *WhoAmITestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Equal(tc.t, statusCode, errors.Convert(tc.err).StatusCode)
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *WhoAmITestCase) NoError() *WhoAmITestCase {
+	testarossa.NoError(tc.t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *WhoAmITestCase) CompletedIn(threshold time.Duration) *WhoAmITestCase {
+	testarossa.True(tc.t, tc.dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *WhoAmITestCase) Assert(asserter func(t *testing.T, res *http.Response, err error)) *WhoAmITestCase {
+	asserter(tc.t, tc.res, tc.err)
+	return tc
+}
+
+// Get returns the result of executing WhoAmI.
+func (tc *WhoAmITestCase) Get() (res *http.Response, err error) {
+	return tc.res, tc.err
+}
+/*
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a URL is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func WhoAmI(t *testing.T, ctx context.Context, url string) *WhoAmITestCase {
+	tc := &WhoAmITestCase{t: t}
+	var err error
+	url, err = httpx.ResolveURL(testerapi.URLOfWhoAmI, url)
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	r, err := http.NewRequest(`GET`, url, nil)
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	ctx = frame.CloneContext(ctx)
+	r = r.WithContext(ctx)
+	r.Header = frame.Of(ctx).Header()
+	w := httpx.NewResponseRecorder()
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		return Svc.WhoAmI(w, r)
+	})
+	tc.dur = time.Since(t0)
+	tc.res = w.Result()
+	return tc
+}
+
+/*
+WhoAmI_Do performs a customized request to the WhoAmI endpoint.
+
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a request is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func WhoAmI_Do(t *testing.T, r *http.Request) *WhoAmITestCase {
+	tc := &WhoAmITestCase{t: t}
+	var err error
+	if r == nil {
+		r, err = http.NewRequest(`GET`, "", nil)
+		if err != nil {
+			tc.err = errors.Trace(err)
+			return tc
+		}
+	}
+	if r.Method != `GET` {
+		tc.err = errors.Newc(http.StatusNotFound, "")
+		return tc
+	}
+	url, err := httpx.ResolveURL(testerapi.URLOfWhoAmI, r.URL.String())
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	r.URL, err = httpx.ParseURL(url)
+	if err != nil {
+		tc.err = errors.Trace(err)
+		return tc
+	}
+	for k, vv := range frame.Of(r.Context()).Header() {
+		r.Header[k] = vv
+	}
+	ctx := frame.ContextWithFrameOf(r.Context(), r.Header)
+	r = r.WithContext(ctx)
+	w := httpx.NewResponseRecorder()
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		return Svc.WhoAmI(w, r)
+	})
+	tc.res = w.Result()
+	tc.dur = time.Since(t0)
+	return tc
+}
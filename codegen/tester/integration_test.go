@@ -29,7 +29,10 @@ import (
 
 	"github.com/microbus-io/testarossa"
 
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/pub"
 
 	"github.com/microbus-io/fabric/codegen/tester/testerapi"
@@ -43,7 +46,10 @@ var (
 func Initialize() (err error) {
 	// Add microservices to the testing app
 	err = App.AddAndStartup(
-		Svc,
+		Svc.Init(func(svc *Service) {
+			svc.SetConfig("ActorKeys", "tester")
+			svc.SetConfig("PayloadKeys", "tester")
+		}),
 	)
 	if err != nil {
 		return err
@@ -229,6 +235,24 @@ func TestTester_ShiftPoint(t *testing.T) {
 		testarossa.Equal(t, 15.0, out.Shifted.X)
 		testarossa.Equal(t, 16.0, out.Shifted.Y)
 	}
+	res, err = Svc.Request(ctx,
+		pub.POST("https://"+Hostname+"/shift-point?x=10&y=10"),
+		pub.CBORBody(testerapi.ShiftPointIn{
+			P: &testerapi.XYCoord{
+				X: 5,
+				Y: 6,
+			},
+		}),
+		pub.Header("Accept", "application/cbor"),
+	)
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "application/cbor", res.Header.Get("Content-Type"))
+		var out testerapi.ShiftPointOut
+		err = httpx.ParseResponseBody(res, &out)
+		testarossa.NoError(t, err)
+		testarossa.Equal(t, 15.0, out.Shifted.X)
+		testarossa.Equal(t, 16.0, out.Shifted.Y)
+	}
 
 	// --- OpenAPI ---
 	basePath := "paths|/" + Hostname + ":443/shift-point|post|"
@@ -588,6 +612,19 @@ func TestTester_Echo(t *testing.T) {
 	}
 }
 
+func TestTester_MarkEcho(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context()
+
+	// The middleware applies to requests to the Echo web handler
+	res, err := Svc.Request(ctx, pub.GET("https://"+Hostname+"/echo"))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Contains(t, string(body), "Echo-Marked: true")
+	}
+}
+
 func TestTester_MultiValueHeaders(t *testing.T) {
 	t.Parallel()
 	/*
@@ -1047,3 +1084,95 @@ func TestTester_WhatTimeIsIt(t *testing.T) {
 	testarossa.Equal(t, "string", openAPIValue(schemaRef+"properties|t|type"))
 	testarossa.Equal(t, "date-time", openAPIValue(schemaRef+"properties|t|format"))
 }
+
+func TestTester_WhoAmI(t *testing.T) {
+	t.Parallel()
+	/*
+		ctx := Context()
+		WhoAmI(t, ctx, "").BodyContains(value)
+		httpReq, _ := http.NewRequestWithContext(ctx, method, "?arg=val", body)
+		WhoAmI_Do(t, httpReq).BodyContains(value)
+	*/
+
+	ctx := Context()
+	admin, err := Svc.SignActor(frame.Actor{"sub": "dumbledore", "roles": []string{"admin"}})
+	testarossa.NoError(t, err)
+	student, err := Svc.SignActor(frame.Actor{"sub": "harry", "roles": []string{"student"}})
+	testarossa.NoError(t, err)
+
+	// The requirement is enforced when requests arrive over the bus
+	res, err := Svc.Request(ctx, pub.GET("https://"+Hostname+"/who-am-i"), pub.Actor(admin))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "dumbledore", string(body))
+	}
+	_, err = Svc.Request(ctx, pub.GET("https://"+Hostname+"/who-am-i"), pub.Actor(student))
+	testarossa.Equal(t, http.StatusForbidden, errors.StatusCode(err))
+	_, err = Svc.Request(ctx, pub.GET("https://"+Hostname+"/who-am-i"))
+	testarossa.Equal(t, http.StatusUnauthorized, errors.StatusCode(err))
+
+	// The handler reads the actor from the frame once it is verified
+	frame.Of(ctx).SetActorToken(admin)
+	WhoAmI(t, ctx, "").BodyNotContains("dumbledore")
+	frame.Of(ctx).SetActorVerified(true)
+	WhoAmI(t, ctx, "").BodyContains("dumbledore")
+}
+
+func TestTester_Whisper(t *testing.T) {
+	t.Parallel()
+	/*
+		ctx := Context()
+		Whisper(t, ctx, secret).
+			Expect(echo)
+	*/
+
+	ctx := Context()
+	Whisper(t, ctx, "hush").Expect("hush")
+
+	// The request is rejected unless its body is encrypted
+	client := testerapi.NewClient(Svc)
+	echo, err := client.Whisper(ctx, "hush")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "hush", echo)
+	}
+	_, err = Svc.Request(ctx, pub.POST("https://"+Hostname+"/whisper"), pub.Body(`{"secret":"hush"}`))
+	testarossa.Equal(t, http.StatusBadRequest, errors.StatusCode(err))
+}
+
+func TestTester_OnlyFriends(t *testing.T) {
+	t.Parallel()
+	/*
+		ctx := Context()
+		OnlyFriends(t, ctx).
+			Expect(ok)
+	*/
+
+	ctx := Context()
+	OnlyFriends(t, ctx).Expect(true)
+
+	// Callers are checked when requests arrive over the bus
+	ok, err := testerapi.NewClient(Svc).OnlyFriends(ctx)
+	if testarossa.NoError(t, err) {
+		testarossa.True(t, ok)
+	}
+	friend := connector.New("best.friends.codegen.test")
+	App.Add(friend)
+	err = friend.Startup()
+	testarossa.NoError(t, err)
+	defer friend.Shutdown()
+	ok, err = testerapi.NewClient(friend).OnlyFriends(ctx)
+	if testarossa.NoError(t, err) {
+		testarossa.True(t, ok)
+	}
+	stranger := connector.New("stranger.codegen.test")
+	App.Add(stranger)
+	err = stranger.Startup()
+	testarossa.NoError(t, err)
+	defer stranger.Shutdown()
+	_, err = testerapi.NewClient(stranger).OnlyFriends(ctx)
+	testarossa.Equal(t, http.StatusForbidden, errors.StatusCode(err))
+
+	// --- OpenAPI ---
+	basePath := "paths|/" + Hostname + ":443/only-friends|post|"
+	testarossa.Equal(t, []any{"codegen.test", "*.friends.codegen.test"}, openAPIValue(basePath+"x-microbus-allowed-callers"))
+}
@@ -88,6 +88,8 @@ type ToDo interface {
 	UnnamedFunctionPathArguments(ctx context.Context, path1 string, path2 string, path3 string) (joined string, err error)
 	PathArgumentsPriority(ctx context.Context, foo string) (echo string, err error)
 	WhatTimeIsIt(ctx context.Context) (t time.Time, err error)
+	Whisper(ctx context.Context, secret string) (echo string, err error)
+	OnlyFriends(ctx context.Context) (ok bool, err error)
 	OnDiscoveredSink(ctx context.Context, p testerapi.XYCoord, n int) (q testerapi.XYCoord, m int, err error)
 	Echo(w http.ResponseWriter, r *http.Request) (err error)
 	MultiValueHeaders(w http.ResponseWriter, r *http.Request) (err error)
@@ -95,6 +97,8 @@ type ToDo interface {
 	UnnamedWebPathArguments(w http.ResponseWriter, r *http.Request) (err error)
 	DirectoryServer(w http.ResponseWriter, r *http.Request) (err error)
 	Hello(w http.ResponseWriter, r *http.Request) (err error)
+	WhoAmI(w http.ResponseWriter, r *http.Request) (err error)
+	MarkEcho(next connector.HTTPHandler) (wrapped connector.HTTPHandler)
 }
 
 // Intermediate extends and customizes the generic base connector.
@@ -126,24 +130,30 @@ func NewService(impl ToDo, version int) *Intermediate {
 	svc.Subscribe(`ANY`, `:443/shift-point`, svc.doShiftPoint)
 	svc.Subscribe(`ANY`, `:443/lines-intersection`, svc.doLinesIntersection)
 	svc.Subscribe(`ANY`, `:443/sub-array-range/{max}`, svc.doSubArrayRange)
-	svc.Subscribe(`ANY`, `:443/sum-two-integers`, svc.doSumTwoIntegers)
+	svc.Subscribe(`ANY`, `:443/sum-two-integers`, svc.doSumTwoIntegers, sub.MaxConcurrency(8), sub.MaxPending(64))
 	svc.Subscribe(`GET`, `:443/function-path-arguments/fixed/{named}/{}/{suffix+}`, svc.doFunctionPathArguments)
 	svc.Subscribe(`GET`, `:443/non-string-path-arguments/fixed/{named}/{}/{suffix+}`, svc.doNonStringPathArguments)
 	svc.Subscribe(`GET`, `:443/unnamed-function-path-arguments/{}/foo/{}/bar/{+}`, svc.doUnnamedFunctionPathArguments)
 	svc.Subscribe(`ANY`, `:443/path-arguments-priority/{foo}`, svc.doPathArgumentsPriority)
 	svc.Subscribe(`ANY`, `:443/what-time-is-it`, svc.doWhatTimeIsIt)
+	svc.Subscribe(`ANY`, `:443/whisper`, svc.doWhisper, sub.Encrypted())
+	svc.Subscribe(`ANY`, `:443/only-friends`, svc.doOnlyFriends, sub.AllowCallers(`codegen.test`, `*.friends.codegen.test`))
 
 	// Webs
-	svc.Subscribe(`ANY`, `:443/echo`, svc.impl.Echo)
+	svc.Subscribe(`ANY`, `:443/echo`, svc.impl.Echo, sub.MaxConcurrency(16))
 	svc.Subscribe(`ANY`, `:443/multi-value-headers`, svc.impl.MultiValueHeaders)
 	svc.Subscribe(`ANY`, `:443/web-path-arguments/fixed/{named}/{}/{suffix+}`, svc.impl.WebPathArguments)
 	svc.Subscribe(`GET`, `:443/unnamed-web-path-arguments/{}/foo/{}/bar/{+}`, svc.impl.UnnamedWebPathArguments)
 	svc.Subscribe(`GET`, `:443/directory-server/{filename+}`, svc.impl.DirectoryServer)
 	svc.Subscribe(`ANY`, `:443/hello`, svc.impl.Hello)
+	svc.Subscribe(`GET`, `:443/who-am-i`, svc.impl.WhoAmI, sub.Actor(`roles.admin || roles.manager`))
 
 	// Sinks
 	testerapi1.NewHook(svc).OnDiscovered(svc.impl.OnDiscoveredSink)
 
+	// Middleware
+	svc.UseSubscriptionMiddleware(`MarkEcho`, sub.OnRoute(`:443/echo`, svc.impl.MarkEcho))
+
 	// Resources file system
 	svc.SetResFS(resources.FS)
 
@@ -354,6 +364,38 @@ An httpResponseBody argument prevents returning additional values, except for th
 			}{},
 		})
 	}
+	if r.URL.Port() == "443" || "443" == "0" {
+		oapiSvc.Endpoints = append(oapiSvc.Endpoints, &openapi.Endpoint{
+			Type:        `function`,
+			Name:        `Whisper`,
+			Method:      `ANY`,
+			Path:        `:443/whisper`,
+			Summary:     `Whisper(secret string) (echo string)`,
+			Description: `Whisper tests a function whose request and response bodies are encrypted.`,
+			InputArgs: struct {
+				Secret string `json:"secret"`
+			}{},
+			OutputArgs: struct {
+				Echo string `json:"echo"`
+			}{},
+		})
+	}
+	if r.URL.Port() == "443" || "443" == "0" {
+		oapiSvc.Endpoints = append(oapiSvc.Endpoints, &openapi.Endpoint{
+			Type:        `function`,
+			Name:        `OnlyFriends`,
+			Method:      `ANY`,
+			Path:        `:443/only-friends`,
+			Summary:     `OnlyFriends() (ok bool)`,
+			Description: `OnlyFriends tests a function that restricts its callers.`,
+			InputArgs: struct {
+			}{},
+			OutputArgs: struct {
+				Ok bool `json:"ok"`
+			}{},
+			AllowedCallers: []string{`codegen.test`, `*.friends.codegen.test`},
+		})
+	}
 	if r.URL.Port() == "443" || "443" == "0" {
 		oapiSvc.Endpoints = append(oapiSvc.Endpoints, &openapi.Endpoint{
 			Type:        `web`,
@@ -438,6 +480,20 @@ An httpResponseBody argument prevents returning additional values, except for th
 			}{},
 		})
 	}
+	if r.URL.Port() == "443" || "443" == "0" {
+		oapiSvc.Endpoints = append(oapiSvc.Endpoints, &openapi.Endpoint{
+			Type:        `web`,
+			Name:        `WhoAmI`,
+			Method:      `GET`,
+			Path:        `:443/who-am-i`,
+			Summary:     `WhoAmI()`,
+			Description: `WhoAmI tests an actor requirement by printing the subject of the actor of the request.`,
+			InputArgs: struct {
+			}{},
+			OutputArgs: struct {
+			}{},
+		})
+	}
 
 	if len(oapiSvc.Endpoints) == 0 {
 		w.WriteHeader(http.StatusNotFound)
@@ -558,6 +614,18 @@ func (svc *Intermediate) doShiftPoint(w http.ResponseWriter, r *http.Request) er
 	if err != nil {
 		return err // No trace
 	}
+	if httpx.AcceptsCBOR(r) {
+		b, err := httpx.MarshalCBOR(o)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w.Header().Set("Content-Type", "application/cbor")
+		_, err = w.Write(b)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
 	w.Header().Set("Content-Type", "application/json")
 	encoder := json.NewEncoder(w)
 	if svc.Deployment() == connector.LOCAL {
@@ -639,6 +707,19 @@ func (svc *Intermediate) doSubArrayRange(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return err // No trace
 	}
+	if httpx.AcceptsCBOR(r) {
+		b, err := httpx.MarshalCBOR(o.HTTPResponseBody)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w.Header().Set("Content-Type", "application/cbor")
+		w.WriteHeader(o.HTTPStatusCode)
+		_, err = w.Write(b)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(o.HTTPStatusCode)
 	encoder := json.NewEncoder(w)
@@ -869,6 +950,91 @@ func (svc *Intermediate) doWhatTimeIsIt(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return err // No trace
 	}
+	if httpx.AcceptsCBOR(r) {
+		b, err := httpx.MarshalCBOR(o)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w.Header().Set("Content-Type", "application/cbor")
+		_, err = w.Write(b)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doWhisper handles marshaling for the Whisper function.
+func (svc *Intermediate) doWhisper(w http.ResponseWriter, r *http.Request) error {
+	var i testerapi.WhisperIn
+	var o testerapi.WhisperOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:443/whisper`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:443/whisper`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Echo, err = svc.impl.Whisper(
+		r.Context(),
+		i.Secret,
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doOnlyFriends handles marshaling for the OnlyFriends function.
+func (svc *Intermediate) doOnlyFriends(w http.ResponseWriter, r *http.Request) error {
+	var i testerapi.OnlyFriendsIn
+	var o testerapi.OnlyFriendsOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:443/only-friends`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:443/only-friends`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Ok, err = svc.impl.OnlyFriends(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
 	w.Header().Set("Content-Type", "application/json")
 	encoder := json.NewEncoder(w)
 	if svc.Deployment() == connector.LOCAL {
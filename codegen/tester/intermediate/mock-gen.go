@@ -51,6 +51,8 @@ type Mock struct {
 	mockUnnamedFunctionPathArguments func(ctx context.Context, path1 string, path2 string, path3 string) (joined string, err error)
 	mockPathArgumentsPriority func(ctx context.Context, foo string) (echo string, err error)
 	mockWhatTimeIsIt func(ctx context.Context) (t time.Time, err error)
+	mockWhisper func(ctx context.Context, secret string) (echo string, err error)
+	mockOnlyFriends func(ctx context.Context) (ok bool, err error)
 	mockOnDiscoveredSink func(ctx context.Context, p testerapi.XYCoord, n int) (q testerapi.XYCoord, m int, err error)
 	mockEcho func(w http.ResponseWriter, r *http.Request) (err error)
 	mockMultiValueHeaders func(w http.ResponseWriter, r *http.Request) (err error)
@@ -58,6 +60,7 @@ type Mock struct {
 	mockUnnamedWebPathArguments func(w http.ResponseWriter, r *http.Request) (err error)
 	mockDirectoryServer func(w http.ResponseWriter, r *http.Request) (err error)
 	mockHello func(w http.ResponseWriter, r *http.Request) (err error)
+	mockWhoAmI func(w http.ResponseWriter, r *http.Request) (err error)
 }
 
 // NewMock creates a new mockable version of the microservice.
@@ -245,6 +248,36 @@ func (svc *Mock) WhatTimeIsIt(ctx context.Context) (t time.Time, err error) {
 	return svc.mockWhatTimeIsIt(ctx)
 }
 
+// MockWhisper sets up a mock handler for the Whisper endpoint.
+func (svc *Mock) MockWhisper(handler func(ctx context.Context, secret string) (echo string, err error)) *Mock {
+	svc.mockWhisper = handler
+	return svc
+}
+
+// Whisper runs the mock handler set by MockWhisper.
+func (svc *Mock) Whisper(ctx context.Context, secret string) (echo string, err error) {
+	if svc.mockWhisper == nil {
+		err = errors.New("mocked endpoint 'Whisper' not implemented")
+		return
+	}
+	return svc.mockWhisper(ctx, secret)
+}
+
+// MockOnlyFriends sets up a mock handler for the OnlyFriends endpoint.
+func (svc *Mock) MockOnlyFriends(handler func(ctx context.Context) (ok bool, err error)) *Mock {
+	svc.mockOnlyFriends = handler
+	return svc
+}
+
+// OnlyFriends runs the mock handler set by MockOnlyFriends.
+func (svc *Mock) OnlyFriends(ctx context.Context) (ok bool, err error) {
+	if svc.mockOnlyFriends == nil {
+		err = errors.New("mocked endpoint 'OnlyFriends' not implemented")
+		return
+	}
+	return svc.mockOnlyFriends(ctx)
+}
+
 // MockOnDiscoveredSink sets up a mock handler for the OnDiscoveredSink endpoint.
 func (svc *Mock) MockOnDiscoveredSink(handler func(ctx context.Context, p testerapi.XYCoord, n int) (q testerapi.XYCoord, m int, err error)) *Mock {
 	svc.mockOnDiscoveredSink = handler
@@ -349,3 +382,23 @@ func (svc *Mock) Hello(w http.ResponseWriter, r *http.Request) (err error) {
 	err = svc.mockHello(w, r)
 	return errors.Trace(err)
 }
+
+// MockWhoAmI sets up a mock handler for the WhoAmI endpoint.
+func (svc *Mock) MockWhoAmI(handler func(w http.ResponseWriter, r *http.Request) (err error)) *Mock {
+	svc.mockWhoAmI = handler
+	return svc
+}
+
+// WhoAmI runs the mock handler set by MockWhoAmI.
+func (svc *Mock) WhoAmI(w http.ResponseWriter, r *http.Request) (err error) {
+	if svc.mockWhoAmI == nil {
+		return errors.New("mocked endpoint 'WhoAmI' not implemented")
+	}
+	err = svc.mockWhoAmI(w, r)
+	return errors.Trace(err)
+}
+
+// MarkEcho is a pass-through middleware.
+func (svc *Mock) MarkEcho(next connector.HTTPHandler) (wrapped connector.HTTPHandler) {
+	return next
+}
@@ -294,3 +294,35 @@ WhatTimeIsIt tests shifting the clock.
 func (svc *Service) WhatTimeIsIt(ctx context.Context) (t time.Time, err error) {
 	return svc.Now(ctx), nil
 }
+
+/*
+MarkEcho tests a middleware that applies only to the Echo web handler.
+*/
+func (svc *Service) MarkEcho(next connector.HTTPHandler) (wrapped connector.HTTPHandler) {
+	return func(w http.ResponseWriter, r *http.Request) (err error) {
+		r.Header.Set("Echo-Marked", "true")
+		return next(w, r) // No trace
+	}
+}
+
+/*
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+*/
+func (svc *Service) WhoAmI(w http.ResponseWriter, r *http.Request) (err error) {
+	w.Write([]byte(frame.Of(r).Actor().Subject()))
+	return nil
+}
+
+/*
+Whisper tests a function whose request and response bodies are encrypted.
+*/
+func (svc *Service) Whisper(ctx context.Context, secret string) (echo string, err error) {
+	return secret, nil
+}
+
+/*
+OnlyFriends tests a function that restricts its callers.
+*/
+func (svc *Service) OnlyFriends(ctx context.Context) (ok bool, err error) {
+	return true, nil
+}
@@ -70,12 +70,15 @@ var (
 	URLOfUnnamedFunctionPathArguments = httpx.JoinHostAndPath(Hostname, `:443/unnamed-function-path-arguments/{}/foo/{}/bar/{+}`)
 	URLOfPathArgumentsPriority = httpx.JoinHostAndPath(Hostname, `:443/path-arguments-priority/{foo}`)
 	URLOfWhatTimeIsIt = httpx.JoinHostAndPath(Hostname, `:443/what-time-is-it`)
+	URLOfWhisper = httpx.JoinHostAndPath(Hostname, `:443/whisper`)
+	URLOfOnlyFriends = httpx.JoinHostAndPath(Hostname, `:443/only-friends`)
 	URLOfEcho = httpx.JoinHostAndPath(Hostname, `:443/echo`)
 	URLOfMultiValueHeaders = httpx.JoinHostAndPath(Hostname, `:443/multi-value-headers`)
 	URLOfWebPathArguments = httpx.JoinHostAndPath(Hostname, `:443/web-path-arguments/fixed/{named}/{}/{suffix+}`)
 	URLOfUnnamedWebPathArguments = httpx.JoinHostAndPath(Hostname, `:443/unnamed-web-path-arguments/{}/foo/{}/bar/{+}`)
 	URLOfDirectoryServer = httpx.JoinHostAndPath(Hostname, `:443/directory-server/{filename+}`)
 	URLOfHello = httpx.JoinHostAndPath(Hostname, `:443/hello`)
+	URLOfWhoAmI = httpx.JoinHostAndPath(Hostname, `:443/who-am-i`)
 )
 
 // Client is an interface to calling the endpoints of the codegen.test microservice.
@@ -940,6 +943,106 @@ func (_c *MulticastClient) Hello(ctx context.Context, r *http.Request) <-chan *p
 	return _c.svc.Publish(ctx, pub.Method(r.Method), pub.URL(url), pub.CopyHeaders(r.Header), pub.Body(r.Body))
 }
 
+/*
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a URL is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func (_c *Client) WhoAmI(ctx context.Context, url string) (res *http.Response, err error) {
+	url, err = httpx.ResolveURL(URLOfWhoAmI, url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res, err = _c.svc.Request(ctx, pub.Method(`GET`), pub.URL(url))
+	if err != nil {
+		return nil, err // No trace
+	}
+	return res, err
+}
+
+/*
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a URL is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func (_c *MulticastClient) WhoAmI(ctx context.Context, url string) <-chan *pub.Response {
+	var err error
+	url, err = httpx.ResolveURL(URLOfWhoAmI, url)
+	if err != nil {
+		return _c.errChan(errors.Trace(err))
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		return _c.errChan(errors.Trace(err))
+	}
+	return _c.svc.Publish(ctx, pub.Method(`GET`), pub.URL(url))
+}
+
+/*
+WhoAmI_Do performs a customized request to the WhoAmI endpoint.
+
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a request is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func (_c *Client) WhoAmI_Do(r *http.Request) (res *http.Response, err error) {
+	if r == nil {
+		r, err = http.NewRequest(`GET`, "", nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if r.Method != `GET` {
+		return nil, errors.Newc(http.StatusNotFound, "")
+	}
+	url, err := httpx.ResolveURL(URLOfWhoAmI, r.URL.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res, err = _c.svc.Request(r.Context(), pub.Method(r.Method), pub.URL(url), pub.CopyHeaders(r.Header), pub.Body(r.Body))
+	if err != nil {
+		return nil, err // No trace
+	}
+	return res, err
+}
+
+/*
+WhoAmI_Do performs a customized request to the WhoAmI endpoint.
+
+WhoAmI tests an actor requirement by printing the subject of the actor of the request.
+
+If a request is not provided, it defaults to the URL of the endpoint. Otherwise, it is resolved relative to the URL of the endpoint.
+*/
+func (_c *MulticastClient) WhoAmI_Do(ctx context.Context, r *http.Request) <-chan *pub.Response {
+	var err error
+	if r == nil {
+		r, err = http.NewRequest(`GET`, "", nil)
+		if err != nil {
+			return _c.errChan(errors.Trace(err))
+		}
+	}
+	if r.Method != `GET` {
+		return _c.errChan(errors.Newc(http.StatusNotFound, ""))
+	}
+	url, err := httpx.ResolveURL(URLOfWhoAmI, r.URL.String())
+	if err != nil {
+		return _c.errChan(errors.Trace(err))
+	}
+	url, err = httpx.FillPathArguments(url)
+	if err != nil {
+		return _c.errChan(errors.Trace(err))
+	}
+	return _c.svc.Publish(ctx, pub.Method(r.Method), pub.URL(url), pub.CopyHeaders(r.Header), pub.Body(r.Body))
+}
+
 // StringCutIn are the input arguments of StringCut.
 type StringCutIn struct {
 	S string `json:"s"`
@@ -1212,7 +1315,8 @@ func (_c *MulticastClient) ShiftPoint(ctx context.Context, p *XYCoord, x float64
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 
 	_res := make(chan *ShiftPointResponse, cap(_ch))
@@ -1223,7 +1327,7 @@ func (_c *MulticastClient) ShiftPoint(ctx context.Context, p *XYCoord, x float64
 		if _err != nil {
 			_r.err = _err // No trace
 		} else {
-			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			_err = httpx.ParseResponseBody(_httpRes, &(_r.data))
 			if _err != nil {
 				_r.err = errors.Trace(_err)
 			}
@@ -1257,14 +1361,15 @@ func (_c *Client) ShiftPoint(ctx context.Context, p *XYCoord, x float64, y float
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 	if _err != nil {
 		err = _err // No trace
 		return
 	}
 	var _out ShiftPointOut
-	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	_err = httpx.ParseResponseBody(_httpRes, &_out)
 	if _err != nil {
 		err = errors.Trace(_err)
 		return
@@ -1434,7 +1539,8 @@ func (_c *MulticastClient) SubArrayRange(ctx context.Context, httpRequestBody []
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 
 	_res := make(chan *SubArrayRangeResponse, cap(_ch))
@@ -1445,7 +1551,7 @@ func (_c *MulticastClient) SubArrayRange(ctx context.Context, httpRequestBody []
 		if _err != nil {
 			_r.err = _err // No trace
 		} else {
-			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data.HTTPResponseBody))
+			_err = httpx.ParseResponseBody(_httpRes, &(_r.data.HTTPResponseBody))
 			if _err != nil {
 				_r.err = errors.Trace(_err)
 			}
@@ -1485,14 +1591,15 @@ func (_c *Client) SubArrayRange(ctx context.Context, httpRequestBody []int, min
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 	if _err != nil {
 		err = _err // No trace
 		return
 	}
 	var _out SubArrayRangeOut
-	_err = json.NewDecoder(_httpRes.Body).Decode(&_out.HTTPResponseBody)
+	_err = httpx.ParseResponseBody(_httpRes, &_out.HTTPResponseBody)
 	if _err != nil {
 		err = errors.Trace(_err)
 		return
@@ -2107,7 +2214,8 @@ func (_c *MulticastClient) WhatTimeIsIt(ctx context.Context) <-chan *WhatTimeIsI
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 
 	_res := make(chan *WhatTimeIsItResponse, cap(_ch))
@@ -2118,7 +2226,7 @@ func (_c *MulticastClient) WhatTimeIsIt(ctx context.Context) <-chan *WhatTimeIsI
 		if _err != nil {
 			_r.err = _err // No trace
 		} else {
-			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			_err = httpx.ParseResponseBody(_httpRes, &(_r.data))
 			if _err != nil {
 				_r.err = errors.Trace(_err)
 			}
@@ -2146,14 +2254,15 @@ func (_c *Client) WhatTimeIsIt(ctx context.Context) (t time.Time, err error) {
 		pub.Method(`POST`),
 		pub.URL(_url),
 		pub.Query(_query),
-		pub.Body(_body),
+		pub.CBORBody(_body),
+		pub.Header("Accept", "application/cbor"),
 	)
 	if _err != nil {
 		err = _err // No trace
 		return
 	}
 	var _out WhatTimeIsItOut
-	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	_err = httpx.ParseResponseBody(_httpRes, &_out)
 	if _err != nil {
 		err = errors.Trace(_err)
 		return
@@ -2162,6 +2271,201 @@ func (_c *Client) WhatTimeIsIt(ctx context.Context) (t time.Time, err error) {
 	return
 }
 
+// WhisperIn are the input arguments of Whisper.
+type WhisperIn struct {
+	Secret string `json:"secret"`
+}
+
+// WhisperOut are the return values of Whisper.
+type WhisperOut struct {
+	Echo string `json:"echo"`
+}
+
+// WhisperResponse is the response to Whisper.
+type WhisperResponse struct {
+	data WhisperOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *WhisperResponse) Get() (echo string, err error) {
+	echo = _out.data.Echo
+	err = _out.err
+	return
+}
+
+/*
+Whisper tests a function whose request and response bodies are encrypted.
+*/
+func (_c *MulticastClient) Whisper(ctx context.Context, secret string) <-chan *WhisperResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:443/whisper`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`secret`: secret,
+	})
+	_in := WhisperIn{
+		secret,
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+		pub.Encrypted(),
+	)
+
+	_res := make(chan *WhisperResponse, cap(_ch))
+	for _i := range _ch {
+		var _r WhisperResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Whisper tests a function whose request and response bodies are encrypted.
+*/
+func (_c *Client) Whisper(ctx context.Context, secret string) (echo string, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:443/whisper`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`secret`: secret,
+	})
+	_in := WhisperIn{
+		secret,
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+		pub.Encrypted(),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out WhisperOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	echo = _out.Echo
+	return
+}
+
+// OnlyFriendsIn are the input arguments of OnlyFriends.
+type OnlyFriendsIn struct {
+}
+
+// OnlyFriendsOut are the return values of OnlyFriends.
+type OnlyFriendsOut struct {
+	Ok bool `json:"ok"`
+}
+
+// OnlyFriendsResponse is the response to OnlyFriends.
+type OnlyFriendsResponse struct {
+	data OnlyFriendsOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *OnlyFriendsResponse) Get() (ok bool, err error) {
+	ok = _out.data.Ok
+	err = _out.err
+	return
+}
+
+/*
+OnlyFriends tests a function that restricts its callers.
+*/
+func (_c *MulticastClient) OnlyFriends(ctx context.Context) <-chan *OnlyFriendsResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:443/only-friends`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := OnlyFriendsIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *OnlyFriendsResponse, cap(_ch))
+	for _i := range _ch {
+		var _r OnlyFriendsResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+OnlyFriends tests a function that restricts its callers.
+*/
+func (_c *Client) OnlyFriends(ctx context.Context) (ok bool, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:443/only-friends`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := OnlyFriendsIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out OnlyFriendsOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	ok = _out.Ok
+	return
+}
+
 // OnDiscoveredIn are the input arguments of OnDiscovered.
 type OnDiscoveredIn struct {
 	P XYCoord `json:"p"`
@@ -2234,7 +2538,7 @@ func (_c *MulticastTrigger) OnDiscovered(ctx context.Context, p XYCoord, n int)
 /*
 OnDiscovered tests firing events.
 */
-func (_c *Hook) OnDiscovered(handler func(ctx context.Context, p XYCoord, n int) (q XYCoord, m int, err error)) error {
+func (_c *Hook) OnDiscovered(handler func(ctx context.Context, p XYCoord, n int) (q XYCoord, m int, err error), options ...sub.Option) error {
 	doOnDiscovered := func(w http.ResponseWriter, r *http.Request) error {
 		var i OnDiscoveredIn
 		var o OnDiscoveredOut
@@ -2262,5 +2566,5 @@ func (_c *Hook) OnDiscovered(handler func(ctx context.Context, p XYCoord, n int)
 	if handler == nil {
 		return _c.svc.Unsubscribe(`POST`, path)
 	}
-	return _c.svc.Subscribe(`POST`, path, doOnDiscovered)
+	return _c.svc.Subscribe(`POST`, path, doOnDiscovered, options...)
 }
@@ -18,12 +18,12 @@ limitations under the License.
 
 package tester
 
-const Version = 111
-const SourceCodeSHA256 = "3a48a6fe81b9920fc47ac26f578a7d140041f6a7a406fbac18a6daa6ae48a0c0"
-const Timestamp = "2024-08-31T21:14:16.890607Z"
+const Version = 125
+const SourceCodeSHA256 = "05224f2131e95cda670ab1be5c386b7ebb05452b683add1587d7ff73684f5eff"
+const Timestamp = "2026-10-17T10:57:23.498086912Z"
 
 /* {
-	"ver": 111,
-	"sha256": "3a48a6fe81b9920fc47ac26f578a7d140041f6a7a406fbac18a6daa6ae48a0c0",
-	"ts": "2024-08-31T21:14:16.890607Z"
+	"ver": 125,
+	"sha256": "05224f2131e95cda670ab1be5c386b7ebb05452b683add1587d7ff73684f5eff",
+	"ts": "2026-10-17T10:57:23.498086912Z"
 } */
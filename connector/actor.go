@@ -0,0 +1,194 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/cfg"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/sub"
+)
+
+const (
+	// actorKeysConfig is the name of the secret config that holds the keys with which actor tokens are signed and validated
+	actorKeysConfig = "ActorKeys"
+	// actorKeysDescription is the description of the ActorKeys config
+	actorKeysDescription = "ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces. The first key signs new tokens. All keys are accepted when validating tokens."
+	// actorTokenMaxAge is the expiration of actor tokens that are signed without an expiration time
+	actorTokenMaxAge = time.Hour
+)
+
+// actorTokenHeader is the encoded header of all actor tokens
+var actorTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// defineKeysConfig defines a secret config that holds keys, unless it is already defined.
+// The config must be defined before the microservice starts.
+func (c *Connector) defineKeysConfig(name string, description string) error {
+	c.configLock.Lock()
+	_, ok := c.configs[strings.ToLower(name)]
+	c.configLock.Unlock()
+	if ok {
+		return nil
+	}
+	if c.IsStarted() {
+		return errors.Newf("config '%s' must be defined before startup", name)
+	}
+	err := c.DefineConfig(name, cfg.Description(description), cfg.Secret())
+	return errors.Trace(err)
+}
+
+// actorKeys returns the keys with which actor tokens are signed and validated.
+func (c *Connector) actorKeys() (keys [][]byte) {
+	return parseKeys(c.Config(actorKeysConfig))
+}
+
+/*
+SignActor signs the claims of an actor with the first of the keys in the ActorKeys config and returns a JWT.
+The token can be attached to outgoing requests using pub.Actor and is propagated to downstream requests.
+The issued-at time is set to the current time and an expiration time is set if one is not provided.
+The ActorKeys config must be defined before the microservice starts, either explicitly or by subscribing with sub.Actor.
+
+	token, err := svc.SignActor(frame.Actor{
+		"sub":   "harry@hogwarts.edu",
+		"roles": []string{"wizard", "seeker"},
+	})
+*/
+func (c *Connector) SignActor(actor frame.Actor) (token string, err error) {
+	keys := c.actorKeys()
+	if len(keys) == 0 {
+		return "", errors.Newf("config '%s' is not set", actorKeysConfig)
+	}
+	now := time.Now()
+	claims := make(map[string]any, len(actor)+2)
+	for k, v := range actor {
+		claims[k] = v
+	}
+	claims["iat"] = now.Unix()
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = now.Add(actorTokenMaxAge).Unix()
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	unsigned := actorTokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signActorToken(unsigned, keys[0]), nil
+}
+
+// VerifyActor validates the signature and the expiration time of an actor token against the keys in the ActorKeys config
+// and returns the claims of the actor.
+// An invalid token results in an error with a 401 status code.
+func (c *Connector) VerifyActor(token string) (actor frame.Actor, err error) {
+	keys := c.Config(actorKeysConfig)
+	if len(parseKeys(keys)) == 0 {
+		return nil, errors.Newcf(http.StatusUnauthorized, "config '%s' is not set", actorKeysConfig)
+	}
+	actor, err = VerifyActorToken(token, keys)
+	return actor, err // No trace
+}
+
+// VerifyActorToken validates the signature and the expiration time of an HS256 JWT against a list of keys
+// separated by commas or spaces, and returns its claims.
+// It is used to validate tokens that are signed by issuers other than the microservices, such as bearer tokens.
+// An invalid token results in an error with a 401 status code.
+func VerifyActorToken(token string, keys string) (actor frame.Actor, err error) {
+	parsedKeys := parseKeys(keys)
+	if len(parsedKeys) == 0 {
+		return nil, errors.Newc(http.StatusUnauthorized, "no keys to validate actor token")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.Newc(http.StatusUnauthorized, "malformed actor token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err == nil {
+		err = json.Unmarshal(headerJSON, &header)
+	}
+	if err != nil {
+		return nil, errors.Newc(http.StatusUnauthorized, "malformed actor token")
+	}
+	if header.Alg != "HS256" {
+		return nil, errors.Newcf(http.StatusUnauthorized, "unsupported actor token algorithm '%s'", header.Alg)
+	}
+	unsigned := parts[0] + "." + parts[1]
+	valid := false
+	for _, key := range parsedKeys {
+		if hmac.Equal([]byte(parts[2]), []byte(signActorToken(unsigned, key))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, errors.Newc(http.StatusUnauthorized, "invalid actor token signature")
+	}
+	f := frame.Of(http.Header{})
+	f.SetActorToken(token)
+	f.SetActorVerified(true)
+	actor = f.Actor()
+	if actor == nil {
+		return nil, errors.Newc(http.StatusUnauthorized, "malformed actor token")
+	}
+	if exp, ok := actor["exp"].(float64); ok && time.Now().Unix() >= int64(exp) {
+		return nil, errors.Newc(http.StatusUnauthorized, "actor token expired")
+	}
+	return actor, nil
+}
+
+// signActorToken returns the base64url-encoded HMAC-SHA256 signature of the unsigned part of a JWT.
+func signActorToken(unsigned string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyRequestActor validates the actor token of an incoming request, if the ActorKeys config is set,
+// and checks that the actor satisfies the requirement of the subscription, if any.
+// The claims of the actor are exposed to the handler only if the token is validated.
+// A token that cannot be validated is still propagated to downstream requests.
+func (c *Connector) verifyRequestActor(r *http.Request, s *sub.Subscription) error {
+	frame.Of(r).SetActorVerified(false)
+	token := frame.Of(r).ActorToken()
+	if token == "" {
+		if s.Actor != nil {
+			return errors.Newc(http.StatusUnauthorized, "actor required")
+		}
+		return nil
+	}
+	if s.Actor == nil && len(c.actorKeys()) == 0 {
+		return nil // Nothing to validate against
+	}
+	actor, err := c.VerifyActor(token)
+	if err != nil {
+		return err // No trace
+	}
+	frame.Of(r).SetActorVerified(true)
+	if s.Actor != nil && !s.Actor.SatisfiedBy(actor) {
+		return errors.Newcf(http.StatusForbidden, "actor does not satisfy '%s'", s.Actor.String())
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Actor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.actor.connector")
+	alpha.SetDeployment(TESTING)
+	alpha.SetPlane(plane)
+	alpha.Subscribe("GET", "admin", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(frame.Of(r).Actor().Subject()))
+		return nil
+	}, sub.Actor("roles.admin"))
+	alpha.Subscribe("GET", "whoami", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(frame.Of(r).Actor().Subject()))
+		return nil
+	})
+	err := alpha.SetConfig("ActorKeys", "key1, key2")
+	testarossa.NoError(t, err)
+
+	beta := New("beta.actor.connector")
+	beta.SetDeployment(TESTING)
+	beta.SetPlane(plane)
+	beta.Subscribe("GET", "forward", func(w http.ResponseWriter, r *http.Request) error {
+		res, err := beta.Request(r.Context(), pub.GET("https://alpha.actor.connector/admin"))
+		if err != nil {
+			return err
+		}
+		b, _ := io.ReadAll(res.Body)
+		w.Write(b)
+		return nil
+	})
+	beta.Subscribe("GET", "whoami", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(frame.Of(r).Actor().Subject()))
+		return nil
+	})
+
+	// Startup the microservices
+	err = alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Actor keys must be defined before startup
+	err = beta.Subscribe("GET", "late", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}, sub.Actor("roles.admin"))
+	testarossa.Error(t, err)
+	_, err = beta.SignActor(frame.Actor{"sub": "harry"})
+	testarossa.Error(t, err)
+
+	admin, err := alpha.SignActor(frame.Actor{"sub": "dumbledore", "roles": []string{"admin", "wizard"}})
+	testarossa.NoError(t, err)
+	wizard, err := alpha.SignActor(frame.Actor{"sub": "harry", "roles": []string{"wizard"}})
+	testarossa.NoError(t, err)
+	expired, err := alpha.SignActor(frame.Actor{"sub": "dumbledore", "roles": []string{"admin"}, "exp": time.Now().Add(-time.Minute).Unix()})
+	testarossa.NoError(t, err)
+	tampered := admin[:len(admin)-2] + "xx"
+
+	actor, err := alpha.VerifyActor(admin)
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "dumbledore", actor.Subject())
+		testarossa.True(t, actor.HasValue("roles", "admin"))
+	}
+
+	// The requirement is enforced
+	testCases := []struct {
+		url    string
+		token  string
+		status int
+		body   string
+	}{
+		{"https://alpha.actor.connector/admin", "", http.StatusUnauthorized, ""},
+		{"https://alpha.actor.connector/admin", wizard, http.StatusForbidden, ""},
+		{"https://alpha.actor.connector/admin", admin, http.StatusOK, "dumbledore"},
+		{"https://alpha.actor.connector/admin", tampered, http.StatusUnauthorized, ""},
+		{"https://alpha.actor.connector/admin", expired, http.StatusUnauthorized, ""},
+		{"https://alpha.actor.connector/whoami", "", http.StatusOK, ""},
+		{"https://alpha.actor.connector/whoami", wizard, http.StatusOK, "harry"},
+		{"https://alpha.actor.connector/whoami", tampered, http.StatusUnauthorized, ""},
+		{"https://beta.actor.connector/forward", admin, http.StatusOK, "dumbledore"},
+		{"https://beta.actor.connector/forward", wizard, http.StatusForbidden, ""},
+		{"https://beta.actor.connector/whoami", wizard, http.StatusOK, ""},   // Not validated, so not exposed
+		{"https://beta.actor.connector/whoami", tampered, http.StatusOK, ""}, // Not validated, so not exposed
+	}
+	for _, tc := range testCases {
+		res, err := beta.Request(ctx, pub.GET(tc.url), pub.Actor(tc.token))
+		if tc.status == http.StatusOK {
+			if testarossa.NoError(t, err) {
+				b, _ := io.ReadAll(res.Body)
+				testarossa.Equal(t, tc.body, string(b))
+			}
+		} else {
+			testarossa.Error(t, err)
+			testarossa.Equal(t, tc.status, errors.StatusCode(err), "%s", tc.url)
+		}
+	}
+
+	// The verification flag cannot be forged by the caller
+	res, err := beta.Request(ctx, pub.GET("https://beta.actor.connector/whoami"), pub.Actor(tampered), pub.Header(frame.HeaderActorVerified, "1"))
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "", string(b))
+	}
+
+	// Tokens signed with any of the keys are accepted
+	err = alpha.SetConfig("ActorKeys", "key2")
+	testarossa.NoError(t, err)
+	rotated, err := alpha.SignActor(frame.Actor{"sub": "minerva", "roles": []string{"admin"}})
+	testarossa.NoError(t, err)
+	err = alpha.SetConfig("ActorKeys", "key1 key2")
+	testarossa.NoError(t, err)
+	res, err = beta.Request(ctx, pub.GET("https://alpha.actor.connector/admin"), pub.Actor(rotated))
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "minerva", string(b))
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// Circuit breaker states
+const (
+	breakerClosed   = 0
+	breakerHalfOpen = 1
+	breakerOpen     = 2
+)
+
+// Outcomes of requests that were allowed through the circuit breaker
+const (
+	breakerSuccess      = 0
+	breakerFailure      = 1
+	breakerInconclusive = 2
+)
+
+// circuitBreaker tracks the health of a destination of unicast requests.
+// A closed circuit breaker lets requests through and counts consecutive failures.
+// It opens when the count reaches the failure threshold, at which point requests are failed fast.
+// Once the cooldown period elapses, the circuit breaker becomes half-open and lets a single probe request through.
+// The outcome of the probe determines if the circuit breaker closes or opens again.
+type circuitBreaker struct {
+	host     string
+	path     string
+	mux      sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow indicates if a request is allowed through the circuit breaker.
+// A state transition is indicated by changed.
+func (cb *circuitBreaker) allow(policy *pub.BreakerPolicy, now time.Time) (ok bool, changed bool) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	switch cb.state {
+	case breakerOpen:
+		if now.Sub(cb.openedAt) < policy.Cooldown {
+			return false, false
+		}
+		cb.state = breakerHalfOpen
+		cb.probing = true
+		return true, true
+	case breakerHalfOpen:
+		if cb.probing {
+			return false, false
+		}
+		cb.probing = true
+		return true, false
+	default:
+		return true, false
+	}
+}
+
+// report records the outcome of a request that was allowed through the circuit breaker.
+// An inconclusive outcome says nothing of the health of the destination.
+// It releases the probe of a half-open circuit breaker so that another request can probe the destination.
+// A state transition is indicated by changed.
+func (cb *circuitBreaker) report(policy *pub.BreakerPolicy, outcome int, now time.Time) (changed bool) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	switch cb.state {
+	case breakerHalfOpen:
+		cb.probing = false
+		switch outcome {
+		case breakerFailure:
+			cb.state = breakerOpen
+			cb.openedAt = now
+		case breakerSuccess:
+			cb.state = breakerClosed
+			cb.failures = 0
+		default:
+			return false
+		}
+		return true
+	case breakerClosed:
+		if outcome == breakerInconclusive {
+			return false
+		}
+		if outcome == breakerSuccess {
+			cb.failures = 0
+			return false
+		}
+		cb.failures++
+		if cb.failures < policy.FailureThreshold {
+			return false
+		}
+		cb.state = breakerOpen
+		cb.openedAt = now
+		cb.failures = 0
+		return true
+	default:
+		// Outcome of a request that was made before the circuit breaker opened
+		return false
+	}
+}
+
+// State returns the state of the circuit breaker.
+func (cb *circuitBreaker) State() int {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	return cb.state
+}
+
+// SetCircuitBreaker sets the default circuit breaker for unicast requests made by this microservice.
+// The circuit breaker of a destination hostname opens after the threshold of consecutive failures is reached,
+// failing subsequent requests fast with a 503 until the cooldown period elapses.
+// Failures are ack timeouts and errors with a 5xx status code returned by the destination.
+// Local errors, such as timeouts of the caller's own time budget, count neither as failures nor as successes.
+// A failure threshold of 0 disables the circuit breaker, which is the default.
+// The default can be overridden for individual requests using pub.CircuitBreaker, pub.PathCircuitBreaker
+// or pub.NoCircuitBreaker.
+func (c *Connector) SetCircuitBreaker(failureThreshold int, cooldown time.Duration) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	if failureThreshold < 0 {
+		return c.captureInitErr(errors.Newf("negative failure threshold '%d'", failureThreshold))
+	}
+	if failureThreshold > 0 && cooldown <= 0 {
+		return c.captureInitErr(errors.Newf("non-positive cooldown '%v'", cooldown))
+	}
+	c.breakerPolicy = pub.BreakerPolicy{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+	}
+	return nil
+}
+
+// makeBreakerAwareRequest makes a unicast request through the circuit breaker of its destination.
+// The request is failed fast with a 503 if the circuit breaker is open.
+func (c *Connector) makeBreakerAwareRequest(ctx context.Context, req *pub.Request) (output []*pub.Response) {
+	policy := req.BreakerPolicy
+	if policy == nil {
+		policy = &c.breakerPolicy
+	}
+	if req.Multicast || policy.FailureThreshold <= 0 {
+		return c.makeVersionAwareRequest(ctx, req)
+	}
+	u, err := url.Parse(req.Canonical())
+	if err != nil {
+		return []*pub.Response{pub.NewErrorResponse(errors.Trace(err))}
+	}
+	host := strings.ToLower(u.Hostname())
+	path := ""
+	if policy.PerPath {
+		path = u.Path
+		if u.Port() != "" {
+			path = ":" + u.Port() + path
+		}
+	}
+	cb, _ := c.breakers.LoadOrStore(host+path, &circuitBreaker{host: host, path: path})
+
+	ok, changed := cb.allow(policy, time.Now())
+	if changed {
+		c.observeBreakerState(ctx, cb)
+	}
+	if !ok {
+		err = errors.Newcf(http.StatusServiceUnavailable, "circuit breaker open for '%s'", host+path)
+		return []*pub.Response{pub.NewErrorResponse(err)}
+	}
+
+	output = c.makeVersionAwareRequest(ctx, req)
+
+	if cb.report(policy, breakerOutcome(output[0]), time.Now()) {
+		c.observeBreakerState(ctx, cb)
+	}
+	return output
+}
+
+// breakerOutcome classifies the response of a request that was allowed through the circuit breaker.
+// Ack timeouts and 5xx errors returned by the destination are failures, and any other response from the destination
+// is a success. Local errors, such as timeouts of the caller's own time budget, are inconclusive.
+func breakerOutcome(res *pub.Response) int {
+	_, err := res.Get()
+	switch {
+	case err == nil:
+		return breakerSuccess
+	case pub.IsAckTimeout(err):
+		return breakerFailure
+	case res.RemoteError() && errors.StatusCode(err) >= 500:
+		return breakerFailure
+	case res.RemoteError():
+		return breakerSuccess
+	default:
+		return breakerInconclusive
+	}
+}
+
+// observeBreakerState logs and records the metric of the state of the circuit breaker after it changed.
+func (c *Connector) observeBreakerState(ctx context.Context, cb *circuitBreaker) {
+	state := cb.State()
+	switch state {
+	case breakerOpen:
+		c.LogWarn(ctx, "Circuit breaker opened", "destination", cb.host+cb.path)
+	case breakerClosed:
+		c.LogInfo(ctx, "Circuit breaker closed", "destination", cb.host+cb.path)
+	}
+	_ = c.ObserveMetric(
+		"microbus_circuit_breaker_state",
+		float64(state),
+		cb.host,
+		cb.path,
+	)
+}
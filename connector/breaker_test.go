@@ -0,0 +1,233 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_CircuitBreakerStates(t *testing.T) {
+	t.Parallel()
+
+	policy := &pub.BreakerPolicy{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+	}
+	cb := &circuitBreaker{}
+	t0 := time.Now()
+
+	// Successes reset the count of consecutive failures
+	for i := 0; i < 2; i++ {
+		ok, _ := cb.allow(policy, t0)
+		testarossa.True(t, ok)
+		testarossa.False(t, cb.report(policy, breakerFailure, t0))
+	}
+	cb.report(policy, breakerSuccess, t0)
+	for i := 0; i < 2; i++ {
+		testarossa.False(t, cb.report(policy, breakerFailure, t0))
+	}
+	testarossa.Equal(t, breakerClosed, cb.State())
+
+	// Trip
+	testarossa.True(t, cb.report(policy, breakerFailure, t0))
+	testarossa.Equal(t, breakerOpen, cb.State())
+	ok, _ := cb.allow(policy, t0.Add(time.Second))
+	testarossa.False(t, ok)
+
+	// Half-open after the cooldown, allowing a single probe
+	ok, changed := cb.allow(policy, t0.Add(time.Minute))
+	testarossa.True(t, ok)
+	testarossa.True(t, changed)
+	testarossa.Equal(t, breakerHalfOpen, cb.State())
+	ok, _ = cb.allow(policy, t0.Add(time.Minute))
+	testarossa.False(t, ok)
+
+	// Failed probe reopens
+	testarossa.True(t, cb.report(policy, breakerFailure, t0.Add(time.Minute)))
+	testarossa.Equal(t, breakerOpen, cb.State())
+	ok, _ = cb.allow(policy, t0.Add(time.Minute+time.Second))
+	testarossa.False(t, ok)
+
+	// Inconclusive probe keeps the breaker half-open and releases the probe
+	ok, _ = cb.allow(policy, t0.Add(2*time.Minute))
+	testarossa.True(t, ok)
+	testarossa.False(t, cb.report(policy, breakerInconclusive, t0.Add(2*time.Minute)))
+	testarossa.Equal(t, breakerHalfOpen, cb.State())
+
+	// Successful probe closes
+	ok, _ = cb.allow(policy, t0.Add(2*time.Minute))
+	testarossa.True(t, ok)
+	testarossa.True(t, cb.report(policy, breakerSuccess, t0.Add(2*time.Minute)))
+	testarossa.Equal(t, breakerClosed, cb.State())
+	ok, _ = cb.allow(policy, t0.Add(2*time.Minute))
+	testarossa.True(t, ok)
+
+	// Inconclusive outcomes neither count as failures nor reset the count
+	testarossa.False(t, cb.report(policy, breakerFailure, t0))
+	testarossa.False(t, cb.report(policy, breakerFailure, t0))
+	testarossa.False(t, cb.report(policy, breakerInconclusive, t0))
+	testarossa.True(t, cb.report(policy, breakerFailure, t0))
+	testarossa.Equal(t, breakerOpen, cb.State())
+}
+
+func TestConnector_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	beta := New("circuit.breaker.beta.connector")
+	beta.Subscribe("GET", "flaky", func(w http.ResponseWriter, r *http.Request) error {
+		calls.Add(1)
+		if !healthy.Load() {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	beta.Subscribe("GET", "missing", func(w http.ResponseWriter, r *http.Request) error {
+		calls.Add(1)
+		return errors.Newc(http.StatusNotFound, "not found")
+	})
+	beta.Subscribe("GET", "slow", func(w http.ResponseWriter, r *http.Request) error {
+		calls.Add(1)
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	alpha := New("circuit.breaker.alpha.connector")
+	err := alpha.SetCircuitBreaker(3, 200*time.Millisecond)
+	testarossa.NoError(t, err)
+
+	// Startup the microservices
+	err = alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Not found errors do not trip the circuit breaker
+	for i := 0; i < 4; i++ {
+		_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/missing")
+		testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	}
+	testarossa.Equal(t, int32(4), calls.Load())
+
+	// Timeouts of the caller's own time budget do not trip the circuit breaker
+	calls.Store(0)
+	for i := 0; i < 4; i++ {
+		shortCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		_, err = alpha.GET(shortCtx, "https://circuit.breaker.beta.connector/slow")
+		cancel()
+		testarossa.Equal(t, http.StatusRequestTimeout, errors.StatusCode(err))
+	}
+	testarossa.Equal(t, int32(4), calls.Load())
+
+	// Trip the circuit breaker
+	calls.Store(0)
+	for i := 0; i < 3; i++ {
+		_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/flaky")
+		testarossa.Equal(t, http.StatusInternalServerError, errors.StatusCode(err))
+	}
+	testarossa.Equal(t, int32(3), calls.Load())
+
+	// Requests fail fast without reaching the destination
+	_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/missing")
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	testarossa.Equal(t, int32(3), calls.Load())
+
+	// Requests can opt out of the circuit breaker
+	_, err = alpha.Request(ctx,
+		pub.GET("https://circuit.breaker.beta.connector/missing"),
+		pub.NoCircuitBreaker(),
+	)
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	testarossa.Equal(t, int32(4), calls.Load())
+
+	// Failed probe after the cooldown reopens the circuit breaker
+	time.Sleep(250 * time.Millisecond)
+	_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/flaky")
+	testarossa.Equal(t, http.StatusInternalServerError, errors.StatusCode(err))
+	testarossa.Equal(t, int32(5), calls.Load())
+	_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/flaky")
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	testarossa.Equal(t, int32(5), calls.Load())
+
+	// Successful probe after the cooldown closes the circuit breaker
+	healthy.Store(true)
+	time.Sleep(250 * time.Millisecond)
+	_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/flaky")
+	testarossa.NoError(t, err)
+	_, err = alpha.GET(ctx, "https://circuit.breaker.beta.connector/flaky")
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, int32(7), calls.Load())
+}
+
+func TestConnector_PathCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("path.circuit.breaker.connector")
+	con.Subscribe("GET", "fail", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("failed")
+	})
+	con.Subscribe("GET", "ok", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Trip the circuit breaker of the path
+	for i := 0; i < 2; i++ {
+		_, err = con.Request(ctx,
+			pub.GET("https://path.circuit.breaker.connector/fail"),
+			pub.PathCircuitBreaker(2, time.Minute),
+		)
+		testarossa.Equal(t, http.StatusInternalServerError, errors.StatusCode(err))
+	}
+	_, err = con.Request(ctx,
+		pub.GET("https://path.circuit.breaker.connector/fail"),
+		pub.PathCircuitBreaker(2, time.Minute),
+	)
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+
+	// Other paths of the same host are not affected
+	_, err = con.Request(ctx,
+		pub.GET("https://path.circuit.breaker.connector/ok"),
+		pub.PathCircuitBreaker(2, time.Minute),
+	)
+	testarossa.NoError(t, err)
+
+	// The circuit breaker is disabled by default
+	_, err = con.GET(ctx, "https://path.circuit.breaker.connector/fail")
+	testarossa.Equal(t, http.StatusInternalServerError, errors.StatusCode(err))
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// verifyRequestCaller checks that the host of the caller of an incoming request is allowed to call the subscription.
+// A request delivered by the scheduler is checked against the host on behalf of which it was scheduled.
+// Denied requests are counted by the microbus_callers_denied_total metric.
+func (c *Connector) verifyRequestCaller(r *http.Request, s *sub.Subscription) error {
+	fromHost := frame.Of(r).FromHost()
+	if onBehalfOf := frame.Of(r).OnBehalfOf(); onBehalfOf != "" && fromHost == schedulerHost {
+		fromHost = onBehalfOf
+	}
+	if s.AllowsCaller(fromHost) {
+		return nil
+	}
+	_ = c.IncrementMetric(
+		"microbus_callers_denied_total",
+		1,
+		s.Canonical(),
+		s.Port,
+		s.Method,
+	)
+	return errors.Newcf(http.StatusForbidden, "caller '%s' not allowed", fromHost)
+}
+
+// warnUnprotectedCallers logs a warning for each subscription that restricts its callers
+// while the microservice does not strictly verify the signatures of incoming messages.
+// Without strict signing, the Microbus-From-Host header on which the restriction relies can be spoofed.
+func (c *Connector) warnUnprotectedCallers(ctx context.Context) {
+	if len(c.signingKeys) > 0 && c.strictSigning {
+		return
+	}
+	for _, s := range c.subs {
+		if len(s.AllowedCallers) > 0 {
+			c.LogWarn(ctx, "Allowed callers are not protected from spoofing without strict signing",
+				"url", s.Canonical(),
+				"method", s.Method,
+			)
+		}
+	}
+}
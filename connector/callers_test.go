@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_AllowedCallers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	ledger := New("ledger.allowed.callers.connector")
+	ledger.SetPlane(plane)
+	ledger.Subscribe("POST", "debit", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	}, sub.AllowCallers("billing.allowed.callers.connector", "*.admin.allowed.callers.connector"))
+	ledger.Subscribe("GET", "balance", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	})
+
+	billing := New("billing.allowed.callers.connector")
+	billing.SetPlane(plane)
+	admin := New("users.admin.allowed.callers.connector")
+	admin.SetPlane(plane)
+	intruder := New("intruder.allowed.callers.connector")
+	intruder.SetPlane(plane)
+
+	// Startup the microservices
+	for _, con := range []*Connector{ledger, billing, admin, intruder} {
+		err := con.Startup()
+		testarossa.NoError(t, err)
+		defer con.Shutdown()
+	}
+
+	// Allowed callers
+	_, err := billing.Request(ctx, pub.POST("https://ledger.allowed.callers.connector/debit"))
+	testarossa.NoError(t, err)
+	_, err = admin.Request(ctx, pub.POST("https://ledger.allowed.callers.connector/debit"))
+	testarossa.NoError(t, err)
+
+	// Disallowed caller
+	_, err = intruder.Request(ctx, pub.POST("https://ledger.allowed.callers.connector/debit"))
+	testarossa.Equal(t, http.StatusForbidden, errors.StatusCode(err))
+
+	// Unrestricted endpoint
+	_, err = intruder.Request(ctx, pub.GET("https://ledger.allowed.callers.connector/balance"))
+	testarossa.NoError(t, err)
+
+	// Allowed callers are not protected without strict signing
+	var buf strings.Builder
+	ledger.logger = slog.New(slog.NewTextHandler(&buf, nil))
+	ledger.warnUnprotectedCallers(ctx)
+	testarossa.Contains(t, buf.String(), "level=WARN")
+	testarossa.Contains(t, buf.String(), "/debit")
+	testarossa.NotContains(t, buf.String(), "/balance")
+
+	buf.Reset()
+	ledger.signingKeys = parseKeys("secret")
+	ledger.strictSigning = true
+	ledger.warnUnprotectedCallers(ctx)
+	testarossa.Equal(t, "", buf.String())
+}
@@ -0,0 +1,226 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// versionPrefix is the prefix of the hostname that targets the instances of a microservice with a specific version.
+// For version 7 of example.com that hostname is v7.example.com .
+func versionPrefix(version int) string {
+	return "v" + strconv.Itoa(version)
+}
+
+/*
+SetVersionWeights splits the unicast requests to the hostname between versions of the destination microservice,
+in proportion to their weights. Version 0 stands for any version.
+For example, the weights {7: 95, 8: 5} send 5% of the requests to version 8 and the rest to version 7.
+If no instance of the chosen version responds, the request is retried with any version.
+The body of a request that is subject to the split is therefore held in memory rather than streamed.
+Setting nil weights removes the split.
+
+Splits that apply to all microservices can be set in the MICROBUS_CANARY_WEIGHTS environment variable.
+
+Requests that are pinned to a version using pub.Version are not subject to the split.
+*/
+func (c *Connector) SetVersionWeights(host string, weights map[int]int) error {
+	if err := utils.ValidateHostname(host); err != nil {
+		return errors.Trace(err)
+	}
+	total := 0
+	copied := map[int]int{}
+	for version, weight := range weights {
+		if version < 0 {
+			return errors.Newf("invalid version %d", version)
+		}
+		if weight < 0 {
+			return errors.Newf("invalid weight %d for version %d", weight, version)
+		}
+		if weight > 0 {
+			copied[version] = weight
+			total += weight
+		}
+	}
+	if len(weights) > 0 && total == 0 {
+		return errors.New("weights must not all be zero")
+	}
+	key := strings.ToLower(host)
+	c.versionWeightsLock.Lock()
+	if total == 0 {
+		delete(c.versionWeights, key)
+	} else {
+		c.versionWeights[key] = copied
+	}
+	c.versionWeightsLock.Unlock()
+	return nil
+}
+
+/*
+ParseVersionWeights parses a canary split in the form host:version=weight,version=weight.
+For example, calculator.example:7=95,8=5 splits the requests to calculator.example between version 7 and version 8.
+*/
+func ParseVersionWeights(split string) (host string, weights map[int]int, err error) {
+	host, vws, ok := strings.Cut(split, ":")
+	if !ok {
+		return "", nil, errors.New("missing host")
+	}
+	host = strings.ToLower(strings.TrimSpace(host))
+	weights = map[int]int{}
+	for _, vw := range strings.Split(vws, ",") {
+		v, w, ok := strings.Cut(vw, "=")
+		if !ok {
+			return "", nil, errors.Newf("invalid weight '%s'", strings.TrimSpace(vw))
+		}
+		version, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return "", nil, errors.Newf("invalid version '%s'", strings.TrimSpace(v))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(w))
+		if err != nil {
+			return "", nil, errors.Newf("invalid weight '%s'", strings.TrimSpace(w))
+		}
+		weights[version] = weight
+	}
+	return host, weights, nil
+}
+
+// initVersionWeights sets the canary splits that are listed in the MICROBUS_CANARY_WEIGHTS environment variable,
+// separated by semicolons or new lines, unless a split was already set for the hostname.
+func (c *Connector) initVersionWeights() error {
+	value := env.Get("MICROBUS_CANARY_WEIGHTS")
+	for _, split := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if strings.TrimSpace(split) == "" {
+			continue
+		}
+		host, weights, err := ParseVersionWeights(split)
+		if err != nil {
+			return errors.Newf("invalid canary weights '%s': %v", strings.TrimSpace(split), err)
+		}
+		if c.VersionWeights(host) != nil {
+			continue
+		}
+		err = c.SetVersionWeights(host, weights)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// VersionWeights returns the split of unicast requests to the hostname between versions of the destination microservice.
+func (c *Connector) VersionWeights(host string) (weights map[int]int) {
+	c.versionWeightsLock.RLock()
+	defer c.versionWeightsLock.RUnlock()
+	if w, ok := c.versionWeights[strings.ToLower(host)]; ok {
+		weights = map[int]int{}
+		for version, weight := range w {
+			weights[version] = weight
+		}
+	}
+	return weights
+}
+
+// pickVersion chooses a version of the destination microservice in proportion to the weights set for the hostname.
+// Zero is returned if there are no weights set, or if version 0 was chosen.
+func (c *Connector) pickVersion(host string) int {
+	c.versionWeightsLock.RLock()
+	defer c.versionWeightsLock.RUnlock()
+	weights, ok := c.versionWeights[strings.ToLower(host)]
+	if !ok {
+		return 0
+	}
+	versions := make([]int, 0, len(weights))
+	total := 0
+	for version, weight := range weights {
+		versions = append(versions, version)
+		total += weight
+	}
+	sort.Ints(versions)
+	r := rand.IntN(total)
+	for _, version := range versions {
+		r -= weights[version]
+		if r < 0 {
+			return version
+		}
+	}
+	return 0
+}
+
+// makeVersionAwareRequest makes a request to the instances of the destination with the pinned version,
+// or with a version that is chosen by weight.
+// A request to a version chosen by weight is retried with any version if no response is received.
+func (c *Connector) makeVersionAwareRequest(ctx context.Context, req *pub.Request) (output []*pub.Response) {
+	version := frame.Of(req.Header).Version()
+	pinned := version > 0
+	if !pinned && !req.Multicast {
+		c.versionWeightsLock.RLock()
+		weighted := len(c.versionWeights) > 0
+		c.versionWeightsLock.RUnlock()
+		if weighted {
+			u, err := url.Parse(req.URL)
+			if err != nil {
+				return []*pub.Response{pub.NewErrorResponse(errors.Trace(err))}
+			}
+			version = c.pickVersion(u.Hostname())
+		}
+	}
+	if version == 0 {
+		return c.makeLocalityAwareRequest(ctx, req)
+	}
+
+	// Hold the body in memory if the request may need to be sent again with any version
+	if !pinned && req.Body != nil {
+		if _, ok := req.Body.(*httpx.BodyReader); !ok {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return []*pub.Response{pub.NewErrorResponse(errors.Trace(err))}
+			}
+			req.Body = httpx.NewBodyReader(body)
+		}
+	}
+
+	// Adjust the hostname to target the version, e.g. example.com -> v7.example.com
+	origURL := req.URL
+	before, after, _ := strings.Cut(origURL, "://")
+	req.URL = before + "://" + versionPrefix(version) + "." + after
+	output = c.makeRequest(ctx, req)
+	req.URL = origURL
+
+	if !pinned {
+		_, err := output[0].Get()
+		if pub.IsAckTimeout(err) {
+			// No instance of the version responded so retry with any version
+			// The body is rewound by makeRequest
+			output = c.makeLocalityAwareRequest(ctx, req)
+		}
+	}
+	return output
+}
@@ -0,0 +1,238 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_VersionPinning(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	newAlpha := func(version int) *Connector {
+		alpha := New("version.pinning.connector")
+		alpha.SetVersion(version)
+		alpha.Subscribe("GET", "hello", func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+		return alpha
+	}
+	alpha7 := newAlpha(7)
+	alpha8 := newAlpha(8)
+	beta := New("beta.version.pinning.connector")
+
+	// Startup the microservices
+	err := alpha7.Startup()
+	testarossa.NoError(t, err)
+	defer alpha7.Shutdown()
+	err = alpha8.Startup()
+	testarossa.NoError(t, err)
+	defer alpha8.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Unpinned requests are load balanced between the versions
+	versions := map[int]int{}
+	for i := 0; i < 64; i++ {
+		res, err := beta.Request(ctx, pub.GET("https://version.pinning.connector/hello"))
+		if testarossa.NoError(t, err) {
+			versions[frame.Of(res).FromVersion()]++
+		}
+	}
+	testarossa.True(t, versions[7] > 0)
+	testarossa.True(t, versions[8] > 0)
+
+	// Pinned requests are routed to their version only
+	for _, version := range []int{7, 8} {
+		for i := 0; i < 16; i++ {
+			res, err := beta.Request(ctx, pub.GET("https://version.pinning.connector/hello"), pub.Version(version))
+			if testarossa.NoError(t, err) {
+				testarossa.Equal(t, version, frame.Of(res).FromVersion())
+			}
+		}
+	}
+
+	// Pinned multicast
+	count := 0
+	for r := range beta.Publish(ctx, pub.GET("https://version.pinning.connector/hello"), pub.Version(8)) {
+		res, err := r.Get()
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, 8, frame.Of(res).FromVersion())
+			count++
+		}
+	}
+	testarossa.Equal(t, 1, count)
+
+	// No instance of the pinned version
+	_, err = beta.Request(ctx, pub.GET("https://version.pinning.connector/hello"), pub.Version(9))
+	testarossa.Error(t, err)
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+}
+
+func TestConnector_VersionWeights(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	newAlpha := func(version int) *Connector {
+		alpha := New("version.weights.connector")
+		alpha.SetVersion(version)
+		alpha.Subscribe("GET", "hello", func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+		alpha.Subscribe("POST", "echo", func(w http.ResponseWriter, r *http.Request) error {
+			_, err := io.Copy(w, r.Body)
+			return errors.Trace(err)
+		})
+		return alpha
+	}
+	alpha7 := newAlpha(7)
+	alpha8 := newAlpha(8)
+	beta := New("beta.version.weights.connector")
+
+	// Startup the microservices
+	err := alpha7.Startup()
+	testarossa.NoError(t, err)
+	defer alpha7.Shutdown()
+	err = alpha8.Startup()
+	testarossa.NoError(t, err)
+	defer alpha8.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	countVersions := func(options ...pub.Option) map[int]int {
+		versions := map[int]int{}
+		for i := 0; i < 64; i++ {
+			options := append([]pub.Option{pub.GET("https://version.weights.connector/hello")}, options...)
+			res, err := beta.Request(ctx, options...)
+			if testarossa.NoError(t, err) {
+				versions[frame.Of(res).FromVersion()]++
+			}
+		}
+		return versions
+	}
+
+	// All to one version
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{7: 0, 8: 100})
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, map[int]int{8: 100}, beta.VersionWeights("version.weights.connector"))
+	versions := countVersions()
+	testarossa.Equal(t, 64, versions[8])
+
+	// Pinning takes precedence over the weights
+	versions = countVersions(pub.Version(7))
+	testarossa.Equal(t, 64, versions[7])
+
+	// Split between versions
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{7: 1, 8: 1})
+	testarossa.NoError(t, err)
+	versions = countVersions()
+	testarossa.True(t, versions[7] > 0)
+	testarossa.True(t, versions[8] > 0)
+
+	// Fall back to any version if the chosen version is not running
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{9: 1})
+	testarossa.NoError(t, err)
+	res, err := beta.Request(ctx, pub.GET("https://version.weights.connector/hello"))
+	if testarossa.NoError(t, err) {
+		testarossa.True(t, frame.Of(res).FromVersion() == 7 || frame.Of(res).FromVersion() == 8)
+	}
+
+	// The streamed body is sent again in full to any version
+	body := strings.Repeat("1234567890", 200*1024) // Multiple fragments
+	res, err = beta.Request(ctx, pub.POST("https://version.weights.connector/echo"), pub.Body(io.MultiReader(strings.NewReader(body))))
+	if testarossa.NoError(t, err) {
+		echoed, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, len(body), len(echoed))
+		testarossa.True(t, body == string(echoed))
+	}
+
+	// Remove the split
+	err = beta.SetVersionWeights("version.weights.connector", nil)
+	testarossa.NoError(t, err)
+	testarossa.Nil(t, beta.VersionWeights("version.weights.connector"))
+
+	// Invalid weights
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{7: -1})
+	testarossa.Error(t, err)
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{-7: 1})
+	testarossa.Error(t, err)
+	err = beta.SetVersionWeights("version.weights.connector", map[int]int{7: 0})
+	testarossa.Error(t, err)
+	err = beta.SetVersionWeights("invalid host", map[int]int{7: 1})
+	testarossa.Error(t, err)
+}
+
+func TestConnector_ParseVersionWeights(t *testing.T) {
+	t.Parallel()
+
+	host, weights, err := ParseVersionWeights("Calculator.Example: 7=95, 8=5")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "calculator.example", host)
+		testarossa.Equal(t, map[int]int{7: 95, 8: 5}, weights)
+	}
+	_, _, err = ParseVersionWeights("7=95,8=5")
+	testarossa.Error(t, err)
+	_, _, err = ParseVersionWeights("calculator.example:7")
+	testarossa.Error(t, err)
+	_, _, err = ParseVersionWeights("calculator.example:x=1")
+	testarossa.Error(t, err)
+	_, _, err = ParseVersionWeights("calculator.example:7=x")
+	testarossa.Error(t, err)
+}
+
+func TestConnector_VersionWeightsEnv(t *testing.T) {
+	// No parallel
+
+	env.Push("MICROBUS_CANARY_WEIGHTS", "alpha.version.weights.env.connector:7=95,8=5; beta.version.weights.env.connector:0=1,9=1")
+	defer env.Pop("MICROBUS_CANARY_WEIGHTS")
+
+	// Splits set explicitly take precedence
+	con := New("version.weights.env.connector")
+	err := con.SetVersionWeights("beta.version.weights.env.connector", map[int]int{9: 1})
+	testarossa.NoError(t, err)
+
+	err = con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	testarossa.Equal(t, map[int]int{7: 95, 8: 5}, con.VersionWeights("alpha.version.weights.env.connector"))
+	testarossa.Equal(t, map[int]int{9: 1}, con.VersionWeights("beta.version.weights.env.connector"))
+
+	// Invalid splits fail the startup
+	env.Push("MICROBUS_CANARY_WEIGHTS", "alpha.version.weights.env.connector:7")
+	defer env.Pop("MICROBUS_CANARY_WEIGHTS")
+	bad := New("bad.version.weights.env.connector")
+	err = bad.Startup()
+	testarossa.Error(t, err)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package connector
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// encodeRequestCBOR re-encodes the body of a request that opted in to CBOR,
+// if the destination is known to accept CBOR. Otherwise, the body remains JSON.
+func (c *Connector) encodeRequestCBOR(httpReq *http.Request, req *pub.Request) error {
+	if req.CBORBody == nil {
+		return nil
+	}
+	accepted, _ := c.peerCBOR.Load(httpReq.URL.Hostname())
+	if !accepted {
+		return nil
+	}
+	b, err := httpx.MarshalCBOR(req.CBORBody)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpReq.Body = httpx.NewBodyReader(b)
+	httpReq.ContentLength = int64(len(b))
+	httpReq.Header.Set("Content-Type", "application/cbor")
+	httpReq.Header.Set("Content-Length", strconv.Itoa(len(b)))
+	return nil
+}
+
+// notePeerCBOR takes note of whether the responder accepts CBOR-encoded request bodies.
+func (c *Connector) notePeerCBOR(httpRes *http.Response) {
+	if frame.Of(httpRes).OpCode() == frame.OpCodeAck {
+		return
+	}
+	if frame.Of(httpRes).AcceptCBOR() {
+		c.peerCBOR.Store(frame.Of(httpRes).FromHost(), true)
+	} else {
+		c.peerCBOR.Delete(frame.Of(httpRes).FromHost())
+	}
+}
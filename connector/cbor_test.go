@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_CBORNegotiation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	// Create the microservices
+	alpha := New("alpha.cbor.negotiation.connector")
+	beta := New("beta.cbor.negotiation.connector")
+	var contentTypes []string
+	beta.Subscribe("POST", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		var p point
+		err := httpx.ParseRequestBody(r, &p)
+		testarossa.NoError(t, err)
+		testarossa.Equal(t, point{X: 1, Y: 2}, p)
+		return nil
+	})
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// The first request is sent as JSON and the second as CBOR
+	for i := 0; i < 2; i++ {
+		_, err := alpha.Request(ctx, pub.POST("https://beta.cbor.negotiation.connector/echo"), pub.CBORBody(point{X: 1, Y: 2}))
+		testarossa.NoError(t, err)
+	}
+	testarossa.Equal(t, []string{"application/json", "application/cbor"}, contentTypes)
+	accepted, _ := alpha.peerCBOR.Load("beta.cbor.negotiation.connector")
+	testarossa.True(t, accepted)
+
+	// Responses without the advertisement revoke it
+	res := &http.Response{Header: http.Header{}}
+	frame.Of(res).SetFromHost("beta.cbor.negotiation.connector")
+	frame.Of(res).SetOpCode(frame.OpCodeResponse)
+	alpha.notePeerCBOR(res)
+	_, ok := alpha.peerCBOR.Load("beta.cbor.negotiation.connector")
+	testarossa.False(t, ok)
+}
@@ -0,0 +1,227 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+const (
+	// compressionThreshold is the size of the body above which it is compressed
+	compressionThreshold = 4 * 1024
+	// acceptedCompressions are the compression algorithms that the connector accepts, in order of preference
+	acceptedCompressions = "zstd, br"
+	// maxDecompressedSize is the size to which a compressed body is allowed to decompress,
+	// to protect against bodies crafted to decompress to an excessive size
+	maxDecompressedSize = 256 * 1024 * 1024
+)
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+)
+
+// negotiateCompression returns the preferred compression algorithm out of a comma-separated list of accepted algorithms,
+// or an empty string if none are supported.
+func negotiateCompression(accepted string) string {
+	if accepted == "" {
+		return ""
+	}
+	for _, preferred := range strings.Split(acceptedCompressions, ",") {
+		preferred = strings.TrimSpace(preferred)
+		for _, a := range strings.Split(accepted, ",") {
+			if strings.TrimSpace(a) == preferred {
+				return preferred
+			}
+		}
+	}
+	return ""
+}
+
+// compress compresses the data with the compression algorithm.
+func compress(compression string, data []byte) ([]byte, error) {
+	switch compression {
+	case "zstd":
+		return zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	case "br":
+		var buf bytes.Buffer
+		w := brotli.NewWriter(&buf)
+		_, err := w.Write(data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = w.Close()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Newf("unsupported compression '%s'", compression)
+	}
+}
+
+// decompress decompresses the data with the compression algorithm.
+// Data that decompresses to more than the limit is rejected with a 413 error.
+// The limit may not exceed the maximum decompressed size.
+func decompress(compression string, data []byte, limit int) ([]byte, error) {
+	tooLarge := errors.Newc(http.StatusRequestEntityTooLarge, "decompressed body too large")
+	switch compression {
+	case "zstd":
+		var header zstd.Header
+		if header.Decode(data) == nil && header.HasFCS && header.FrameContentSize > uint64(limit) {
+			return nil, tooLarge
+		}
+		decompressed, err := zstdDecoder.DecodeAll(data, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, tooLarge
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(decompressed) > limit {
+			return nil, tooLarge
+		}
+		return decompressed, nil
+	case "br":
+		decompressed, err := io.ReadAll(io.LimitReader(brotli.NewReader(bytes.NewReader(data)), int64(limit)+1))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(decompressed) > limit {
+			return nil, tooLarge
+		}
+		return decompressed, nil
+	default:
+		return nil, errors.Newf("unsupported compression '%s'", compression)
+	}
+}
+
+// compressBody compresses an in-memory body with the compression algorithm if it is above the threshold and not already encoded.
+// It returns nil if the body should be sent as is.
+func compressBody(compression string, body io.Reader, header http.Header) ([]byte, error) {
+	if compression == "" || header.Get("Content-Encoding") != "" {
+		return nil, nil
+	}
+	bodyReader, ok := body.(*httpx.BodyReader)
+	if !ok || len(bodyReader.Bytes()) <= compressionThreshold {
+		return nil, nil
+	}
+	compressed, err := compress(compression, bodyReader.Bytes())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(compressed) >= len(bodyReader.Bytes()) {
+		return nil, nil
+	}
+	return compressed, nil
+}
+
+// compressRequest compresses the body of the request before it is fragmented,
+// if the destination is known to accept compression.
+func (c *Connector) compressRequest(httpReq *http.Request) error {
+	frame.Of(httpReq).SetAcceptCompression(acceptedCompressions)
+	accepted, _ := c.peerCompression.Load(httpReq.URL.Hostname())
+	compression := negotiateCompression(accepted)
+	compressed, err := compressBody(compression, httpReq.Body, httpReq.Header)
+	if err != nil || compressed == nil {
+		return errors.Trace(err)
+	}
+	httpReq.Body = httpx.NewBodyReader(compressed)
+	httpReq.ContentLength = int64(len(compressed))
+	httpReq.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+	frame.Of(httpReq).SetCompression(compression)
+	return nil
+}
+
+// decompressRequest decompresses the body of an incoming request that was compressed by the sender.
+func (c *Connector) decompressRequest(httpReq *http.Request) error {
+	compression := frame.Of(httpReq).Compression()
+	if compression == "" {
+		return nil
+	}
+	compressed, err := io.ReadAll(httpReq.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	decompressed, err := decompress(compression, compressed, maxDecompressedSize)
+	if errors.StatusCode(err) == http.StatusRequestEntityTooLarge {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Newc(http.StatusBadRequest, "malformed compressed body")
+	}
+	httpReq.Body = httpx.NewBodyReader(decompressed)
+	httpReq.ContentLength = int64(len(decompressed))
+	httpReq.Header.Set("Content-Length", strconv.Itoa(len(decompressed)))
+	frame.Of(httpReq).SetCompression("")
+	return nil
+}
+
+// compressResponse compresses the body of the response before it is fragmented,
+// if the request indicated that the caller accepts compression.
+func (c *Connector) compressResponse(httpReq *http.Request, httpRes *http.Response) error {
+	compression := negotiateCompression(frame.Of(httpReq).AcceptCompression())
+	compressed, err := compressBody(compression, httpRes.Body, httpRes.Header)
+	if err != nil || compressed == nil {
+		return errors.Trace(err)
+	}
+	httpRes.Body = httpx.NewBodyReader(compressed)
+	httpRes.ContentLength = int64(len(compressed))
+	httpRes.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+	frame.Of(httpRes).SetCompression(compression)
+	return nil
+}
+
+// decompressResponse decompresses the body of an incoming response that was compressed by the responder.
+// It also takes note of the compression algorithms that the responder accepts in requests.
+func (c *Connector) decompressResponse(httpRes *http.Response) error {
+	if frame.Of(httpRes).OpCode() != frame.OpCodeAck {
+		if accepted := frame.Of(httpRes).AcceptCompression(); accepted != "" {
+			c.peerCompression.Store(frame.Of(httpRes).FromHost(), accepted)
+		} else {
+			c.peerCompression.Delete(frame.Of(httpRes).FromHost())
+		}
+	}
+	compression := frame.Of(httpRes).Compression()
+	if compression == "" {
+		return nil
+	}
+	compressed, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	decompressed, err := decompress(compression, compressed, maxDecompressedSize)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpRes.Body = httpx.NewBodyReader(decompressed)
+	httpRes.ContentLength = int64(len(decompressed))
+	httpRes.Header.Set("Content-Length", strconv.Itoa(len(decompressed)))
+	frame.Of(httpRes).SetCompression("")
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_NegotiateCompression(t *testing.T) {
+	t.Parallel()
+
+	testarossa.Equal(t, "zstd", negotiateCompression("zstd, br"))
+	testarossa.Equal(t, "zstd", negotiateCompression("br,zstd"))
+	testarossa.Equal(t, "br", negotiateCompression("gzip, br"))
+	testarossa.Equal(t, "", negotiateCompression("gzip"))
+	testarossa.Equal(t, "", negotiateCompression(""))
+}
+
+func TestConnector_CompressDecompress(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat("Hello, World! ", 1024))
+	for _, compression := range []string{"zstd", "br"} {
+		compressed, err := compress(compression, data)
+		testarossa.NoError(t, err)
+		testarossa.True(t, len(compressed) < len(data))
+		decompressed, err := decompress(compression, compressed, maxDecompressedSize)
+		testarossa.NoError(t, err)
+		testarossa.Equal(t, data, decompressed)
+
+		// Decompressing beyond the limit is rejected
+		_, err = decompress(compression, compressed, len(data)-1)
+		testarossa.Equal(t, http.StatusRequestEntityTooLarge, errors.StatusCode(err))
+	}
+	_, err := compress("gzip", data)
+	testarossa.Error(t, err)
+	_, err = decompress("zstd", data, maxDecompressedSize)
+	testarossa.Error(t, err)
+}
+
+func TestConnector_CompressRequest(t *testing.T) {
+	t.Parallel()
+
+	con := New("compress.request.connector")
+	large := []byte(strings.Repeat("Hello, World! ", 1024))
+	small := []byte("Hello, World!")
+
+	newRequest := func(body []byte) *http.Request {
+		r, err := http.NewRequest("POST", "https://compress.request.connector/", httpx.NewBodyReader(body))
+		testarossa.NoError(t, err)
+		return r
+	}
+	body := func(r *http.Request) []byte {
+		b, err := io.ReadAll(r.Body)
+		testarossa.NoError(t, err)
+		return b
+	}
+
+	// Not compressed until the destination is known to accept compression
+	r := newRequest(large)
+	err := con.compressRequest(r)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(r).Compression())
+	testarossa.Equal(t, acceptedCompressions, frame.Of(r).AcceptCompression())
+	testarossa.Equal(t, large, body(r))
+
+	// Compressed
+	con.peerCompression.Store("compress.request.connector", "br")
+	r = newRequest(large)
+	err = con.compressRequest(r)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "br", frame.Of(r).Compression())
+	testarossa.True(t, r.ContentLength < int64(len(large)))
+	err = con.decompressRequest(r)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(r).Compression())
+	testarossa.Equal(t, int64(len(large)), r.ContentLength)
+	testarossa.Equal(t, large, body(r))
+
+	// Below the threshold
+	r = newRequest(small)
+	err = con.compressRequest(r)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(r).Compression())
+
+	// Already encoded
+	r = newRequest(large)
+	r.Header.Set("Content-Encoding", "gzip")
+	err = con.compressRequest(r)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(r).Compression())
+
+	// Malformed
+	r = newRequest(large)
+	frame.Of(r).SetCompression("zstd")
+	err = con.decompressRequest(r)
+	testarossa.Error(t, err)
+}
+
+func TestConnector_CompressResponse(t *testing.T) {
+	t.Parallel()
+
+	con := New("compress.response.connector")
+	large := []byte(strings.Repeat("Hello, World! ", 1024))
+
+	newResponse := func() *http.Response {
+		rec := httpx.NewResponseRecorder()
+		rec.Write(large)
+		res := rec.Result()
+		frame.Of(res).SetFromHost("compress.response.peer")
+		frame.Of(res).SetOpCode(frame.OpCodeResponse)
+		return res
+	}
+	req, _ := http.NewRequest("GET", "https://compress.response.connector/", nil)
+
+	// Not compressed unless the caller accepts compression
+	res := newResponse()
+	err := con.compressResponse(req, res)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(res).Compression())
+
+	// Compressed
+	frame.Of(req).SetAcceptCompression(acceptedCompressions)
+	res = newResponse()
+	err = con.compressResponse(req, res)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "zstd", frame.Of(res).Compression())
+	testarossa.True(t, res.ContentLength < int64(len(large)))
+
+	// The caller takes note of the compressions accepted by the responder
+	frame.Of(res).SetAcceptCompression(acceptedCompressions)
+	err = con.decompressResponse(res)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", frame.Of(res).Compression())
+	b, _ := io.ReadAll(res.Body)
+	testarossa.Equal(t, large, b)
+	accepted, _ := con.peerCompression.Load("compress.response.peer")
+	testarossa.Equal(t, acceptedCompressions, accepted)
+
+	// Responders that do not indicate accepted compressions are forgotten
+	res = newResponse()
+	err = con.decompressResponse(res)
+	testarossa.NoError(t, err)
+	_, ok := con.peerCompression.Load("compress.response.peer")
+	testarossa.False(t, ok)
+}
+
+func TestConnector_CompressedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	alpha := New("alpha.compressed.round.trip.connector")
+	alpha.maxFragmentSize = 1024
+	beta := New("beta.compressed.round.trip.connector")
+	beta.maxFragmentSize = 1024
+	beta.Subscribe("POST", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		testarossa.Equal(t, "", frame.Of(r).Compression())
+		b, err := io.ReadAll(r.Body)
+		testarossa.NoError(t, err)
+		w.Write(b)
+		return nil
+	})
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// The first request is not compressed but the second is
+	payload := []byte(strings.Repeat("Hello, World! ", 16*1024))
+	for i := 0; i < 2; i++ {
+		res, err := alpha.Request(ctx, pub.POST("https://beta.compressed.round.trip.connector/echo"), pub.Body(payload))
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, "", frame.Of(res).Compression())
+			b, _ := io.ReadAll(res.Body)
+			testarossa.True(t, bytes.Equal(payload, b))
+		}
+	}
+	accepted, _ := alpha.peerCompression.Load("beta.compressed.round.trip.connector")
+	testarossa.Equal(t, acceptedCompressions, accepted)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// concurrencyLimiter bounds the number of requests to a subscription that are processed concurrently.
+// Requests over the limit wait in a queue of bounded length, beyond which they are rejected.
+// A limiter with no slots does not limit concurrency but still counts the requests in flight.
+type concurrencyLimiter struct {
+	slots      chan struct{}
+	maxPending int32
+	pending    atomic.Int32
+	inFlight   atomic.Int32
+}
+
+// newConcurrencyLimiter creates a new limiter based on the limits of the subscription.
+func newConcurrencyLimiter(s *sub.Subscription) *concurrencyLimiter {
+	cl := &concurrencyLimiter{
+		maxPending: int32(s.MaxPending),
+	}
+	if s.MaxConcurrency > 0 {
+		cl.slots = make(chan struct{}, s.MaxConcurrency)
+	}
+	return cl
+}
+
+// acquire obtains a slot for a request, waiting in queue if necessary.
+// It returns a 503 error if the queue is full or if the context is done before a slot is obtained.
+// A request that obtained a slot must release it when done.
+func (cl *concurrencyLimiter) acquire(ctx context.Context) error {
+	if cl.slots == nil {
+		cl.inFlight.Add(1)
+		return nil
+	}
+	select {
+	case cl.slots <- struct{}{}:
+		cl.inFlight.Add(1)
+		return nil
+	default:
+	}
+	if cl.pending.Add(1) > cl.maxPending {
+		cl.pending.Add(-1)
+		return errors.Newc(http.StatusServiceUnavailable, "too many concurrent requests")
+	}
+	defer cl.pending.Add(-1)
+	select {
+	case cl.slots <- struct{}{}:
+		cl.inFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		return errors.Newc(http.StatusServiceUnavailable, "too many concurrent requests")
+	}
+}
+
+// release frees the slot obtained by a request.
+func (cl *concurrencyLimiter) release() {
+	cl.inFlight.Add(-1)
+	if cl.slots != nil {
+		<-cl.slots
+	}
+}
+
+// meterConcurrency records the number of requests to the subscription that are in flight and in queue.
+func (c *Connector) meterConcurrency(s *sub.Subscription, cl *concurrencyLimiter) {
+	_ = c.ObserveMetric(
+		"microbus_requests_in_flight",
+		float64(cl.inFlight.Load()),
+		s.Canonical(),
+		s.Port,
+		s.Method,
+	)
+	if cl.slots != nil {
+		_ = c.ObserveMetric(
+			"microbus_requests_pending",
+			float64(cl.pending.Load()),
+			s.Canonical(),
+			s.Port,
+			s.Method,
+		)
+	}
+}
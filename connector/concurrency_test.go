@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_ConcurrencyLimiter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cl := newConcurrencyLimiter(&sub.Subscription{MaxConcurrency: 2, MaxPending: 1})
+
+	// Two slots
+	testarossa.NoError(t, cl.acquire(ctx))
+	testarossa.NoError(t, cl.acquire(ctx))
+	testarossa.Equal(t, int32(2), cl.inFlight.Load())
+
+	// One in queue
+	acquired := make(chan error)
+	go func() {
+		acquired <- cl.acquire(ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	testarossa.Equal(t, int32(1), cl.pending.Load())
+
+	// Queue is full
+	err := cl.acquire(ctx)
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+
+	// Releasing a slot lets the queued request through
+	cl.release()
+	testarossa.NoError(t, <-acquired)
+	testarossa.Equal(t, int32(0), cl.pending.Load())
+	testarossa.Equal(t, int32(2), cl.inFlight.Load())
+
+	// Queued requests give up when their context is done
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	err = cl.acquire(shortCtx)
+	cancel()
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	testarossa.Equal(t, int32(0), cl.pending.Load())
+
+	// Unlimited
+	cl = newConcurrencyLimiter(&sub.Subscription{})
+	for i := 0; i < 100; i++ {
+		testarossa.NoError(t, cl.acquire(ctx))
+	}
+	testarossa.Equal(t, int32(100), cl.inFlight.Load())
+}
+
+func TestConnector_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	var running atomic.Int32
+	var maxRunning atomic.Int32
+	var handled atomic.Int32
+	release := make(chan bool)
+	con := New("max.concurrency.connector")
+	con.Subscribe("GET", "limited", func(w http.ResponseWriter, r *http.Request) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		<-release
+		handled.Add(1)
+		return nil
+	}, sub.MaxConcurrency(2), sub.MaxPending(2))
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Two requests are processed, two are queued and two are rejected
+	var wg sync.WaitGroup
+	var rejected atomic.Int32
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := con.GET(ctx, "https://max.concurrency.connector/limited")
+			if err != nil {
+				testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+				rejected.Add(1)
+			}
+		}()
+	}
+	for rejected.Load() < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	testarossa.Equal(t, int32(2), running.Load())
+	close(release)
+	wg.Wait()
+	testarossa.Equal(t, int32(2), rejected.Load())
+	testarossa.Equal(t, int32(4), handled.Load())
+	testarossa.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestConnector_MaxConcurrencyDrainOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	var handled atomic.Int32
+	beta := New("max.concurrency.drain.beta.connector")
+	beta.Subscribe("GET", "slow", func(w http.ResponseWriter, r *http.Request) error {
+		time.Sleep(100 * time.Millisecond)
+		handled.Add(1)
+		return nil
+	}, sub.MaxConcurrency(1), sub.MaxPending(10))
+
+	alpha := New("max.concurrency.drain.alpha.connector")
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+
+	// Queued requests are processed before the microservice shuts down
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := alpha.GET(ctx, "https://max.concurrency.drain.beta.connector/slow")
+			testarossa.NoError(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	err = beta.Shutdown()
+	testarossa.NoError(t, err)
+	wg.Wait()
+	testarossa.Equal(t, int32(4), handled.Load())
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -33,9 +34,11 @@ import (
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/lru"
+	"github.com/microbus-io/fabric/pub"
 	"github.com/microbus-io/fabric/rand"
 	"github.com/microbus-io/fabric/service"
 	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
 	"github.com/microbus-io/fabric/utils"
 
 	"github.com/nats-io/nats.go"
@@ -67,6 +70,8 @@ type Connector struct {
 	lifetimeCtx     context.Context
 	ctxCancel       context.CancelFunc
 	pendingOps      int32
+	draining        atomic.Bool
+	drainTimeout    time.Duration
 	onStartupCalled bool
 	initErr         error
 	startupTime     time.Time
@@ -80,12 +85,15 @@ type Connector struct {
 	tracer         trace.Tracer
 	traceProcessor *selectiveProcessor
 
-	natsConn        *nats.Conn
-	natsResponseSub *nats.Subscription
-	subs            map[string]*sub.Subscription
-	subsLock        sync.Mutex
-	started         atomic.Bool
-	plane           string
+	natsURL       string
+	transportConn *transport.Conn
+	responseSub   *transport.Subscription
+	subs          map[string]*sub.Subscription
+	subsLock      sync.Mutex
+	started       atomic.Bool
+	plane         string
+	signingKeys   [][]byte
+	strictSigning bool
 
 	reqs             utils.SyncMap[string, *transferChan]
 	networkHop       time.Duration
@@ -97,9 +105,19 @@ type Connector struct {
 	requestDefrags  utils.SyncMap[string, *httpx.DefragRequest]
 	responseDefrags utils.SyncMap[string, *httpx.DefragResponse]
 
-	knownResponders *lru.Cache[string, map[string]bool]
+	knownResponders *lru.Cache[string, map[string]string]
 	postRequestData *lru.Cache[string, string]
+	peerCompression *lru.Cache[string, string]
+	peerCBOR        *lru.Cache[string, bool]
 	localResponder  *lru.Cache[string, string]
+	discovered      *lru.Cache[string, *discoveredInstances]
+	announceSubbed  atomic.Bool
+	breakers        *lru.Cache[string, *circuitBreaker]
+	breakerPolicy   pub.BreakerPolicy
+
+	publishMiddleware pub.Chain
+	publishHandler    pub.Handler
+	subMiddleware     sub.Chain
 
 	configs         map[string]*cfg.Config
 	configLock      sync.Mutex
@@ -111,9 +129,23 @@ type Connector struct {
 	tickers     map[string]*tickerCallback
 	tickersLock sync.Mutex
 
+	healthChecks map[string]service.HealthCheckHandler
+	healthLock   sync.Mutex
+
+	versionWeights     map[string]map[int]int
+	versionWeightsLock sync.RWMutex
+
 	distribCache *dlru.Cache
 	resourcesFS  service.FS
 	stringBundle map[string]map[string]string
+
+	durableLock         sync.Mutex
+	durableStreamsReady bool
+
+	replicasLock      sync.Mutex
+	replicas          int
+	replicasCountedAt time.Time
+	replicasAsOf      time.Time
 }
 
 // NewConnector constructs a new Connector.
@@ -123,13 +155,20 @@ func NewConnector() *Connector {
 		configs:          map[string]*cfg.Config{},
 		networkHop:       250 * time.Millisecond,
 		ackTimeout:       250 * time.Millisecond,
+		drainTimeout:     8 * time.Second,
 		maxCallDepth:     64,
 		subs:             map[string]*sub.Subscription{},
 		tickers:          map[string]*tickerCallback{},
+		healthChecks:     map[string]service.HealthCheckHandler{},
+		versionWeights:   map[string]map[int]int{},
 		lifetimeCtx:      context.Background(),
-		knownResponders:  lru.NewCache[string, map[string]bool](),
+		knownResponders:  lru.NewCache[string, map[string]string](),
 		postRequestData:  lru.NewCache[string, string](),
+		peerCompression:  lru.NewCache[string, string](),
+		peerCBOR:         lru.NewCache[string, bool](),
 		localResponder:   lru.NewCache[string, string](),
+		discovered:       lru.NewCache[string, *discoveredInstances](),
+		breakers:         lru.NewCache[string, *circuitBreaker](),
 		multicastChanCap: 32,
 		metricDefs:       map[string]*metric{},
 	}
@@ -139,8 +178,15 @@ func NewConnector() *Connector {
 	c.knownResponders.SetMaxAge(24 * time.Hour)
 	c.postRequestData.SetMaxWeight(256 << 10) // 256KB
 	c.postRequestData.SetMaxAge(time.Minute)
+	c.peerCompression.SetMaxWeight(16 << 10) // 16KB
+	c.peerCompression.SetMaxAge(24 * time.Hour)
+	c.peerCBOR.SetMaxWeight(16 << 10) // 16KB
+	c.peerCBOR.SetMaxAge(24 * time.Hour)
 	c.localResponder.SetMaxWeight(16 << 10) // 16KB
 	c.localResponder.SetMaxAge(24 * time.Hour)
+	c.discovered.SetMaxWeight(1024)
+	c.discovered.SetMaxAge(time.Minute)
+	c.breakers.SetMaxWeight(4 << 10) // 4K destinations
 
 	c.newMetricsRegistry()
 
@@ -277,6 +323,17 @@ func (c *Connector) SetPlane(plane string) error {
 	return nil
 }
 
+// SetNATSURL sets the URL of the NATS server to connect to.
+// Explicitly setting a URL overrides any value specified by the MICROBUS_NATS environment variable.
+// Setting an empty value will clear this override.
+func (c *Connector) SetNATSURL(url string) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.natsURL = url
+	return nil
+}
+
 // SetLocality sets the geographic locality of the microservice which is used to optimize routing.
 // Localities are hierarchical with the more specific identifiers first, separated by dots.
 // It can be set to correlate to AWS regions such as "1.b.west.us", or arbitrarily to "rome.italy.europe" for example.
@@ -299,18 +356,41 @@ func (c *Connector) Locality() string {
 	return c.locality
 }
 
-// connectToNATS connects to the NATS cluster based on settings in environment variables
+// connectToNATS connects to the NATS cluster based on settings in environment variables,
+// then opens the transport over that connection.
+// If enabled, the transport short-circuits messages to microservices in the same process.
+// Short-circuiting is opt-in because microservices that predate it do not recognize messages
+// that were already delivered in memory, and would process them again.
 func (c *Connector) connectToNATS(ctx context.Context) error {
-	opts := []nats.Option{}
-
-	// Unique name to identify this connection
-	opts = append(opts, nats.Name(c.id+"."+c.hostname))
+	shortCircuit := false
+	if sc := env.Get("MICROBUS_SHORT_CIRCUIT"); sc != "" {
+		shortCircuit, _ = strconv.ParseBool(sc)
+	}
 
 	// URL
-	u := env.Get("MICROBUS_NATS")
+	u := c.natsURL
+	if u == "" {
+		u = env.Get("MICROBUS_NATS")
+	}
 	if u == "" {
 		u = "nats://127.0.0.1:4222"
 	}
+	if u == "none" || u == "embedded" {
+		// Short-circuit transport only.
+		// An embedded NATS server is started by the application, which overrides the URL of its microservices
+		tc, err := transport.Open(nil, true)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.LogInfo(ctx, "Connected to short-circuit transport")
+		c.transportConn = tc
+		return nil
+	}
+
+	opts := []nats.Option{}
+
+	// Unique name to identify this connection
+	opts = append(opts, nats.Name(c.id+"."+c.hostname))
 
 	// Credentials
 	user := env.Get("MICROBUS_NATS_USER")
@@ -363,7 +443,12 @@ func (c *Connector) connectToNATS(ctx context.Context) error {
 		)
 	})
 
-	c.natsConn = cn
+	tc, err := transport.Open(cn, shortCircuit)
+	if err != nil {
+		cn.Close()
+		return errors.Trace(err)
+	}
+	c.transportConn = tc
 	return nil
 }
 
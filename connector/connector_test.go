@@ -17,10 +17,14 @@ limitations under the License.
 package connector
 
 import (
+	"context"
+	"io"
+	"net/http"
 	"regexp"
 	"testing"
 
 	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/rand"
 	"github.com/microbus-io/testarossa"
 )
@@ -168,3 +172,38 @@ func TestConnector_Version(t *testing.T) {
 	err = con.SetVersion(123)
 	testarossa.Error(t, err)
 }
+
+func TestConnector_ShortCircuitWithoutNATS(t *testing.T) {
+	// No parallel
+
+	env.Push("MICROBUS_NATS", "none")
+	defer env.Pop("MICROBUS_NATS")
+
+	ctx := context.Background()
+
+	// Create the microservices
+	alpha := New("alpha.short.circuit.connector")
+	beta := New("beta.short.circuit.connector")
+	beta.Subscribe("GET", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(r.URL.Query().Get("x")))
+		return nil
+	})
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+	testarossa.Nil(t, alpha.transportConn.NATS())
+
+	// Requests are delivered in-process
+	res, err := alpha.GET(ctx, "https://beta.short.circuit.connector/echo?x=hello")
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "hello", string(b))
+	}
+	_, err = alpha.GET(ctx, "https://gamma.short.circuit.connector/echo")
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+}
@@ -30,6 +30,7 @@ func (c *Connector) subscribeControl() error {
 		path    string
 		handler HTTPHandler
 		options []sub.Option
+		noAll   bool // Not subscribed to the "all" host
 	}
 	subs := []*ctrlSub{
 		{
@@ -52,13 +53,43 @@ func (c *Connector) subscribeControl() error {
 			handler: c.handleTrace,
 			options: []sub.Option{sub.NoQueue()},
 		},
+		{
+			path:    "health",
+			handler: c.handleControlHealth,
+			options: []sub.Option{sub.NoQueue()},
+		},
+		{
+			path:    "ready",
+			handler: c.handleControlReady,
+			options: []sub.Option{sub.NoQueue()},
+		},
+		{
+			path:    "info",
+			handler: c.handleControlInfo,
+			options: []sub.Option{sub.NoQueue()},
+		},
+		{
+			path:    "lame-duck",
+			handler: c.handleControlLameDuck,
+			options: []sub.Option{sub.NoQueue()},
+			noAll:   true, // Draining must be addressed at the microservice or its instance rather than the entire fleet
+		},
+		{
+			path:    "evict",
+			handler: c.handleControlEvict,
+			options: []sub.Option{sub.NoQueue()},
+		},
 	}
+	// The subscriptions are activated by NATS by the time the connector completes its startup
 	for _, s := range subs {
-		err := c.Subscribe("ANY", ":888/"+s.path, s.handler, s.options...)
+		err := c.subscribe("ANY", ":888/"+s.path, s.handler, s.options...)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		err = c.Subscribe("ANY", "https://all:888/"+s.path, s.handler, s.options...)
+		if s.noAll {
+			continue
+		}
+		err = c.subscribe("ANY", "https://all:888/"+s.path, s.handler, s.options...)
 		if err != nil {
 			return errors.Trace(err)
 		}
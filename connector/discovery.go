@@ -0,0 +1,259 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/lru"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// Instance describes a live instance of a microservice, as discovered by Discover.
+type Instance struct {
+	ID            string                  `json:"id,omitempty"`
+	Host          string                  `json:"host,omitempty"`
+	Version       int                     `json:"version,omitempty"`
+	Locality      string                  `json:"locality,omitempty"`
+	Deployment    string                  `json:"deployment,omitempty"`
+	StartupTime   time.Time               `json:"startupTime,omitempty"`
+	Subscriptions []*InstanceSubscription `json:"subscriptions,omitempty"`
+}
+
+// Uptime is the duration of time since the instance started up.
+func (inst *Instance) Uptime() time.Duration {
+	if inst.StartupTime.IsZero() {
+		return 0
+	}
+	return time.Since(inst.StartupTime)
+}
+
+// InstanceSubscription describes an endpoint of an instance of a microservice.
+type InstanceSubscription struct {
+	Method string `json:"method,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Port   string `json:"port,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Queue  string `json:"queue,omitempty"`
+}
+
+// announcement is the payload of the :888/announce control request.
+type announcement struct {
+	Event    string    `json:"event"`
+	Instance *Instance `json:"instance"`
+}
+
+// discoveredInstances are the live instances of a hostname, as discovered by multicasting
+// the :888/info control request and as kept up to date by startup and shutdown announcements.
+type discoveredInstances struct {
+	instances map[string]*Instance
+	lock      sync.Mutex
+}
+
+/*
+Discover returns the live instances of the microservices at the hostname, or of all microservices if the hostname is "all".
+Each instance is described by its ID, version, locality, deployment, startup time and subscriptions.
+
+Discovery is performed by multicasting the :888/info control request. The results are cached for up to a minute,
+during which they are kept up to date by the announcements that microservices make on startup and on shutdown.
+Only microservices that called Discover listen to these announcements.
+*/
+func (c *Connector) Discover(ctx context.Context, host string) (instances []*Instance, err error) {
+	if err := utils.ValidateHostname(host); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.announceSubbed.CompareAndSwap(false, true) {
+		// Start listening to announcements before discovering to not miss any
+		err := c.Subscribe("ANY", "https://all:888/announce", c.handleControlAnnounce, sub.NoQueue())
+		if err != nil {
+			c.announceSubbed.Store(false)
+			return nil, errors.Trace(err)
+		}
+	}
+	key := strings.ToLower(host)
+	discovered, ok := c.discovered.Load(key, lru.NoBump())
+	if !ok {
+		discovered = &discoveredInstances{
+			instances: map[string]*Instance{},
+		}
+		for r := range c.Publish(ctx, pub.POST("https://"+host+":888/info"), pub.Body(struct{}{})) {
+			res, err := r.Get()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			var out struct {
+				Instance *Instance `json:"instance"`
+			}
+			err = json.NewDecoder(res.Body).Decode(&out)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if out.Instance != nil && out.Instance.ID != "" {
+				discovered.instances[out.Instance.ID] = out.Instance
+			}
+		}
+		c.discovered.Store(key, discovered)
+	}
+	discovered.lock.Lock()
+	for _, instance := range discovered.instances {
+		instances = append(instances, instance)
+	}
+	discovered.lock.Unlock()
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Host != instances[j].Host {
+			return instances[i].Host < instances[j].Host
+		}
+		return instances[i].ID < instances[j].ID
+	})
+	return instances, nil
+}
+
+// describe returns information about this instance of the microservice, including its subscriptions.
+// Control subscriptions on port 888 are excluded.
+func (c *Connector) describe() *Instance {
+	instance := &Instance{
+		ID:          c.id,
+		Host:        c.hostname,
+		Version:     c.version,
+		Locality:    c.locality,
+		Deployment:  c.deployment,
+		StartupTime: c.startupTime,
+	}
+	c.subsLock.Lock()
+	for _, s := range c.subs {
+		if s.Port == "888" {
+			continue
+		}
+		instance.Subscriptions = append(instance.Subscriptions, &InstanceSubscription{
+			Method: s.Method,
+			Host:   s.Host,
+			Port:   s.Port,
+			Path:   s.Path,
+			Queue:  s.Queue,
+		})
+	}
+	c.subsLock.Unlock()
+	sort.Slice(instance.Subscriptions, func(i, j int) bool {
+		a, b := instance.Subscriptions[i], instance.Subscriptions[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return instance
+}
+
+// announce informs the microservices that discovered this one's hostname that this instance has started up or is shutting down.
+// The announcement is published without awaiting acks or responses so as to not delay the startup or shutdown.
+func (c *Connector) announce(event string) error {
+	body, err := json.Marshal(announcement{
+		Event:    event,
+		Instance: c.describe(),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpReq, err := http.NewRequest("POST", "https://all:888/announce", bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	// Stop the http package from setting Go-http-client/1.1 as the user-agent
+	httpReq.Header.Set("User-Agent", "")
+	f := frame.Of(httpReq)
+	f.SetCallDepth(1)
+	f.SetFromHost(c.hostname)
+	f.SetFromID(c.id)
+	f.SetFromVersion(c.version)
+	f.SetOpCode(frame.OpCodeRequest)
+	f.SetMessageID(rand.AlphaNum64(8))
+	var buf bytes.Buffer
+	err = httpReq.WriteProxy(&buf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subject := subjectOfRequest(c.plane, "POST", "all", "888", "/announce")
+	err = c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
+	return errors.Trace(err)
+}
+
+// handleControlInfo responds to the :888/info control request with information about this instance of the microservice.
+func (c *Connector) handleControlInfo(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(struct {
+		Instance *Instance `json:"instance"`
+	}{
+		Instance: c.describe(),
+	})
+	return errors.Trace(err)
+}
+
+// handleControlAnnounce responds to the :888/announce control request
+// by updating the discovered instances with the instance that started up or is shutting down.
+func (c *Connector) handleControlAnnounce(w http.ResponseWriter, r *http.Request) error {
+	var in announcement
+	err := httpx.ParseRequestData(r, &in)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if in.Instance == nil || in.Instance.ID == "" || in.Instance.Host == "" {
+		return errors.Newc(http.StatusBadRequest, "missing instance")
+	}
+	switch in.Event {
+	case "startup", "shutdown":
+	default:
+		return errors.Newcf(http.StatusBadRequest, "invalid event '%s'", in.Event)
+	}
+	for _, key := range []string{strings.ToLower(in.Instance.Host), "all"} {
+		discovered, ok := c.discovered.Load(key, lru.NoBump())
+		if !ok {
+			continue
+		}
+		discovered.lock.Lock()
+		if in.Event == "startup" {
+			discovered.instances[in.Instance.ID] = in.Instance
+		} else {
+			delete(discovered.instances, in.Instance.ID)
+		}
+		discovered.lock.Unlock()
+	}
+	if in.Event == "shutdown" {
+		c.evictResponder(in.Instance.ID, in.Instance.Host)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return nil
+}
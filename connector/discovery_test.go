@@ -0,0 +1,120 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/lru"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Discover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	randomPlane := rand.AlphaNum64(12) // Isolate from other tests when discovering "all"
+
+	// Create the microservices
+	newAlpha := func() *Connector {
+		alpha := New("alpha.discover.connector")
+		alpha.SetPlane(randomPlane)
+		alpha.SetVersion(7)
+		alpha.Subscribe("GET", "hello", func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+		return alpha
+	}
+	alpha1 := newAlpha()
+	alpha2 := newAlpha()
+	alpha3 := newAlpha()
+	beta := New("beta.discover.connector")
+	beta.SetPlane(randomPlane)
+
+	// Startup the microservices
+	err := alpha1.Startup()
+	testarossa.NoError(t, err)
+	defer alpha1.Shutdown()
+	err = alpha2.Startup()
+	testarossa.NoError(t, err)
+	defer alpha2.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Discover the replicas
+	instances, err := beta.Discover(ctx, "alpha.discover.connector")
+	if testarossa.NoError(t, err) && testarossa.Equal(t, 2, len(instances)) {
+		for _, instance := range instances {
+			testarossa.True(t, instance.ID == alpha1.ID() || instance.ID == alpha2.ID())
+			testarossa.Equal(t, "alpha.discover.connector", instance.Host)
+			testarossa.Equal(t, 7, instance.Version)
+			testarossa.Equal(t, TESTING, instance.Deployment)
+			testarossa.True(t, instance.Uptime() > 0)
+			if testarossa.Equal(t, 1, len(instance.Subscriptions)) {
+				testarossa.Equal(t, "GET", instance.Subscriptions[0].Method)
+				testarossa.Equal(t, "443", instance.Subscriptions[0].Port)
+				testarossa.Equal(t, "/hello", instance.Subscriptions[0].Path)
+				testarossa.Equal(t, "alpha.discover.connector", instance.Subscriptions[0].Queue)
+			}
+		}
+	}
+	instances, err = beta.Discover(ctx, "all")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, 3, len(instances))
+	}
+
+	// The startup of a replica is announced
+	err = alpha3.Startup()
+	testarossa.NoError(t, err)
+	time.Sleep(100 * time.Millisecond) // Give time for the announcement
+	cached, ok := beta.discovered.Load("alpha.discover.connector", lru.NoBump())
+	if testarossa.True(t, ok) {
+		testarossa.Equal(t, 3, len(cached.instances))
+	}
+	instances, err = beta.Discover(ctx, "alpha.discover.connector")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, 3, len(instances))
+	}
+	instances, err = beta.Discover(ctx, "all")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, 4, len(instances))
+	}
+
+	// The shutdown of a replica is announced
+	err = alpha3.Shutdown()
+	testarossa.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	instances, err = beta.Discover(ctx, "alpha.discover.connector")
+	if testarossa.NoError(t, err) && testarossa.Equal(t, 2, len(instances)) {
+		for _, instance := range instances {
+			testarossa.NotEqual(t, alpha3.ID(), instance.ID)
+		}
+	}
+	instances, err = beta.Discover(ctx, "all")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, 3, len(instances))
+	}
+
+	// Invalid hostname
+	_, err = beta.Discover(ctx, "invalid host")
+	testarossa.Error(t, err)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/transport"
+	"github.com/microbus-io/fabric/trc"
+)
+
+// SetDrainTimeout sets the time allotted to the pending operations of the microservice to complete when it is drained.
+// The default is 8 seconds.
+func (c *Connector) SetDrainTimeout(timeout time.Duration) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	if timeout < 0 {
+		return c.captureInitErr(errors.Newf("negative timeout '%v'", timeout))
+	}
+	c.drainTimeout = timeout
+	return nil
+}
+
+/*
+Drain puts the microservice in lame-duck mode in preparation for its shutdown.
+It stops taking new load-balanced requests and running tickers, informs the other microservices
+to no longer route requests to this instance, and then waits for pending operations to complete,
+up to the drain timeout.
+
+Pervasive subscriptions remain active, as do the subscriptions that receive the remaining fragments
+of requests that are already in flight. Drain returns immediately if the microservice is already draining.
+*/
+func (c *Connector) Drain() (err error) {
+	if !c.IsStarted() {
+		return errors.New("not started")
+	}
+	if !c.draining.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	// OpenTelemetry: create a span for the callback
+	ctx, span := c.StartSpan(c.lifetimeCtx, "drain", trc.Internal())
+	defer span.End()
+	c.LogInfo(ctx, "Draining")
+
+	// Stop all tickers
+	err = c.stopTickers()
+	if err != nil {
+		err = errors.Trace(err)
+	}
+
+	// Stop taking new load-balanced requests
+	c.subsLock.Lock()
+	for _, s := range c.subs {
+		if s.Queue == "" || len(s.Subs) == 0 {
+			continue
+		}
+		if s.Durable != nil {
+			c.deactivateSub(s)
+			continue
+		}
+		// The transport subscription that is addressed to the ID of this instance
+		// is kept active to receive the remaining fragments of requests that are in flight
+		idSubject := subjectOfSubscription(c.plane, s.Method, c.id+"."+s.Host, s.Port, s.Path)
+		var kept []*transport.Subscription
+		for _, transportSub := range s.Subs {
+			if transportSub.Subject() == idSubject {
+				kept = append(kept, transportSub)
+			} else {
+				transportSub.Unsubscribe()
+			}
+		}
+		s.Subs = kept
+	}
+	c.subsLock.Unlock()
+	time.Sleep(20 * time.Millisecond) // Give time for subscription deactivation by NATS
+
+	// Inform the other microservices to evict this instance from their caches
+	for range c.Publish(ctx, pub.POST("https://all:888/evict"), pub.Body(evictRequest{ID: c.id, Host: c.hostname})) {
+	}
+
+	// Drain pending operations (incoming requests, running tickers, goroutines)
+	totalDrainTime := time.Duration(0)
+	for atomic.LoadInt32(&c.pendingOps) > 0 && totalDrainTime < c.drainTimeout {
+		time.Sleep(20 * time.Millisecond)
+		totalDrainTime += 20 * time.Millisecond
+	}
+	undrained := atomic.LoadInt32(&c.pendingOps)
+	if undrained > 0 {
+		c.LogWarn(ctx, "Unable to drain pending operations",
+			"ops", int(undrained),
+		)
+	}
+	c.LogInfo(ctx, "Drained")
+	return err
+}
+
+// IsDraining indicates if the microservice is in lame-duck mode in preparation for its shutdown.
+func (c *Connector) IsDraining() bool {
+	return c.draining.Load()
+}
+
+// handleControlLameDuck responds to the :888/lame-duck control request
+// by draining the microservice in preparation for its shutdown.
+func (c *Connector) handleControlLameDuck(w http.ResponseWriter, r *http.Request) error {
+	// Drain in a goroutine because the pending operations include this very request
+	go func() {
+		err := c.Drain()
+		if err != nil {
+			c.LogError(context.Background(), "Draining", "error", err)
+		}
+	}()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return nil
+}
+
+// evictRequest is the payload of the :888/evict control request.
+type evictRequest struct {
+	ID   string `json:"id"`
+	Host string `json:"host"`
+}
+
+// handleControlEvict responds to the :888/evict control request
+// by evicting a draining instance of another microservice from the caches of the known responders.
+func (c *Connector) handleControlEvict(w http.ResponseWriter, r *http.Request) error {
+	var in evictRequest
+	err := httpx.ParseRequestData(r, &in)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if in.ID != "" && in.Host != "" {
+		c.evictResponder(in.ID, in.Host)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return nil
+}
+
+// evictResponder removes the instance of a microservice from the caches of the known responders.
+// Multicast requests will therefore not expect a response from the instance, and
+// locality-aware routing will rediscover the best locality of the microservice.
+// The instance is matched by its ID because the name of the queue it responded in
+// is the hostname of the microservice, unless it subscribed with no queue or to a named queue.
+func (c *Connector) evictResponder(id string, host string) {
+	for subject, responders := range c.knownResponders.ToMap() {
+		for _, responderID := range responders {
+			if responderID == id {
+				c.knownResponders.Delete(subject)
+				break
+			}
+		}
+	}
+	host = strings.ToLower(host)
+	c.localResponder.DeletePredicate(func(key string) bool {
+		u, err := httpx.ParseURL(key)
+		return err == nil && strings.ToLower(u.Hostname()) == host
+	})
+}
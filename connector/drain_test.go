@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Drain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	randomPlane := rand.AlphaNum64(12) // Isolate the eviction multicast from other tests
+
+	// Create the microservices
+	slowHandler := func(w http.ResponseWriter, r *http.Request) error {
+		time.Sleep(500 * time.Millisecond)
+		return nil
+	}
+	alpha1 := New("drain.connector")
+	alpha1.SetPlane(randomPlane)
+	alpha1.Subscribe("GET", "slow", slowHandler)
+	alpha1.Subscribe("GET", "pervasive", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}, sub.NoQueue())
+	alpha1.SetDrainTimeout(4 * time.Second)
+
+	alpha2 := New("drain.connector")
+	alpha2.SetPlane(randomPlane)
+	alpha2.Subscribe("GET", "slow", slowHandler)
+	alpha2.Subscribe("GET", "pervasive", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}, sub.NoQueue())
+
+	beta := New("beta.drain.connector")
+	beta.SetPlane(randomPlane)
+
+	// Startup the microservices
+	err := alpha1.Startup()
+	testarossa.NoError(t, err)
+	defer alpha1.Shutdown()
+	err = alpha2.Startup()
+	testarossa.NoError(t, err)
+	defer alpha2.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Multicast to cache the known responders
+	subject := subjectOfRequest(beta.plane, "GET", "drain.connector", "443", "/pervasive")
+	count := 0
+	for r := range beta.Publish(ctx, pub.GET("https://drain.connector/pervasive")) {
+		_, err := r.Get()
+		testarossa.NoError(t, err)
+		count++
+	}
+	testarossa.Equal(t, 2, count)
+	responders, ok := beta.knownResponders.Load(subject)
+	testarossa.True(t, ok)
+	testarossa.Equal(t, alpha1.id, responders[alpha1.id+"."+alpha1.hostname])
+
+	// Send a slow request to the first replica
+	done := make(chan bool)
+	go func() {
+		_, err := beta.Request(ctx, pub.GET("https://"+alpha1.id+".drain.connector/slow"))
+		testarossa.NoError(t, err)
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Drain should wait for the pending request to complete
+	t0 := time.Now()
+	err = alpha1.Drain()
+	testarossa.NoError(t, err)
+	testarossa.True(t, alpha1.IsDraining())
+	testarossa.True(t, time.Since(t0) >= 300*time.Millisecond)
+	select {
+	case <-done:
+	default:
+		testarossa.FailIf(t, true, "pending request not completed")
+	}
+
+	// The drained replica should have been evicted from the known responders
+	_, ok = beta.knownResponders.Load(subject)
+	testarossa.False(t, ok)
+
+	// Load-balanced requests should be routed to the second replica only
+	for i := 0; i < 8; i++ {
+		res, err := beta.Request(ctx, pub.GET("https://drain.connector/slow"))
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, alpha2.id, frame.Of(res).FromID())
+		}
+	}
+
+	// Draining again should return immediately
+	t0 = time.Now()
+	err = alpha1.Drain()
+	testarossa.NoError(t, err)
+	testarossa.True(t, time.Since(t0) < 100*time.Millisecond)
+
+	// Shutdown should reset the draining state
+	err = alpha1.Shutdown()
+	testarossa.NoError(t, err)
+	testarossa.False(t, alpha1.IsDraining())
+}
+
+func TestConnector_DrainEvictsQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	randomPlane := rand.AlphaNum64(12) // Isolate the eviction multicast from other tests
+
+	// Create the microservices
+	alpha := New("drain.evicts.queue.connector")
+	alpha.SetPlane(randomPlane)
+	alpha.Subscribe("GET", "queued", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+	beta := New("beta.drain.evicts.queue.connector")
+	beta.SetPlane(randomPlane)
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Multicast to cache the known responders
+	// The responder is known by the name of its default queue, which is the hostname of the microservice
+	subject := subjectOfRequest(beta.plane, "GET", "drain.evicts.queue.connector", "443", "/queued")
+	for r := range beta.Publish(ctx, pub.GET("https://drain.evicts.queue.connector/queued")) {
+		_, err := r.Get()
+		testarossa.NoError(t, err)
+	}
+	responders, ok := beta.knownResponders.Load(subject)
+	testarossa.True(t, ok)
+	testarossa.Equal(t, alpha.id, responders[alpha.hostname])
+
+	// The drained instance should have been evicted from the known responders
+	err = alpha.Drain()
+	testarossa.NoError(t, err)
+	_, ok = beta.knownResponders.Load(subject)
+	testarossa.False(t, ok)
+}
+
+func TestConnector_DrainControl(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("drain.control.connector")
+	con.SetPlane(rand.AlphaNum64(12)) // Isolate the eviction multicast from other tests
+	con.Subscribe("GET", "hello", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	_, err = con.Request(ctx, pub.GET("https://drain.control.connector/hello"))
+	testarossa.NoError(t, err)
+
+	// The entire fleet cannot be drained at once
+	_, err = con.Request(ctx, pub.GET("https://all:888/lame-duck"))
+	testarossa.Error(t, err)
+	testarossa.False(t, con.IsDraining())
+
+	// Drain using the control endpoint addressed to the instance
+	_, err = con.Request(ctx, pub.GET("https://"+con.ID()+".drain.control.connector:888/lame-duck"))
+	testarossa.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	testarossa.True(t, con.IsDraining())
+
+	// Load-balanced requests are no longer accepted
+	_, err = con.Request(ctx, pub.GET("https://drain.control.connector/hello"))
+	testarossa.Error(t, err)
+
+	// Control requests are still accepted
+	_, err = con.Request(ctx, pub.GET("https://drain.control.connector:888/ping"))
+	testarossa.NoError(t, err)
+}
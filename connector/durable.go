@@ -0,0 +1,258 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
+	"github.com/microbus-io/fabric/trc"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// durableMaxAge is the duration that durable requests and dead letters are kept in JetStream
+	durableMaxAge = 7 * 24 * time.Hour
+	// durableTimeBudget is the time budget of the handler of a durable request
+	durableTimeBudget = 20 * time.Second
+)
+
+// ensureDurableStreams creates the JetStream streams of durable requests and of dead letters, if they do not already exist.
+// A durable request is kept until all durable subscriptions acknowledge it, whereas a dead letter is kept until it expires.
+func (c *Connector) ensureDurableStreams() error {
+	c.durableLock.Lock()
+	defer c.durableLock.Unlock()
+	if c.durableStreamsReady {
+		return nil
+	}
+	err := c.transportConn.EnsureStream(c.plane+"_durable", []string{c.plane + ".d.>"}, true, durableMaxAge)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = c.transportConn.EnsureStream(c.plane+"_deadletter", []string{c.plane + ".dl.>"}, false, durableMaxAge)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.durableStreamsReady = true
+	return nil
+}
+
+// publishDurable stores the request in JetStream for at-least-once delivery to the durable subscriptions of its URL.
+func (c *Connector) publishDurable(ctx context.Context, req *pub.Request) error {
+	err := c.ensureDurableStreams()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	httpReq, data, err := c.serializeRequest(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	msgID := frame.Of(httpReq).MessageID()
+
+	port := "443"
+	if httpReq.URL.Scheme == "http" {
+		port = "80"
+	}
+	if httpReq.URL.Port() != "" {
+		port = httpReq.URL.Port()
+	}
+	subject := subjectOfDurableRequest(c.plane, httpReq.Method, httpReq.URL.Hostname(), port, httpReq.URL.Path)
+
+	c.LogDebug(ctx, "Durable request",
+		"msg", msgID,
+		"url", req.Canonical(),
+		"method", req.Method,
+	)
+	err = c.transportConn.PublishDurable(subject, c.signMessage(subject, data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// durableConsumerName is the name of the JetStream consumer that is shared by the members of the queue of a durable subscription.
+func durableConsumerName(s *sub.Subscription) string {
+	name := []byte(s.Queue + "_" + s.Method + "_" + s.Host + "_" + s.Port + s.Path)
+	for i, ch := range name {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_') {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// activateDurableSub subscribes to the durable requests stored in JetStream.
+func (c *Connector) activateDurableSub(s *sub.Subscription) error {
+	err := c.ensureDurableStreams()
+	if err == nil {
+		tb := newTokenBucket(s)
+		cl := newConcurrencyLimiter(s)
+		var transportSub *transport.Subscription
+		transportSub, err = c.transportConn.DurableSubscribe(
+			subjectOfDurableSubscription(c.plane, s.Method, s.Host, s.Port, s.Path),
+			transport.DurableOptions{
+				Stream:        c.plane + "_durable",
+				Consumer:      durableConsumerName(s),
+				Queue:         s.Queue,
+				MaxDeliveries: s.Durable.MaxDeliveries,
+				Backoff:       s.Durable.Backoff,
+				AckWait:       durableTimeBudget + c.networkHop,
+				DeadLetterSubject: func(subject string) string {
+					return subjectOfDeadLetter(c.plane, subject)
+				},
+			},
+			func(msg *transport.Msg) error {
+				return c.handleDurableRequest(msg, s, tb, cl)
+			},
+		)
+		if err == nil {
+			s.Subs = append(s.Subs, transportSub)
+		}
+	}
+	if err != nil {
+		c.LogError(c.lifetimeCtx, "Activating durable sub",
+			"error", err,
+			"url", s.Canonical(),
+			"method", s.Method,
+		)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// handleDurableRequest is called when a durable request is delivered from JetStream.
+// The request is dispatched to the web handler of the subscription and the response is discarded.
+// The request is redelivered if the handler returns an error or if it is not admitted.
+func (c *Connector) handleDurableRequest(msg *transport.Msg, s *sub.Subscription, tb *tokenBucket, cl *concurrencyLimiter) error {
+	atomic.AddInt32(&c.pendingOps, 1)
+	defer atomic.AddInt32(&c.pendingOps, -1)
+
+	// Verify the signature, dropping the request without redelivery if it fails
+	err := c.verifyMessage(msg.Subject, msg.Data, durableMaxAge)
+	if err != nil {
+		c.LogWarn(c.lifetimeCtx, "Dropping durable request",
+			"error", err,
+			"subject", msg.Subject,
+		)
+		return nil
+	}
+
+	// Parse the request
+	httpReq, err := http.ReadRequest(bufio.NewReaderSize(bytes.NewReader(msg.Data), 64))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Remove the default user-agent set by the http package
+	if httpReq.Header.Get("User-Agent") == "Go-http-client/1.1" {
+		httpReq.Header.Del("User-Agent")
+	}
+	// The signature was verified on arrival and must not be passed along
+	httpReq.Header.Del(frame.HeaderSignature)
+	encryptionKeyID, err := c.decryptRequest(httpReq)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	c.LogDebug(c.lifetimeCtx, "Handling durable",
+		"msg", frame.Of(httpReq).MessageID(),
+		"url", s.Canonical(),
+		"method", s.Method,
+	)
+
+	// OpenTelemetry: create a child span
+	spanOptions := []trc.Option{
+		trc.Server(),
+	}
+	if c.deployment == LOCAL {
+		spanOptions = append(spanOptions, trc.Request(httpReq), trc.String("http.route", s.Path))
+	}
+	ctx := propagation.TraceContext{}.Extract(c.lifetimeCtx, propagation.HeaderCarrier(httpReq.Header))
+	ctx, span := c.StartSpan(ctx, fmt.Sprintf(":%s%s", s.Port, s.Path), spanOptions...)
+	defer span.End()
+
+	// Prepare the context
+	ctx = frame.ContextWithFrameOf(ctx, httpReq.Header)
+	ctx, cancel := context.WithTimeout(ctx, durableTimeBudget)
+	defer cancel()
+	httpReq = httpReq.WithContext(ctx)
+
+	// Call the handler once the request is admitted
+	handlerStartTime := time.Now()
+	httpRecorder := httpx.NewResponseRecorder()
+	var handlerErr error
+	if s.Encrypted && encryptionKeyID == "" {
+		handlerErr = errors.Newc(http.StatusBadRequest, "encryption required")
+	} else {
+		_, handlerErr = c.admitRequest(ctx, s, tb, cl)
+	}
+	if handlerErr == nil {
+		handlerErr = c.callHandler(httpRecorder, httpReq, s)
+		c.releaseRequest(s, cl)
+	}
+	statusCode := httpRecorder.StatusCode()
+	if handlerErr != nil {
+		handlerErr = errors.Convert(handlerErr)
+		statusCode = errors.Convert(handlerErr).StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		c.LogError(ctx, "Handling durable request",
+			"error", handlerErr,
+			"path", s.Path,
+			"code", statusCode,
+		)
+
+		// OpenTelemetry: record the error, adding the request attributes
+		span.SetString("http.route", s.Path)
+		span.SetRequest(httpReq)
+		span.SetError(handlerErr)
+		c.ForceTrace(ctx)
+	} else {
+		span.SetOK(statusCode)
+	}
+
+	// Meter
+	_ = c.ObserveMetric(
+		"microbus_response_duration_seconds",
+		time.Since(handlerStartTime).Seconds(),
+		s.Canonical(),
+		s.Port,
+		httpReq.Method,
+		strconv.Itoa(statusCode),
+		func() string {
+			if handlerErr != nil {
+				return "ERROR"
+			}
+			return "OK"
+		}(),
+	)
+	return handlerErr
+}
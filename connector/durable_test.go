@@ -0,0 +1,232 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
+	"github.com/microbus-io/testarossa"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// startJetStreamServer starts an embedded NATS server with JetStream enabled.
+func startJetStreamServer(t *testing.T) *server.Server {
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	testarossa.NoError(t, err)
+	ns.Start()
+	testarossa.True(t, ns.ReadyForConnections(10*time.Second))
+	return ns
+}
+
+func TestConnector_Durable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ns := startJetStreamServer(t)
+	defer ns.Shutdown()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.durable.connector")
+	alpha.SetPlane(plane)
+	alpha.SetNATSURL(ns.ClientURL())
+
+	var count atomic.Int32
+	var lastValue atomic.Value
+	beta := New("beta.durable.connector")
+	beta.SetPlane(plane)
+	beta.SetNATSURL(ns.ClientURL())
+	beta.Subscribe("POST", "https://alpha.durable.connector:417/on-event", func(w http.ResponseWriter, r *http.Request) error {
+		count.Add(1)
+		lastValue.Store(r.URL.Query().Get("x"))
+		return nil
+	}, sub.Durable())
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+
+	// Durable requests return no responses
+	ch := alpha.Publish(ctx, pub.POST("https://alpha.durable.connector:417/on-event?x=1"), pub.Durable())
+	testarossa.Zero(t, len(ch))
+	time.Sleep(250 * time.Millisecond)
+	testarossa.Equal(t, int32(1), count.Load())
+	testarossa.Equal(t, "1", lastValue.Load())
+
+	// Requests published while the subscriber is down are delivered when it restarts
+	err = beta.Shutdown()
+	testarossa.NoError(t, err)
+	for r := range alpha.Publish(ctx, pub.POST("https://alpha.durable.connector:417/on-event?x=2"), pub.Durable()) {
+		_, err := r.Get()
+		testarossa.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	testarossa.Equal(t, int32(1), count.Load())
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+	time.Sleep(250 * time.Millisecond)
+	testarossa.Equal(t, int32(2), count.Load())
+	testarossa.Equal(t, "2", lastValue.Load())
+
+	// Non-durable requests do not reach the durable subscription
+	for r := range alpha.Publish(ctx, pub.POST("https://alpha.durable.connector:417/on-event?x=3")) {
+		_, err := r.Get()
+		testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	}
+	testarossa.Equal(t, int32(2), count.Load())
+}
+
+func TestConnector_DurableRedelivery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ns := startJetStreamServer(t)
+	defer ns.Shutdown()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.durable.redelivery.connector")
+	alpha.SetPlane(plane)
+	alpha.SetNATSURL(ns.ClientURL())
+
+	var count atomic.Int32
+	var succeeded atomic.Bool
+	beta := New("beta.durable.redelivery.connector")
+	beta.SetPlane(plane)
+	beta.SetNATSURL(ns.ClientURL())
+	beta.Subscribe("POST", "https://alpha.durable.redelivery.connector:417/on-event", func(w http.ResponseWriter, r *http.Request) error {
+		if count.Add(1) < 3 {
+			return errors.New("failing")
+		}
+		succeeded.Store(true)
+		return nil
+	}, sub.Redelivery(5, 50*time.Millisecond))
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// The request is redelivered until the handler succeeds
+	alpha.Publish(ctx, pub.POST("https://alpha.durable.redelivery.connector:417/on-event"), pub.Durable())
+	for i := 0; i < 20 && !succeeded.Load(); i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	testarossa.True(t, succeeded.Load())
+	time.Sleep(250 * time.Millisecond)
+	testarossa.Equal(t, int32(3), count.Load())
+}
+
+func TestConnector_DurableDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ns := startJetStreamServer(t)
+	defer ns.Shutdown()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.durable.dead.letter.connector")
+	alpha.SetPlane(plane)
+	alpha.SetNATSURL(ns.ClientURL())
+
+	var count atomic.Int32
+	beta := New("beta.durable.dead.letter.connector")
+	beta.SetPlane(plane)
+	beta.SetNATSURL(ns.ClientURL())
+	beta.Subscribe("POST", "https://alpha.durable.dead.letter.connector:417/on-event", func(w http.ResponseWriter, r *http.Request) error {
+		count.Add(1)
+		return errors.New("always failing")
+	}, sub.Redelivery(2, 10*time.Millisecond))
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Listen to the dead-letter subject
+	nc, err := nats.Connect(ns.ClientURL())
+	testarossa.NoError(t, err)
+	defer nc.Close()
+	deadLetters := make(chan *nats.Msg, 8)
+	_, err = nc.ChanSubscribe(plane+".dl.>", deadLetters)
+	testarossa.NoError(t, err)
+
+	// The request is moved to the dead-letter subject after its deliveries are exhausted
+	alpha.Publish(ctx, pub.POST("https://alpha.durable.dead.letter.connector:417/on-event"), pub.Durable())
+	select {
+	case msg := <-deadLetters:
+		testarossa.Equal(t, subjectOfDeadLetter(plane, subjectOfDurableRequest(plane, "POST", "alpha.durable.dead.letter.connector", "417", "/on-event")), msg.Subject)
+		testarossa.Equal(t, "always failing", msg.Header.Get(transport.HeaderDeadLetterError))
+		testarossa.Equal(t, "beta.durable.dead.letter.connector", msg.Header.Get(transport.HeaderDeadLetterQueue))
+		testarossa.Equal(t, strconv.Itoa(2), msg.Header.Get(transport.HeaderDeadLetterDeliveries))
+	case <-time.After(2 * time.Second):
+		testarossa.FailIf(t, true, "dead letter not received")
+	}
+	time.Sleep(100 * time.Millisecond)
+	testarossa.Equal(t, int32(2), count.Load())
+}
+
+func TestConnector_DurableWithoutNATS(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	con := New("durable.without.nats.connector")
+	con.SetNATSURL("none")
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Durable delivery is not available without NATS
+	for r := range con.Publish(ctx, pub.POST("https://durable.without.nats.connector:417/on-event"), pub.Durable()) {
+		_, err := r.Get()
+		testarossa.Error(t, err)
+	}
+	err = con.Subscribe("POST", ":417/on-event", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}, sub.Durable())
+	testarossa.Error(t, err)
+}
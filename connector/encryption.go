@@ -0,0 +1,243 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+const (
+	// payloadKeysConfig is the name of the secret config that holds the keys with which payloads are encrypted
+	payloadKeysConfig = "PayloadKeys"
+	// payloadKeysDescription is the description of the PayloadKeys config
+	payloadKeysDescription = "PayloadKeys are the secret keys with which the bodies of encrypted requests and responses are encrypted, separated by commas or spaces. The first key encrypts. All keys are accepted when decrypting."
+)
+
+// payloadKey is an AES-256-GCM key derived from a secret.
+type payloadKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// newPayloadKey derives an AES-256-GCM key from a secret of any length.
+// The identifier of the key is derived from the key, so that it can be sent along with the ciphertext without revealing the key.
+func newPayloadKey(secret []byte) (*payloadKey, error) {
+	key := sha256.Sum256(secret)
+	id := sha256.Sum256(key[:])
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &payloadKey{
+		id:   hex.EncodeToString(id[:4]),
+		aead: aead,
+	}, nil
+}
+
+// payloadKeys returns the keys in the PayloadKeys config.
+func (c *Connector) payloadKeys() (keys []*payloadKey, err error) {
+	for _, secret := range parseKeys(c.Config(payloadKeysConfig)) {
+		key, err := newPayloadKey(secret)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// payloadAAD returns the additional data that is authenticated along with an encrypted body.
+// It binds the ciphertext to the message ID, fragment index, fragment count and op code of its message,
+// so that it cannot be replayed in another message, fragment or direction, nor truncate or extend its message.
+// The fragment count of a streamed message is zero until its final fragment.
+func payloadAAD(header http.Header) []byte {
+	f := frame.Of(header)
+	index, max := f.Fragment()
+	return []byte(f.MessageID() + "\x00" + strconv.Itoa(index) + "\x00" + strconv.Itoa(max) + "\x00" + f.OpCode())
+}
+
+// encryptBody encrypts the body with the identified payload key, or the first key if none is identified,
+// and identifies the key in the header. The nonce is prepended to the ciphertext.
+func (c *Connector) encryptBody(header http.Header, body io.Reader, keyID string) (ciphertext []byte, err error) {
+	keys, err := c.payloadKeys()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.Newf("config '%s' is not set", payloadKeysConfig)
+	}
+	key := keys[0]
+	if keyID != "" {
+		key = nil
+		for _, k := range keys {
+			if k.id == keyID {
+				key = k
+				break
+			}
+		}
+		if key == nil {
+			return nil, errors.Newf("unknown payload key '%s'", keyID)
+		}
+	}
+	var plaintext []byte
+	if body != nil {
+		plaintext, err = io.ReadAll(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	aead := key.aead
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ciphertext = aead.Seal(nonce, nonce, plaintext, payloadAAD(header))
+	frame.Of(header).SetEncryption(key.id)
+	header.Set("Content-Length", strconv.Itoa(len(ciphertext)))
+	return ciphertext, nil
+}
+
+// decryptBody decrypts the body with the payload key identified in the header, then removes the identifier from the header.
+func (c *Connector) decryptBody(header http.Header, body io.Reader) (plaintext []byte, err error) {
+	keyID := frame.Of(header).Encryption()
+	keys, err := c.payloadKeys()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var key *payloadKey
+	for _, k := range keys {
+		if k.id == keyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		return nil, errors.Newf("unknown payload key '%s'", keyID)
+	}
+	var ciphertext []byte
+	if body != nil {
+		ciphertext, err = io.ReadAll(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	nonceSize := key.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("malformed ciphertext")
+	}
+	plaintext, err = key.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], payloadAAD(header))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	frame.Of(header).SetEncryption("")
+	header.Set("Content-Length", strconv.Itoa(len(plaintext)))
+	return plaintext, nil
+}
+
+// encryptRequest encrypts the body of a fragment of a request with the first of the payload keys.
+func (c *Connector) encryptRequest(r *http.Request) error {
+	ciphertext, err := c.encryptBody(r.Header, r.Body, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.Body = httpx.NewBodyReader(ciphertext)
+	r.ContentLength = int64(len(ciphertext))
+	return nil
+}
+
+// decryptRequest decrypts the body of a fragment of a request, if it is encrypted,
+// and returns the identifier of the key with which it was encrypted.
+func (c *Connector) decryptRequest(r *http.Request) (keyID string, err error) {
+	keyID = frame.Of(r).Encryption()
+	if keyID == "" {
+		return "", nil
+	}
+	plaintext, err := c.decryptBody(r.Header, r.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	r.Body = httpx.NewBodyReader(plaintext)
+	r.ContentLength = int64(len(plaintext))
+	return keyID, nil
+}
+
+// encryptResponse encrypts the body of a fragment of a response with the identified payload key.
+// The response is encrypted with the same key as its request, which the caller is known to have.
+func (c *Connector) encryptResponse(res *http.Response, keyID string) error {
+	ciphertext, err := c.encryptBody(res.Header, res.Body, keyID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res.Body = httpx.NewBodyReader(ciphertext)
+	res.ContentLength = int64(len(ciphertext))
+	return nil
+}
+
+// decryptResponse decrypts the body of a fragment of a response, if it is encrypted.
+func (c *Connector) decryptResponse(res *http.Response) error {
+	if frame.Of(res).Encryption() == "" {
+		return nil
+	}
+	plaintext, err := c.decryptBody(res.Header, res.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res.Body = httpx.NewBodyReader(plaintext)
+	res.ContentLength = int64(len(plaintext))
+	return nil
+}
+
+// undecryptableResponse turns a fragment of a response that failed to decrypt into an error response,
+// so that the error is delivered to the caller.
+// The error terminates a streamed response that is already under way, or otherwise takes the place of the entire response.
+func (c *Connector) undecryptableResponse(res *http.Response, decryptErr error) *http.Response {
+	err := errors.Tracec(http.StatusBadGateway, decryptErr)
+	body, _ := json.Marshal(err)
+	index, _ := frame.Of(res).Fragment()
+	defragger, ok := c.responseDefrags.Load(frame.Of(res).FromID() + "|" + frame.Of(res).MessageID())
+	if ok && defragger.Streaming() {
+		frame.Of(res).SetFragment(index, index)
+	} else {
+		frame.Of(res).SetFragment(1, 1)
+	}
+	frame.Of(res).SetOpCode(frame.OpCodeError)
+	frame.Of(res).SetEncryption("")
+	frame.Of(res).SetCompression("")
+	res.StatusCode = http.StatusBadGateway
+	res.Header.Set("Content-Type", "application/json")
+	res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	res.Body = httpx.NewBodyReader(body)
+	res.ContentLength = int64(len(body))
+	return res
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/cfg"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_EncryptedPayload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.encrypted.payload.connector")
+	alpha.SetDeployment(TESTING)
+	alpha.SetPlane(plane)
+	alpha.maxFragmentSize = 1024
+	alpha.Subscribe("POST", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		testarossa.Equal(t, "", frame.Of(r).Encryption())
+		b, _ := io.ReadAll(r.Body)
+		w.Write(b)
+		return nil
+	}, sub.Encrypted())
+	alpha.SetConfig("PayloadKeys", "key2 key1")
+
+	beta := New("beta.encrypted.payload.connector")
+	beta.SetDeployment(TESTING)
+	beta.SetPlane(plane)
+	beta.maxFragmentSize = 1024
+	beta.DefineConfig("PayloadKeys", cfg.Secret())
+	beta.SetConfig("PayloadKeys", "key1")
+
+	var hedgeCalls atomic.Int32
+	alpha.Subscribe("GET", "hedged", func(w http.ResponseWriter, r *http.Request) error {
+		if hedgeCalls.Add(1) == 1 {
+			time.Sleep(time.Second)
+		}
+		w.Write([]byte(frame.Of(r).MessageID()))
+		return nil
+	}, sub.Encrypted())
+	alpha.Subscribe("POST", "rotate", func(w http.ResponseWriter, r *http.Request) error {
+		// The caller loses the key before the response arrives
+		beta.SetConfig("PayloadKeys", "key3")
+		w.Write([]byte("ok"))
+		return nil
+	}, sub.Encrypted())
+
+	// Tap into all messages on the plane
+	var tapLock sync.Mutex
+	var tapped [][]byte
+	tap := New("tap.encrypted.payload.connector")
+	tap.SetPlane(plane)
+
+	// Startup the microservices
+	for _, con := range []*Connector{alpha, beta, tap} {
+		err := con.Startup()
+		testarossa.NoError(t, err)
+		defer con.Shutdown()
+	}
+	tapSub, err := tap.transportConn.Subscribe(plane+".>", func(msg *transport.Msg) {
+		tapLock.Lock()
+		tapped = append(tapped, msg.Data)
+		tapLock.Unlock()
+	})
+	testarossa.NoError(t, err)
+	defer tapSub.Unsubscribe()
+	tap.transportConn.NATS().Flush() // Make sure the subscription is registered with the server
+
+	// The payload is not visible on the bus
+	secret := []byte("Social security number 123-45-6789")
+	res, err := beta.Request(ctx, pub.POST("https://alpha.encrypted.payload.connector/echo"), pub.Body(secret), pub.Encrypted())
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "", frame.Of(res).Encryption())
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, secret, b)
+	}
+	time.Sleep(100 * time.Millisecond) // The tap may receive the messages after the response
+	tapLock.Lock()
+	testarossa.True(t, len(tapped) >= 2)
+	for _, data := range tapped {
+		testarossa.False(t, bytes.Contains(data, secret))
+		testarossa.True(t, bytes.Contains(data, []byte(frame.HeaderFromHost)))
+	}
+	tapLock.Unlock()
+
+	// Fragments are encrypted separately
+	payload := []byte(rand.AlphaNum64(16 * 1024))
+	res, err = beta.Request(ctx, pub.POST("https://alpha.encrypted.payload.connector/echo"), pub.Body(payload), pub.Encrypted())
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, payload, b)
+	}
+
+	// Requests that are not encrypted are rejected
+	_, err = beta.Request(ctx, pub.POST("https://alpha.encrypted.payload.connector/echo"), pub.Body(secret))
+	testarossa.Equal(t, http.StatusBadRequest, errors.StatusCode(err))
+
+	// Requests cannot be encrypted without keys
+	_, err = tap.Request(ctx, pub.POST("https://alpha.encrypted.payload.connector/echo"), pub.Body(secret), pub.Encrypted())
+	testarossa.Error(t, err)
+
+	// A hedged copy is encrypted again under its own message ID
+	t0 := time.Now()
+	res, err = beta.Request(ctx, pub.GET("https://alpha.encrypted.payload.connector/hedged"), pub.Hedge(100*time.Millisecond), pub.Encrypted())
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, frame.Of(res).MessageID(), string(b))
+	}
+	testarossa.True(t, time.Since(t0) < time.Second)
+	testarossa.Equal(t, int32(2), hedgeCalls.Load())
+
+	// A response that cannot be decrypted is delivered as an error
+	t0 = time.Now()
+	_, err = beta.Request(ctx, pub.POST("https://alpha.encrypted.payload.connector/rotate"), pub.Body(secret), pub.Encrypted())
+	testarossa.Equal(t, http.StatusBadGateway, errors.StatusCode(err))
+	testarossa.True(t, time.Since(t0) < time.Second)
+	beta.SetConfig("PayloadKeys", "key1")
+}
+
+func TestConnector_PayloadAAD(t *testing.T) {
+	t.Parallel()
+
+	con := New("payload.aad.connector")
+	con.DefineConfig("PayloadKeys", cfg.Secret())
+	con.SetConfig("PayloadKeys", "key1")
+
+	newHeader := func() http.Header {
+		h := make(http.Header)
+		frame.Of(h).SetMessageID("msg1")
+		frame.Of(h).SetFragment(2, 3)
+		frame.Of(h).SetOpCode(frame.OpCodeRequest)
+		return h
+	}
+	plaintext := []byte("Social security number 123-45-6789")
+	encrypt := func() (http.Header, []byte) {
+		h := newHeader()
+		ciphertext, err := con.encryptBody(h, bytes.NewReader(plaintext), "")
+		testarossa.NoError(t, err)
+		return h, ciphertext
+	}
+
+	// The ciphertext decrypts in its own message
+	h, ciphertext := encrypt()
+	decrypted, err := con.decryptBody(h, bytes.NewReader(ciphertext))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, plaintext, decrypted)
+	}
+
+	// The ciphertext is bound to the message ID, fragment index, fragment count and op code
+	h, ciphertext = encrypt()
+	frame.Of(h).SetMessageID("msg2")
+	_, err = con.decryptBody(h, bytes.NewReader(ciphertext))
+	testarossa.Error(t, err)
+
+	h, ciphertext = encrypt()
+	frame.Of(h).SetFragment(3, 3)
+	_, err = con.decryptBody(h, bytes.NewReader(ciphertext))
+	testarossa.Error(t, err)
+
+	h, ciphertext = encrypt()
+	frame.Of(h).SetFragment(2, 2)
+	_, err = con.decryptBody(h, bytes.NewReader(ciphertext))
+	testarossa.Error(t, err)
+
+	h, ciphertext = encrypt()
+	frame.Of(h).SetOpCode(frame.OpCodeResponse)
+	_, err = con.decryptBody(h, bytes.NewReader(ciphertext))
+	testarossa.Error(t, err)
+}
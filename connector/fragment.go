@@ -25,11 +25,17 @@ import (
 	"github.com/microbus-io/fabric/httpx"
 )
 
+// streamFragmentWindow is the number of fragments of a streamed request that the caller may send
+// ahead of their acknowledgement by the recipient.
+// The window is announced by the caller and echoed by recipients that acknowledge fragments.
+const streamFragmentWindow = 4
+
 // defragRequest assembles all fragments of an incoming HTTP request and returns the integrated HTTP request.
-// If not all fragments are available yet, it returns nil
+// If not all fragments are available yet, it returns nil.
+// A streamed request is returned as soon as its first fragment arrives, and its body is fed by subsequent fragments.
 func (c *Connector) defragRequest(r *http.Request) (integrated *http.Request, err error) {
 	fragmentIndex, fragmentMax := frame.Of(r).Fragment()
-	if fragmentMax <= 1 {
+	if fragmentIndex == 1 && fragmentMax == 1 {
 		return r, nil
 	}
 	fromID := frame.Of(r).FromID()
@@ -42,21 +48,7 @@ func (c *Connector) defragRequest(r *http.Request) (integrated *http.Request, er
 			// Most likely caused after a timeout, but can also happen if initial chunk has wrong index
 			return nil, errors.Newc(http.StatusRequestTimeout, "defrag timeout")
 		}
-		defragger = httpx.NewDefragRequest()
-		c.requestDefrags.Store(fragKey, defragger)
-		// Timeout if fragments stop arriving
-		go func() {
-			for {
-				time.Sleep(c.networkHop / 2)
-				if _, ok := c.requestDefrags.Load(fragKey); !ok {
-					break
-				}
-				if defragger.LastActivity() > c.networkHop {
-					c.requestDefrags.Delete(fragKey)
-					break
-				}
-			}
-		}()
+		defragger = c.expectRequestFragments(fragKey)
 	}
 
 	err = defragger.Add(r)
@@ -72,16 +64,55 @@ func (c *Connector) defragRequest(r *http.Request) (integrated *http.Request, er
 		return nil, nil
 	}
 
-	c.requestDefrags.Delete(fragKey)
+	if !defragger.Streaming() {
+		c.requestDefrags.Delete(fragKey)
+	}
 
 	return integrated, nil
 }
 
+// expectRequestFragments returns the defragger of the fragmented request, creating it if it does not yet exist.
+// The defragger is discarded if fragments stop arriving.
+func (c *Connector) expectRequestFragments(fragKey string) *httpx.DefragRequest {
+	defragger, loaded := c.requestDefrags.LoadOrStore(fragKey, httpx.NewDefragRequest())
+	if loaded {
+		return defragger
+	}
+	// Timeout if fragments stop arriving
+	go func() {
+		for {
+			time.Sleep(c.networkHop / 2)
+			if _, ok := c.requestDefrags.Load(fragKey); !ok {
+				break
+			}
+			if defragger.Streaming() {
+				// Streamed requests may idle for as long as the stream is open.
+				// Fragments that arrive after the reader closed the stream are discarded
+				if defragger.Done() && defragger.LastActivity() > c.networkHop {
+					c.requestDefrags.Delete(fragKey)
+					break
+				}
+				continue
+			}
+			if defragger.LastActivity() > c.networkHop {
+				c.requestDefrags.Delete(fragKey)
+				break
+			}
+		}
+	}()
+	return defragger
+}
+
 // defragResponse assembles all fragments of an incoming HTTP response and returns the integrated HTTP request.
-// If not all fragments are available yet, it returns nil
+// If not all fragments are available yet, it returns nil.
+// A streamed response is returned as soon as its first fragment arrives, and its body is fed by subsequent fragments.
 func (c *Connector) defragResponse(r *http.Response) (integrated *http.Response, err error) {
 	fragmentIndex, fragmentMax := frame.Of(r).Fragment()
-	if fragmentMax <= 1 {
+	if fragmentIndex == 1 && fragmentMax == 1 {
+		return r, nil
+	}
+	if frame.Of(r).OpCode() == frame.OpCodeAck {
+		// Acks are never fragmented, but may indicate the fragment of the request that they acknowledge
 		return r, nil
 	}
 	fromID := frame.Of(r).FromID()
@@ -103,6 +134,15 @@ func (c *Connector) defragResponse(r *http.Response) (integrated *http.Response,
 				if _, ok := c.responseDefrags.Load(fragKey); !ok {
 					break
 				}
+				if defragger.Streaming() {
+					// Streamed responses may idle for as long as the stream is open.
+					// Fragments that arrive after the reader closed the stream are discarded
+					if defragger.Done() && defragger.LastActivity() > c.networkHop {
+						c.responseDefrags.Delete(fragKey)
+						break
+					}
+					continue
+				}
 				if defragger.LastActivity() > c.networkHop {
 					c.responseDefrags.Delete(fragKey)
 					break
@@ -124,7 +164,9 @@ func (c *Connector) defragResponse(r *http.Response) (integrated *http.Response,
 		return nil, nil
 	}
 
-	c.responseDefrags.Delete(fragKey)
+	if !defragger.Streaming() {
+		c.responseDefrags.Delete(fragKey)
+	}
 
 	return integrated, nil
 }
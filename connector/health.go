@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/service"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// Names of the built-in health checks
+const (
+	healthCheckNATS         = "nats"
+	healthCheckConfigurator = "configurator"
+	healthCheckLifecycle    = "lifecycle"
+)
+
+// HealthReport summarizes the results of health checks.
+// It is the JSON response to the :888/health and :888/ready control requests.
+type HealthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// NewHealthReport summarizes the results of health checks, keyed by name.
+// The status is "fail" if any of the checks failed, or "ok" otherwise.
+func NewHealthReport(results map[string]error) *HealthReport {
+	report := &HealthReport{
+		Status: "ok",
+		Checks: make(map[string]string, len(results)),
+	}
+	for name, err := range results {
+		if err != nil {
+			report.Status = "fail"
+			report.Checks[name] = err.Error()
+		} else {
+			report.Checks[name] = "ok"
+		}
+	}
+	return report
+}
+
+// Write writes the report as JSON. The status code is 503 if any of the checks failed.
+func (report *HealthReport) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	err := json.NewEncoder(w).Encode(report)
+	return errors.Trace(err)
+}
+
+/*
+SetHealthCheck sets a named check of the health of the microservice.
+Health checks are run on demand when the :888/health or :888/ready control requests are received,
+and should therefore be quick to complete.
+A check is considered failed if it returns an error. A nil handler removes the health check.
+
+The names nats, configurator and lifecycle are reserved for the built-in checks.
+*/
+func (c *Connector) SetHealthCheck(name string, handler service.HealthCheckHandler) error {
+	if err := utils.ValidateHealthCheckName(name); err != nil {
+		return c.captureInitErr(errors.Trace(err))
+	}
+	name = strings.ToLower(name)
+	if name == healthCheckNATS || name == healthCheckConfigurator || name == healthCheckLifecycle {
+		return c.captureInitErr(errors.Newf("reserved health check name '%s'", name))
+	}
+	c.healthLock.Lock()
+	if handler == nil {
+		delete(c.healthChecks, name)
+	} else {
+		c.healthChecks[name] = handler
+	}
+	c.healthLock.Unlock()
+	return nil
+}
+
+/*
+CheckHealth runs the health checks of the microservice and returns their results, keyed by name.
+A nil result indicates that the check passed.
+The built-in nats check verifies the connectivity to the NATS bus.
+*/
+func (c *Connector) CheckHealth(ctx context.Context) (results map[string]error) {
+	c.healthLock.Lock()
+	checks := make(map[string]service.HealthCheckHandler, len(c.healthChecks)+1)
+	for name, handler := range c.healthChecks {
+		checks[name] = handler
+	}
+	c.healthLock.Unlock()
+	checks[healthCheckNATS] = c.checkNATS
+	return c.runHealthChecks(ctx, checks)
+}
+
+/*
+CheckReadiness runs the health checks of the microservice, along with the checks that indicate
+if it is ready to take requests, and returns their results, keyed by name.
+A nil result indicates that the check passed.
+The built-in lifecycle check verifies that the microservice is started and is not draining,
+and the built-in configurator check verifies that the configurator is reachable.
+*/
+func (c *Connector) CheckReadiness(ctx context.Context) (results map[string]error) {
+	c.healthLock.Lock()
+	checks := make(map[string]service.HealthCheckHandler, len(c.healthChecks)+3)
+	for name, handler := range c.healthChecks {
+		checks[name] = handler
+	}
+	c.healthLock.Unlock()
+	checks[healthCheckNATS] = c.checkNATS
+	checks[healthCheckConfigurator] = c.checkConfigurator
+	checks[healthCheckLifecycle] = c.checkLifecycle
+	return c.runHealthChecks(ctx, checks)
+}
+
+// runHealthChecks runs the health checks in parallel and collects their results.
+func (c *Connector) runHealthChecks(ctx context.Context, checks map[string]service.HealthCheckHandler) (results map[string]error) {
+	results = make(map[string]error, len(checks))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for name, handler := range checks {
+		go func() {
+			defer wg.Done()
+			err := errors.CatchPanic(func() error {
+				return handler(ctx)
+			})
+			lock.Lock()
+			results[name] = err
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// checkNATS verifies the connectivity to the NATS bus.
+func (c *Connector) checkNATS(ctx context.Context) error {
+	conn := c.transportConn
+	if conn == nil {
+		return errors.New("not connected")
+	}
+	if natsConn := conn.NATS(); natsConn != nil && !natsConn.IsConnected() {
+		return errors.Newf("connection is %s", strings.ToLower(natsConn.Status().String()))
+	}
+	return nil
+}
+
+// checkConfigurator verifies that the configurator is reachable, if the microservice has configs to fetch from it.
+func (c *Connector) checkConfigurator(ctx context.Context) error {
+	if c.deployment == TESTING || c.hostname == "configurator.core" {
+		return nil
+	}
+	c.configLock.Lock()
+	count := len(c.configs)
+	c.configLock.Unlock()
+	if count == 0 {
+		return nil
+	}
+	_, err := c.Request(ctx, pub.POST("https://configurator.core:888/ping"), pub.Body(struct{}{}))
+	return errors.Trace(err)
+}
+
+// checkLifecycle verifies that the microservice is started and is not draining.
+func (c *Connector) checkLifecycle(ctx context.Context) error {
+	if !c.IsStarted() {
+		return errors.New("not started")
+	}
+	if c.IsDraining() {
+		return errors.New("draining")
+	}
+	return nil
+}
+
+// handleControlHealth responds to the :888/health control request with the results of the health checks.
+func (c *Connector) handleControlHealth(w http.ResponseWriter, r *http.Request) error {
+	return NewHealthReport(c.CheckHealth(r.Context())).Write(w)
+}
+
+// handleControlReady responds to the :888/ready control request with the results of the readiness checks.
+func (c *Connector) handleControlReady(w http.ResponseWriter, r *http.Request) error {
+	return NewHealthReport(c.CheckReadiness(r.Context())).Write(w)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/microbus-io/fabric/coreservices/control/controlapi"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	healthy := true
+	con := New("health.check.connector")
+	err := con.SetHealthCheck("Database", func(ctx context.Context) error {
+		if !healthy {
+			return errors.New("database is down")
+		}
+		return nil
+	})
+	testarossa.NoError(t, err)
+
+	// Reserved and invalid names
+	testarossa.Error(t, con.SetHealthCheck("nats", func(ctx context.Context) error { return nil }))
+	testarossa.Error(t, con.SetHealthCheck("Lifecycle", func(ctx context.Context) error { return nil }))
+	testarossa.Error(t, con.SetHealthCheck("no spaces", func(ctx context.Context) error { return nil }))
+	con.initErr = nil
+
+	// Startup the microservice
+	err = con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Healthy
+	status, checks, err := controlapi.NewClient(con).ForHost(con.Hostname()).Health(ctx)
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "ok", status)
+		testarossa.Equal(t, "ok", checks["database"])
+		testarossa.Equal(t, "ok", checks["nats"])
+		testarossa.Equal(t, "", checks["lifecycle"])
+	}
+	status, checks, err = controlapi.NewClient(con).ForHost(con.Hostname()).Ready(ctx)
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "ok", status)
+		testarossa.Equal(t, "ok", checks["database"])
+		testarossa.Equal(t, "ok", checks["nats"])
+		testarossa.Equal(t, "ok", checks["lifecycle"])
+		testarossa.Equal(t, "ok", checks["configurator"])
+	}
+
+	// Unhealthy
+	healthy = false
+	res, err := con.Request(ctx, pub.GET("https://health.check.connector:888/health"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		var report HealthReport
+		err = json.NewDecoder(res.Body).Decode(&report)
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, "fail", report.Status)
+			testarossa.Equal(t, "database is down", report.Checks["database"])
+			testarossa.Equal(t, "ok", report.Checks["nats"])
+		}
+	}
+
+	// Removing the health check
+	err = con.SetHealthCheck("database", nil)
+	testarossa.NoError(t, err)
+	results := con.CheckHealth(ctx)
+	_, ok := results["database"]
+	testarossa.False(t, ok)
+	testarossa.Nil(t, results["nats"])
+}
+
+func TestConnector_ReadinessWhileDraining(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("readiness.while.draining.connector")
+	con.SetPlane(rand.AlphaNum64(12)) // Isolate the eviction multicast from other tests
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	results := con.CheckReadiness(ctx)
+	testarossa.Nil(t, results["lifecycle"])
+
+	err = con.Drain()
+	testarossa.NoError(t, err)
+
+	results = con.CheckReadiness(ctx)
+	testarossa.Error(t, results["lifecycle"])
+	results = con.CheckHealth(ctx)
+	testarossa.Nil(t, results["nats"])
+}
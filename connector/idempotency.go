@@ -0,0 +1,223 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/dlru"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/sub"
+)
+
+const (
+	// idempotencyInFlight prefixes the marker that is stored in the distributed cache while the first request with an idempotency key is in flight
+	idempotencyInFlight = "In-Flight "
+	// idempotencyInFlightMaxAge is how long a request with no time budget is considered in flight
+	idempotencyInFlightMaxAge = time.Minute
+)
+
+// idempotencyRecorder captures the response of the handler while passing it through to the underlying writer.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// WriteHeader captures the status code and passes it through.
+func (ir *idempotencyRecorder) WriteHeader(statusCode int) {
+	if ir.statusCode == 0 {
+		ir.statusCode = statusCode
+	}
+	ir.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures the body and passes it through.
+func (ir *idempotencyRecorder) Write(b []byte) (int, error) {
+	if ir.statusCode == 0 {
+		ir.statusCode = http.StatusOK
+	}
+	ir.body.Write(b)
+	return ir.ResponseWriter.Write(b)
+}
+
+// Flush passes through to the underlying writer, if it supports flushing.
+func (ir *idempotencyRecorder) Flush() {
+	if flusher, ok := ir.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// idempotencyCacheKey returns the key under which the response to a request to an idempotent subscription is stored in the distributed cache,
+// or an empty string if the request is not subject to idempotency.
+// Keys are scoped to the calling microservice and to the actor so that one caller cannot replay the response to another.
+func idempotencyCacheKey(s *sub.Subscription, r *http.Request) string {
+	if !s.Idempotent || s.Queue == "" {
+		return ""
+	}
+	key := frame.Of(r).IdempotencyKey()
+	if key == "" {
+		key = r.Header.Get("Idempotency-Key")
+	}
+	if key == "" {
+		return ""
+	}
+	f := frame.Of(r)
+	return "idempotency|" + r.Method + "|" + s.Port + "|" + r.URL.Path + "|" + f.FromHost() + "|" + f.Actor().Subject() + "|" + key
+}
+
+// idempotencyFingerprint returns a hash of the query and the body of the request,
+// which tells apart a retry of a request from a different request that reuses its idempotency key.
+// A body that was delivered in full is read into memory and restored.
+// A streamed body is not read ahead of the handler, so only its content type and length are hashed.
+func idempotencyFingerprint(r *http.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(r.URL.RawQuery))
+	h.Write([]byte{0})
+	if _, ok := r.Body.(*httpx.StreamReader); ok {
+		h.Write([]byte(r.Header.Get("Content-Type")))
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.FormatInt(r.ContentLength, 10)))
+	} else if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// callIdempotentHandler calls the handler of the subscription.
+// For an idempotent subscription, the response to the first request with an idempotency key is stored in the distributed cache
+// and replayed to subsequent requests with the same key. A request whose key matches a request still in flight is rejected with a 409 error.
+// A request whose key matches a request with a different query or body is rejected with a 422 error.
+// Storing the in-flight marker is not atomic across replicas, so concurrent requests with the same key may on rare occasion both be processed.
+func (c *Connector) callIdempotentHandler(w http.ResponseWriter, r *http.Request, s *sub.Subscription) error {
+	cacheKey := idempotencyCacheKey(s, r)
+	if cacheKey == "" {
+		return s.Handler.(HTTPHandler)(w, r) // No trace
+	}
+	ctx := r.Context()
+	fingerprint, err := idempotencyFingerprint(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Replay the stored response, if there is one
+	// Stored values are prefixed by the fingerprint of the request
+	cached, ok, err := c.distribCache.Load(ctx, cacheKey, dlru.NoBump())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		storedFingerprint, rest, _ := bytes.Cut(cached, []byte("\n"))
+		if string(storedFingerprint) != fingerprint {
+			return errors.Newc(http.StatusUnprocessableEntity, "idempotency key is reused with a different request")
+		}
+		cached = rest
+	}
+	if ok && strings.HasPrefix(string(cached), idempotencyInFlight) {
+		deadline, _ := strconv.ParseInt(strings.TrimPrefix(string(cached), idempotencyInFlight), 10, 64)
+		if time.Now().UnixNano() < deadline {
+			return errors.Newc(http.StatusConflict, "request with the same idempotency key is in flight")
+		}
+		ok = false
+	}
+	if ok {
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(cached)), nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer res.Body.Close()
+		for k, vv := range res.Header {
+			w.Header()[k] = vv
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(res.StatusCode)
+		_, err = io.Copy(w, res.Body)
+		return errors.Trace(err)
+	}
+
+	// Mark the request as in flight
+	inFlightAge := idempotencyInFlightMaxAge
+	if budget := frame.Of(r).TimeBudget(); budget > 0 {
+		inFlightAge = budget
+	}
+	marker := fingerprint + "\n" + idempotencyInFlight + strconv.FormatInt(time.Now().Add(inFlightAge).UnixNano(), 10)
+	err = c.distribCache.Store(ctx, cacheKey, []byte(marker))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Call the handler
+	recorder := &idempotencyRecorder{ResponseWriter: w}
+	handlerErr := errors.CatchPanic(func() error {
+		return s.Handler.(HTTPHandler)(recorder, r)
+	})
+	if handlerErr != nil {
+		// Allow the request to be retried
+		err = c.distribCache.Delete(c.lifetimeCtx, cacheKey)
+		if err != nil {
+			c.LogWarn(ctx, "Deleting idempotency marker",
+				"error", err,
+				"key", cacheKey,
+			)
+		}
+		return handlerErr // No trace
+	}
+
+	// Store the response
+	statusCode := recorder.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	res := &http.Response{
+		StatusCode:    statusCode,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.Header().Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		ContentLength: int64(recorder.body.Len()),
+	}
+	var buf bytes.Buffer
+	buf.WriteString(fingerprint + "\n")
+	err = res.Write(&buf)
+	if err == nil {
+		// The context of the request may have expired by now
+		err = c.distribCache.Store(c.lifetimeCtx, cacheKey, buf.Bytes())
+	}
+	if err != nil {
+		c.LogWarn(ctx, "Storing idempotent response",
+			"error", err,
+			"key", cacheKey,
+		)
+	}
+	return nil
+}
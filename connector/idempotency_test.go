@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	var count atomic.Int32
+	var fail atomic.Bool
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		if fail.Load() {
+			return errors.New("oops")
+		}
+		n := count.Add(1)
+		w.Header().Set("X-Count", strconv.Itoa(int(n)))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("Created " + strconv.Itoa(int(n))))
+		return nil
+	}
+	con := New("idempotent.connector")
+	con.Subscribe("POST", "create", handler, sub.Idempotent())
+	con.Subscribe("POST", "not-idempotent", handler)
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// First request is processed
+	res, err := con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("abc"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusCreated, res.StatusCode)
+		testarossa.Equal(t, "1", res.Header.Get("X-Count"))
+		testarossa.Equal(t, "", res.Header.Get("Idempotent-Replayed"))
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "Created 1", string(body))
+	}
+
+	// Duplicate request is replayed
+	res, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("abc"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusCreated, res.StatusCode)
+		testarossa.Equal(t, "1", res.Header.Get("X-Count"))
+		testarossa.Equal(t, "true", res.Header.Get("Idempotent-Replayed"))
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "Created 1", string(body))
+	}
+	testarossa.Equal(t, int32(1), count.Load())
+
+	// The key can also be set in the Idempotency-Key header
+	res, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.Header("Idempotency-Key", "abc"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "1", res.Header.Get("X-Count"))
+	}
+	testarossa.Equal(t, int32(1), count.Load())
+
+	// A different key is processed
+	res, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("def"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "2", res.Header.Get("X-Count"))
+	}
+
+	// Requests without a key are always processed
+	for i := 3; i <= 4; i++ {
+		res, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"))
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, strconv.Itoa(i), res.Header.Get("X-Count"))
+		}
+	}
+
+	// The key is ignored by endpoints that are not idempotent
+	for i := 5; i <= 6; i++ {
+		res, err = con.Request(ctx, pub.POST("https://idempotent.connector/not-idempotent"), pub.IdempotencyKey("abc"))
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, strconv.Itoa(i), res.Header.Get("X-Count"))
+		}
+	}
+
+	// Errors are not stored and the request can be retried
+	fail.Store(true)
+	_, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("ghi"))
+	testarossa.Error(t, err)
+	fail.Store(false)
+	res, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("ghi"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "7", res.Header.Get("X-Count"))
+	}
+
+	// A different request that reuses the key is rejected
+	_, err = con.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("abc"), pub.Body("different"))
+	testarossa.Equal(t, http.StatusUnprocessableEntity, errors.StatusCode(err))
+	_, err = con.Request(ctx, pub.POST("https://idempotent.connector/create?x=1"), pub.IdempotencyKey("abc"))
+	testarossa.Equal(t, http.StatusUnprocessableEntity, errors.StatusCode(err))
+	testarossa.Equal(t, int32(7), count.Load())
+
+	// Keys are scoped to the caller
+	other := New("other.idempotent.connector")
+	err = other.Startup()
+	testarossa.NoError(t, err)
+	defer other.Shutdown()
+	res, err = other.Request(ctx, pub.POST("https://idempotent.connector/create"), pub.IdempotencyKey("abc"))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "8", res.Header.Get("X-Count"))
+		testarossa.Equal(t, "", res.Header.Get("Idempotent-Replayed"))
+	}
+}
+
+func TestConnector_IdempotentInFlight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	entered := make(chan bool)
+	release := make(chan bool)
+	con := New("idempotent.in.flight.connector")
+	con.Subscribe("POST", "slow", func(w http.ResponseWriter, r *http.Request) error {
+		entered <- true
+		<-release
+		w.Write([]byte("Done"))
+		return nil
+	}, sub.Idempotent())
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Duplicate request is rejected while the first is in flight
+	done := make(chan error)
+	go func() {
+		_, err := con.Request(ctx, pub.POST("https://idempotent.in.flight.connector/slow"), pub.IdempotencyKey("abc"))
+		done <- err
+	}()
+	<-entered
+	_, err = con.Request(ctx, pub.POST("https://idempotent.in.flight.connector/slow"), pub.IdempotencyKey("abc"))
+	testarossa.Equal(t, http.StatusConflict, errors.StatusCode(err))
+	release <- true
+	testarossa.NoError(t, <-done)
+
+	// Duplicate request is replayed once the first completes
+	res, err := con.Request(ctx, pub.POST("https://idempotent.in.flight.connector/slow"), pub.IdempotencyKey("abc"))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "Done", string(body))
+	}
+
+	// In-flight markers expire with the time budget of the request
+	shortCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	go func() {
+		_, err := con.Request(shortCtx, pub.POST("https://idempotent.in.flight.connector/slow"), pub.IdempotencyKey("def"))
+		done <- err
+	}()
+	<-entered
+	<-shortCtx.Done()
+	go func() {
+		_, err := con.Request(ctx, pub.POST("https://idempotent.in.flight.connector/slow"), pub.IdempotencyKey("def"))
+		done <- err
+	}()
+	<-entered
+	release <- true
+	release <- true
+	<-done
+	<-done
+}
+
+func TestConnector_IdempotencyFingerprintStreamed(t *testing.T) {
+	t.Parallel()
+
+	// The fingerprint of a streamed body does not wait for the stream to end
+	stream := httpx.NewStreamReader()
+	stream.Write([]byte("Hello"))
+	r, err := http.NewRequest("POST", "https://fingerprint.connector/streamed", stream)
+	testarossa.NoError(t, err)
+	r.ContentLength = -1
+	fingerprint1, err := idempotencyFingerprint(r)
+	testarossa.NoError(t, err)
+
+	// The body is left for the handler to read
+	stream.Write([]byte(" World"))
+	stream.CloseWithError(nil)
+	body, err := io.ReadAll(r.Body)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "Hello World", string(body))
+
+	// The content type is part of the fingerprint
+	r.Header.Set("Content-Type", "text/plain")
+	fingerprint2, err := idempotencyFingerprint(r)
+	testarossa.NoError(t, err)
+	testarossa.NotEqual(t, fingerprint1, fingerprint2)
+
+	// A body that was delivered in full is hashed and restored
+	r, err = http.NewRequest("POST", "https://fingerprint.connector/buffered", strings.NewReader("Hello"))
+	testarossa.NoError(t, err)
+	fingerprint3, err := idempotencyFingerprint(r)
+	testarossa.NoError(t, err)
+	body, err = io.ReadAll(r.Body)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "Hello", string(body))
+	r, err = http.NewRequest("POST", "https://fingerprint.connector/buffered", strings.NewReader("World"))
+	testarossa.NoError(t, err)
+	fingerprint4, err := idempotencyFingerprint(r)
+	testarossa.NoError(t, err)
+	testarossa.NotEqual(t, fingerprint3, fingerprint4)
+}
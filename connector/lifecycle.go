@@ -71,6 +71,9 @@ func (c *Connector) Startup() (err error) {
 		return c.initErr
 	}
 
+	// Wrap the publisher with the publish middleware
+	c.publishHandler = c.publishMiddleware.Wrap(c.publish)
+
 	// Determine the communication plane
 	defaultPlane := false
 	if c.plane == "" {
@@ -86,6 +89,12 @@ func (c *Connector) Startup() (err error) {
 		}
 	}
 
+	// Determine the keys that sign and verify messages
+	err = c.initSigning()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// Determine the geographic locality
 	if c.locality == "" {
 		if locality := env.Get("MICROBUS_LOCALITY"); locality != "" {
@@ -100,6 +109,12 @@ func (c *Connector) Startup() (err error) {
 		return errors.Trace(err)
 	}
 
+	// Determine the canary splits
+	err = c.initVersionWeights()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// Identify the environment deployment
 	if c.deployment == "" {
 		if deployment := env.Get("MICROBUS_DEPLOYMENT"); deployment != "" {
@@ -135,7 +150,11 @@ func (c *Connector) Startup() (err error) {
 		}
 		if c.deployment == "" {
 			c.deployment = LOCAL
-			if nats := env.Get("MICROBUS_NATS"); nats != "" {
+			nats := c.natsURL
+			if nats == "" {
+				nats = env.Get("MICROBUS_NATS")
+			}
+			if nats != "" && nats != "none" && nats != "embedded" {
 				if !strings.Contains(nats, "/127.0.0.1:") &&
 					!strings.Contains(nats, "/0.0.0.0:") &&
 					!strings.Contains(nats, "/localhost:") {
@@ -201,14 +220,14 @@ func (c *Connector) Startup() (err error) {
 	}
 	c.started.Store(true)
 
-	c.maxFragmentSize = c.natsConn.MaxPayload() - 64*1024 // Up to 64K for headers
+	c.maxFragmentSize = c.transportConn.MaxPayload() - 64*1024 // Up to 64K for headers
 	if c.maxFragmentSize < 64*1024 {
 		err = errors.New("message size limit is too restrictive")
 		return err
 	}
 
 	// Subscribe to the response subject
-	c.natsResponseSub, err = c.natsConn.QueueSubscribe(subjectOfResponses(c.plane, c.hostname, c.id), c.id, c.onResponse)
+	c.responseSub, err = c.transportConn.QueueSubscribe(subjectOfResponses(c.plane, c.hostname, c.id), c.id, c.onResponse)
 	if err != nil {
 		err = errors.Trace(err)
 		return err
@@ -260,12 +279,18 @@ func (c *Connector) Startup() (err error) {
 		}
 	}
 	time.Sleep(20 * time.Millisecond) // Give time for subscription activation by NATS
+	c.warnUnprotectedCallers(ctx)
 
 	// Run all tickers
 	c.runTickers()
 
 	c.startupTime = time.Now().UTC()
 
+	// Announce the startup to microservices that discovered this one's hostname
+	if err := c.announce("startup"); err != nil {
+		c.LogWarn(ctx, "Announcing startup", "error", err)
+	}
+
 	return nil
 }
 
@@ -274,6 +299,12 @@ func (c *Connector) Shutdown() (err error) {
 	if !c.IsStarted() {
 		return errors.New("not started")
 	}
+	if !c.startupTime.IsZero() {
+		// Announce the shutdown to microservices that discovered this one's hostname
+		if err := c.announce("shutdown"); err != nil {
+			c.LogWarn(c.lifetimeCtx, "Announcing shutdown", "error", err)
+		}
+	}
 	c.started.Store(false)
 
 	// OpenTelemetry: create a span for the callback
@@ -349,19 +380,29 @@ func (c *Connector) Shutdown() (err error) {
 	}
 
 	// Unsubscribe from the response subject
-	if c.natsResponseSub != nil {
-		err = c.natsResponseSub.Unsubscribe()
+	if c.responseSub != nil {
+		err = c.responseSub.Unsubscribe()
 		if err != nil {
 			lastErr = errors.Trace(err)
 		}
-		c.natsResponseSub = nil
+		c.responseSub = nil
 	}
 
 	// Disconnect from NATS
-	if c.natsConn != nil {
-		c.natsConn.Close()
-		c.natsConn = nil
-	}
+	if c.transportConn != nil {
+		c.transportConn.Close()
+		c.transportConn = nil
+	}
+	c.durableLock.Lock()
+	c.durableStreamsReady = false
+	c.durableLock.Unlock()
+	c.draining.Store(false)
+	c.startupTime = time.Time{}
+	c.replicasLock.Lock()
+	c.replicas = 0
+	c.replicasCountedAt = time.Time{}
+	c.replicasAsOf = time.Time{}
+	c.replicasLock.Unlock()
 
 	// Last chance to log an error
 	if lastErr != nil {
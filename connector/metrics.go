@@ -55,6 +55,26 @@ func (c *Connector) newMetricsRegistry() (err error) {
 		[]float64{16 * 1024, 64 * 1024, 256 * 1024, 1024 * 1024, 4 * 1024 * 1024},
 		[]string{"handler", "port", "method", "code", "error"},
 	)
+	c.DefineGauge(
+		"microbus_requests_in_flight",
+		"Number of incoming requests being processed",
+		[]string{"handler", "port", "method"},
+	)
+	c.DefineGauge(
+		"microbus_requests_pending",
+		"Number of incoming requests waiting in queue to be processed",
+		[]string{"handler", "port", "method"},
+	)
+	c.DefineCounter(
+		"microbus_requests_rejected_total",
+		"Number of incoming requests rejected without being processed",
+		[]string{"handler", "port", "method", "reason"},
+	)
+	c.DefineCounter(
+		"microbus_callers_denied_total",
+		"Number of incoming requests denied because their caller is not allowed",
+		[]string{"handler", "port", "method"},
+	)
 	c.DefineCounter(
 		"microbus_request_count_total",
 		"Number of outgoing requests",
@@ -66,6 +86,21 @@ func (c *Connector) newMetricsRegistry() (err error) {
 		[]float64{.005, .010, .025, .050, .100, .250, .500, 1},
 		[]string{"host"},
 	)
+	c.DefineCounter(
+		"microbus_hedge_fired_total",
+		"Number of hedged copies of outgoing requests that were sent",
+		[]string{"method", "host", "port"},
+	)
+	c.DefineCounter(
+		"microbus_hedge_won_total",
+		"Number of hedged copies of outgoing requests that responded first",
+		[]string{"method", "host", "port"},
+	)
+	c.DefineGauge(
+		"microbus_circuit_breaker_state",
+		"State of the circuit breaker of a destination: 0 closed, 1 half-open, 2 open",
+		[]string{"host", "path"},
+	)
 	c.DefineCounter(
 		"microbus_log_messages_total",
 		"Number of log messages recorded",
@@ -157,12 +157,19 @@ func TestConnector_StandardMetrics(t *testing.T) {
 	t.Parallel()
 
 	con := New("standard.metrics.connector")
-	testarossa.Equal(t, 11, len(con.metricDefs))
+	testarossa.Equal(t, 18, len(con.metricDefs))
 	testarossa.NotNil(t, con.metricDefs["microbus_callback_duration_seconds"])
 	testarossa.NotNil(t, con.metricDefs["microbus_response_duration_seconds"])
 	testarossa.NotNil(t, con.metricDefs["microbus_response_size_bytes"])
+	testarossa.NotNil(t, con.metricDefs["microbus_requests_in_flight"])
+	testarossa.NotNil(t, con.metricDefs["microbus_requests_pending"])
+	testarossa.NotNil(t, con.metricDefs["microbus_requests_rejected_total"])
+	testarossa.NotNil(t, con.metricDefs["microbus_callers_denied_total"])
 	testarossa.NotNil(t, con.metricDefs["microbus_request_count_total"])
 	testarossa.NotNil(t, con.metricDefs["microbus_ack_duration_seconds"])
+	testarossa.NotNil(t, con.metricDefs["microbus_hedge_fired_total"])
+	testarossa.NotNil(t, con.metricDefs["microbus_hedge_won_total"])
+	testarossa.NotNil(t, con.metricDefs["microbus_circuit_breaker_state"])
 	testarossa.NotNil(t, con.metricDefs["microbus_log_messages_total"])
 	testarossa.NotNil(t, con.metricDefs["microbus_uptime_duration_seconds_total"])
 	testarossa.NotNil(t, con.metricDefs["microbus_cache_hits_total"])
@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+)
+
+/*
+UsePublishMiddleware appends a named middleware to the chain that wraps every outgoing request
+made by the microservice, including those made by generated clients, and the responses it receives.
+Middleware can be used to inject headers, log payloads, inject faults or collect custom metrics.
+Middleware must be added before the microservice starts.
+
+	con.UsePublishMiddleware("Auth", func(next pub.Handler) pub.Handler {
+		return func(ctx context.Context, req *pub.Request) []*pub.Response {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return next(ctx, req)
+		}
+	})
+*/
+func (c *Connector) UsePublishMiddleware(name string, mw pub.Middleware) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.publishMiddleware.Append(name, mw)
+	return nil
+}
+
+// PublishMiddleware returns the chain of middleware that wraps every outgoing request made by the microservice.
+// The chain allows inserting, replacing and deleting named middleware.
+// It must not be modified after the microservice starts.
+func (c *Connector) PublishMiddleware() *pub.Chain {
+	return &c.publishMiddleware
+}
+
+/*
+UseSubscriptionMiddleware appends a named middleware to the chain that wraps the handlers of all subscriptions
+of the microservice, except for the control subscriptions on port 888.
+Middleware can be used for cross-cutting checks such as authorization, tenant resolution and request validation.
+Use sub.OnRoute to apply the middleware only to requests to a port or path prefix.
+Middleware must be added before the microservice starts.
+
+	con.UseSubscriptionMiddleware("Admin", sub.OnRoute(":443/admin/", func(next sub.HTTPHandler) sub.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Header.Get("X-Role") != "admin" {
+				return errors.Newc(http.StatusForbidden, "forbidden")
+			}
+			return next(w, r)
+		}
+	}))
+*/
+func (c *Connector) UseSubscriptionMiddleware(name string, mw sub.Middleware) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.subMiddleware.Append(name, mw)
+	return nil
+}
+
+// SubscriptionMiddleware returns the chain of middleware that wraps the handlers of all subscriptions of the microservice.
+// The chain allows inserting, replacing and deleting named middleware.
+// It must not be modified after the microservice starts.
+func (c *Connector) SubscriptionMiddleware() *sub.Chain {
+	return &c.subMiddleware
+}
+
+// callHandler calls the handler of the subscription wrapped with the subscription middleware, catching any panics.
+// The caller and the actor of the request are verified before the middleware is called.
+// Control subscriptions on port 888 are neither verified nor wrapped.
+func (c *Connector) callHandler(w http.ResponseWriter, r *http.Request, s *sub.Subscription) error {
+	frame.Of(r).SetActorVerified(false) // Set only by the connector once the actor is verified
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		return c.callIdempotentHandler(w, r, s) // No trace
+	}
+	if s.Port != "888" {
+		err := c.verifyRequest(r, s)
+		if err != nil {
+			return err // No trace
+		}
+		handler = c.subMiddleware.Wrap(handler)
+	}
+	return errors.CatchPanic(func() error {
+		return handler(w, r)
+	})
+}
+
+// verifyRequest verifies that the caller and the actor of the request are allowed by the subscription.
+func (c *Connector) verifyRequest(r *http.Request, s *sub.Subscription) error {
+	err := c.verifyRequestCaller(r, s)
+	if err != nil {
+		return err // No trace
+	}
+	err = c.verifyRequestActor(r, s)
+	if err != nil {
+		return err // No trace
+	}
+	return nil
+}
+
+// preflightRequest responds with a 204 if the caller and the actor of the request are allowed by the subscription,
+// without calling its handler.
+// The scheduler uses preflight requests to verify that a request is allowed before accepting it for delivery.
+func (c *Connector) preflightRequest(w http.ResponseWriter, r *http.Request, s *sub.Subscription) error {
+	if s.Port != "888" {
+		err := c.verifyRequest(r, s)
+		if err != nil {
+			return err // No trace
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
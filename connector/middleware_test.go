@@ -0,0 +1,245 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_PublishMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	alpha := New("alpha.publish.middleware.connector")
+	alpha.Subscribe("GET", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(r.Header.Get("X-Auth")))
+		return nil
+	})
+
+	var countResponses atomic.Int32
+	beta := New("beta.publish.middleware.connector")
+	err := beta.UsePublishMiddleware("Auth", func(next pub.Handler) pub.Handler {
+		return func(ctx context.Context, req *pub.Request) []*pub.Response {
+			req.Header.Set("X-Auth", "Secret")
+			return next(ctx, req)
+		}
+	})
+	testarossa.NoError(t, err)
+	err = beta.UsePublishMiddleware("Count", func(next pub.Handler) pub.Handler {
+		return func(ctx context.Context, req *pub.Request) []*pub.Response {
+			responses := next(ctx, req)
+			countResponses.Add(int32(len(responses)))
+			return responses
+		}
+	})
+	testarossa.NoError(t, err)
+	ok := beta.PublishMiddleware().InsertBefore("Count", "Fault", func(next pub.Handler) pub.Handler {
+		return next
+	})
+	testarossa.True(t, ok)
+	testarossa.Equal(t, "Auth -> Fault -> Count", beta.PublishMiddleware().String())
+
+	// Startup the microservices
+	err = alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Middleware cannot be added after startup
+	err = beta.UsePublishMiddleware("Late", func(next pub.Handler) pub.Handler {
+		return next
+	})
+	testarossa.Error(t, err)
+
+	// The middleware process the request and the response
+	res, err := beta.Request(ctx, pub.GET("https://alpha.publish.middleware.connector/echo"))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "Secret", string(body))
+	}
+	testarossa.Equal(t, int32(1), countResponses.Load())
+
+	// Requests made by other microservices are not impacted
+	res, err = alpha.Request(ctx, pub.GET("https://alpha.publish.middleware.connector/echo"))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "", string(body))
+	}
+}
+
+func TestConnector_PublishMiddlewareFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	var countCalls atomic.Int32
+	alpha := New("alpha.publish.middleware.fault.injection.connector")
+	alpha.Subscribe("GET", "ok", func(w http.ResponseWriter, r *http.Request) error {
+		countCalls.Add(1)
+		return nil
+	})
+
+	beta := New("beta.publish.middleware.fault.injection.connector")
+	beta.UsePublishMiddleware("Fault", func(next pub.Handler) pub.Handler {
+		return next
+	})
+	ok := beta.PublishMiddleware().Replace("Fault", func(next pub.Handler) pub.Handler {
+		return func(ctx context.Context, req *pub.Request) []*pub.Response {
+			err := errors.Newc(http.StatusServiceUnavailable, "injected fault")
+			return []*pub.Response{pub.NewErrorResponse(err)}
+		}
+	})
+	testarossa.True(t, ok)
+
+	// Startup the microservices
+	err := alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// The request is short-circuited by the middleware
+	_, err = beta.Request(ctx, pub.GET("https://alpha.publish.middleware.fault.injection.connector/ok"))
+	testarossa.Error(t, err)
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	count := 0
+	for range beta.Publish(ctx, pub.GET("https://alpha.publish.middleware.fault.injection.connector/ok")) {
+		count++
+	}
+	testarossa.Equal(t, 1, count)
+	testarossa.Zero(t, countCalls.Load())
+}
+
+func TestConnector_SubscriptionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	var countCalls atomic.Int32
+	alpha := New("alpha.subscription.middleware.connector")
+	alpha.Subscribe("GET", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		countCalls.Add(1)
+		w.Write([]byte(strings.Join(r.Header.Values("X-Trail"), ",")))
+		return nil
+	})
+	alpha.Subscribe("GET", ":444/admin/echo", func(w http.ResponseWriter, r *http.Request) error {
+		countCalls.Add(1)
+		w.Write([]byte(strings.Join(r.Header.Values("X-Trail"), ",")))
+		return nil
+	})
+	alpha.Subscribe("GET", "panic", func(w http.ResponseWriter, r *http.Request) error {
+		panic("oops")
+	})
+	tag := func(name string) sub.Middleware {
+		return func(next sub.HTTPHandler) sub.HTTPHandler {
+			return func(w http.ResponseWriter, r *http.Request) error {
+				r.Header.Add("X-Trail", name)
+				return next(w, r)
+			}
+		}
+	}
+	err := alpha.UseSubscriptionMiddleware("Tag", tag("A"))
+	testarossa.NoError(t, err)
+	err = alpha.UseSubscriptionMiddleware("Admin", sub.OnRoute(":444/admin/", func(next sub.HTTPHandler) sub.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Header.Get("X-Role") != "admin" {
+				return errors.Newc(http.StatusForbidden, "forbidden")
+			}
+			return next(w, r)
+		}
+	}))
+	testarossa.NoError(t, err)
+	ok := alpha.SubscriptionMiddleware().InsertAfter("Tag", "Tag2", tag("B"))
+	testarossa.True(t, ok)
+	testarossa.Equal(t, "Tag -> Tag2 -> Admin", alpha.SubscriptionMiddleware().String())
+
+	beta := New("beta.subscription.middleware.connector")
+
+	// Isolate the trace multicast of the error responses from other tests
+	plane := rand.AlphaNum64(12)
+	alpha.SetPlane(plane)
+	beta.SetPlane(plane)
+
+	// Startup the microservices
+	err = alpha.Startup()
+	testarossa.NoError(t, err)
+	defer alpha.Shutdown()
+	err = beta.Startup()
+	testarossa.NoError(t, err)
+	defer beta.Shutdown()
+
+	// Middleware cannot be added after startup
+	err = alpha.UseSubscriptionMiddleware("Late", func(next sub.HTTPHandler) sub.HTTPHandler {
+		return next
+	})
+	testarossa.Error(t, err)
+
+	// The middleware wrap the handler in order
+	res, err := beta.Request(ctx, pub.GET("https://alpha.subscription.middleware.connector/echo"))
+	if testarossa.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "A,B", string(body))
+	}
+	testarossa.Equal(t, int32(1), countCalls.Load())
+
+	// The route-specific middleware rejects the request
+	_, err = beta.Request(ctx, pub.GET("https://alpha.subscription.middleware.connector:444/admin/echo"))
+	testarossa.Error(t, err)
+	testarossa.Equal(t, http.StatusForbidden, errors.StatusCode(err))
+	testarossa.Equal(t, int32(1), countCalls.Load())
+	_, err = beta.Request(ctx,
+		pub.GET("https://alpha.subscription.middleware.connector:444/admin/echo"),
+		pub.Header("X-Role", "admin"),
+	)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, int32(2), countCalls.Load())
+
+	// Panics are caught
+	_, err = beta.Request(ctx, pub.GET("https://alpha.subscription.middleware.connector/panic"))
+	testarossa.Error(t, err)
+
+	// Control subscriptions are not wrapped
+	controlSub, err := sub.NewSub("GET", alpha.Hostname(), ":888/trail", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte(strings.Join(r.Header.Values("X-Trail"), ",")))
+		return nil
+	})
+	testarossa.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	err = alpha.callHandler(recorder, httptest.NewRequest("GET", "https://alpha.subscription.middleware.connector:888/trail", nil), controlSub)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "", recorder.Body.String())
+
+}
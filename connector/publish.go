@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/microbus-io/fabric/errors"
@@ -33,7 +34,7 @@ import (
 	"github.com/microbus-io/fabric/lru"
 	"github.com/microbus-io/fabric/pub"
 	"github.com/microbus-io/fabric/rand"
-	"github.com/nats-io/nats.go"
+	"github.com/microbus-io/fabric/transport"
 	"go.opentelemetry.io/otel/propagation"
 )
 
@@ -109,12 +110,13 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) <-chan *
 	outboundFrame.SetFromVersion(c.version)
 	outboundFrame.SetOpCode(frame.OpCodeRequest)
 
-	// Copy X-Forwarded headers (set by ingress proxy), baggage, clock shift, and Accept-Language headers
+	// Copy X-Forwarded headers (set by ingress proxy), baggage, clock shift, actor, and Accept-Language headers
 	for k, vv := range inboundFrame.Header() {
 		if strings.HasPrefix(k, "X-Forwarded-") ||
 			strings.HasPrefix(k, frame.HeaderBaggagePrefix) ||
 			k == "Accept-Language" ||
-			k == frame.HeaderClockShift {
+			k == frame.HeaderClockShift ||
+			k == frame.HeaderActor {
 			if len(outboundFrame.Header()[k]) == 0 {
 				for _, v := range vv {
 					outboundFrame.Header().Add(k, v)
@@ -130,6 +132,79 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) <-chan *
 		outboundFrame.Set(k, v[0])
 	}
 
+	// Make the request through the publish middleware
+	handler := c.publishHandler
+	if handler == nil {
+		handler = c.publish
+	}
+	output := handler(ctx, req)
+
+	// Return as channel
+	ch := make(chan *pub.Response, len(output))
+	for _, x := range output {
+		ch <- x
+	}
+	close(ch)
+	return ch
+}
+
+// publish makes the request, whether durable, scheduled, hedged or retried, and returns its responses.
+// It is the innermost handler of the publish middleware chain.
+func (c *Connector) publish(ctx context.Context, req *pub.Request) (output []*pub.Response) {
+	// Scheduled requests are handed over to the scheduler core microservice
+	if !req.DeliverAt.IsZero() || req.DeliverAfter > 0 {
+		if req.Durable {
+			return []*pub.Response{pub.NewErrorResponse(errors.New("durable requests cannot be scheduled"))}
+		}
+		for r := range c.scheduleRequest(ctx, req) {
+			output = append(output, r)
+		}
+		return output
+	}
+
+	// Durable requests are stored in JetStream rather than awaiting responses
+	if req.Durable {
+		err := c.publishDurable(ctx, req)
+		if err != nil {
+			return []*pub.Response{pub.NewErrorResponse(errors.Trace(err))}
+		}
+		return nil
+	}
+
+	// Only idempotent requests can be hedged
+	if req.HedgeDelay > 0 && !req.Multicast {
+		switch req.Method {
+		case "GET", "HEAD", "OPTIONS", "PUT", "DELETE", "TRACE":
+		default:
+			err := errors.Newf("hedging non-idempotent method '%s'", req.Method)
+			return []*pub.Response{pub.NewErrorResponse(err)}
+		}
+	}
+
+	// Hold the body in memory if the request may need to be sent again
+	if (req.RetryPolicy != nil || req.HedgeDelay > 0) && !req.Multicast && req.Body != nil {
+		if _, ok := req.Body.(*httpx.BodyReader); !ok {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return []*pub.Response{pub.NewErrorResponse(errors.Trace(err))}
+			}
+			req.Body = httpx.NewBodyReader(body)
+		}
+	}
+
+	// Make the request, retrying if so requested
+	for attempt := 1; ; attempt++ {
+		output = c.makeBreakerAwareRequest(ctx, req)
+		if !c.retryRequest(ctx, req, output, attempt) {
+			break
+		}
+	}
+	return output
+}
+
+// makeLocalityAwareRequest makes a request to the best known locality of the destination,
+// falling back to the original URL if no response is received.
+func (c *Connector) makeLocalityAwareRequest(ctx context.Context, req *pub.Request) (output []*pub.Response) {
 	// Locality-aware routing
 	optimizeLocality := !req.Multicast && c.locality != ""
 	origURL := req.URL
@@ -146,7 +221,7 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) <-chan *
 	}
 
 	// Make the request
-	output := c.makeRequest(ctx, req)
+	output = c.makeRequest(ctx, req)
 
 	// Locality-aware routing
 	if optimizeLocality {
@@ -180,17 +255,64 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) <-chan *
 		}
 	}
 
-	// Return as channel
-	ch := make(chan *pub.Response, len(output))
-	for _, x := range output {
-		ch <- x
+	req.URL = origURL
+	return output
+}
+
+// retryRequest records the attempt and determines if the request should be retried based on the output of the attempt.
+// If so, it awaits the backoff delay before returning.
+func (c *Connector) retryRequest(ctx context.Context, req *pub.Request, output []*pub.Response, attempt int) bool {
+	if req.RetryPolicy == nil || req.Multicast {
+		return false
+	}
+	statusCode := http.StatusOK
+	res, err := output[0].Get()
+	if err != nil {
+		statusCode = errors.StatusCode(err)
+	} else if res != nil {
+		statusCode = res.StatusCode
+	}
+
+	// OpenTelemetry: record the attempt
+	c.Span(ctx).AddEvent("Request attempt",
+		"url", req.Canonical(),
+		"attempt", attempt,
+		"status", statusCode,
+	)
+
+	if attempt >= req.RetryPolicy.MaxAttempts || !req.RetryPolicy.Retriable(statusCode, err) {
+		return false
+	}
+	delay := req.RetryPolicy.Delay(attempt + 1)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline)-delay <= c.networkHop {
+		// Not enough time budget for another attempt
+		return false
+	}
+	if res != nil && res.Body != nil {
+		res.Body.Close()
+	}
+	c.LogDebug(ctx, "Retrying request",
+		"url", req.Canonical(),
+		"attempt", attempt+1,
+		"status", statusCode,
+		"delay", delay,
+	)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
-	close(ch)
-	return ch
 }
 
-// makeRequest makes an HTTP request over NATS, then awaits and pushes the responses to the output channel.
+// makeRequest makes an HTTP request over the transport, then awaits and pushes the responses to the output channel.
 func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output []*pub.Response) {
+	// Rewind the body in case the request is sent more than once
+	if bodyReader, ok := req.Body.(*httpx.BodyReader); ok {
+		bodyReader.Reset()
+	}
 	if req.Multicast {
 		output = make([]*pub.Response, 0, c.multicastChanCap)
 	} else {
@@ -216,8 +338,33 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 		frame.Of(httpReq).SetTimeBudget(time.Until(deadline))
 	}
 
-	// Fragment large requests
-	fragger, err := httpx.NewFragRequest(httpReq, c.maxFragmentSize)
+	// Encode the body as CBOR if the destination is known to accept it
+	err = c.encodeRequestCBOR(httpReq, req)
+	if err != nil {
+		err = errors.Trace(err)
+		output = append(output, pub.NewErrorResponse(err))
+		return output
+	}
+
+	// Compress large requests before fragmenting them
+	err = c.compressRequest(httpReq)
+	if err != nil {
+		err = errors.Trace(err)
+		output = append(output, pub.NewErrorResponse(err))
+		return output
+	}
+
+	// Fragment large requests.
+	// The body of a unicast request is streamed rather than read into memory in full
+	// because the fragments are sent only once, to the single responder
+	var fragger *httpx.FragRequest
+	if req.Multicast {
+		fragger, err = httpx.NewFragRequest(httpReq, c.maxFragmentSize)
+	} else {
+		// Ask the responder to acknowledge fragments as it takes them in, to hold back a slow consumer
+		frame.Of(httpReq).SetFragmentWindow(streamFragmentWindow)
+		fragger, err = httpx.NewStreamedFragRequest(httpReq, c.maxFragmentSize)
+	}
 	if err != nil {
 		err = errors.Trace(err)
 		output = append(output, pub.NewErrorResponse(err))
@@ -243,8 +390,14 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 			break
 		}
 	}
+	hedgeMsgID := ""
+	var ackedFragments atomic.Int32
+	fragmentAcked := make(chan bool, 1)
 	defer func() {
 		c.reqs.Delete(msgID)
+		if hedgeMsgID != "" {
+			c.reqs.Delete(hedgeMsgID)
+		}
 		close(awaitCh.Done)
 	}()
 
@@ -258,8 +411,19 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 	}
 	subject := subjectOfRequest(c.plane, httpReq.Method, httpReq.URL.Hostname(), port, httpReq.URL.Path)
 
-	var buf bytes.Buffer
 	frame.Of(httpReq).SetMessageID(msgID)
+
+	// Encrypt each fragment separately, once its message ID is set
+	if req.Encrypted {
+		err = c.encryptRequest(httpReq)
+		if err != nil {
+			err = errors.Trace(err)
+			output = append(output, pub.NewErrorResponse(err))
+			return output
+		}
+	}
+
+	var buf bytes.Buffer
 	err = httpReq.WriteProxy(&buf)
 	if err != nil {
 		err = errors.Trace(err)
@@ -274,7 +438,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 	)
 
 	publishTime := time.Now()
-	err = c.natsConn.Publish(subject, buf.Bytes())
+	err = c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
 	if err != nil {
 		err = errors.Trace(err)
 		output = append(output, pub.NewErrorResponse(err))
@@ -282,7 +446,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 	}
 
 	// Await and return the responses
-	enumResponders := func(responders map[string]bool) string {
+	enumResponders := func(responders map[string]string) string {
 		var b strings.Builder
 		for k := range responders {
 			if b.Len() != 0 {
@@ -293,7 +457,23 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 		return b.String()
 	}
 
-	var expectedResponders map[string]bool
+	if req.Multicast && req.Quorum > 0 {
+		defer func() {
+			successes := 0
+			for _, r := range output {
+				if _, err := r.Get(); err == nil {
+					successes++
+				}
+			}
+			if successes < req.Quorum {
+				// The error comes first so that it is not mistaken for one of the responses
+				err := errors.Newcf(http.StatusServiceUnavailable, "quorum of %d not reached", req.Quorum)
+				output = append([]*pub.Response{pub.NewErrorResponse(err)}, output...)
+			}
+		}()
+	}
+
+	var expectedResponders map[string]string
 	if req.Multicast {
 		expectedResponders, _ = c.knownResponders.Load(subject, lru.Bump(true))
 		if len(expectedResponders) > 0 {
@@ -306,8 +486,10 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 		c.postRequestData.Store("multicast:"+msgID, subject)
 	}
 	countResponses := 0
-	seenIDs := map[string]string{} // FromID -> OpCode
-	seenQueues := map[string]bool{}
+	countSuccesses := 0
+	matched := false
+	seenIDs := map[string]string{}    // FromID -> OpCode
+	seenQueues := map[string]string{} // Queue -> FromID
 	doneWaitingForAcks := false
 	var timeoutTimer *time.Timer
 	if deadlineOK {
@@ -322,6 +504,16 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 	ackTimer := time.NewTimer(c.ackTimeout)
 	defer ackTimer.Stop()
 	ackTimerStart := time.Now()
+	var hedgeTimer *time.Timer
+	if req.HedgeDelay > 0 && !req.Multicast && fragger.N() == 1 {
+		hedgeTimer = time.NewTimer(req.HedgeDelay)
+		defer hedgeTimer.Stop()
+	} else {
+		// No op timer
+		hedgeTimer = &time.Timer{
+			C: make(<-chan time.Time),
+		}
+	}
 	for {
 		select {
 		case response := <-awaitCh.C:
@@ -329,13 +521,23 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 			fromID := frame.Of(response).FromID()
 			queue := frame.Of(response).Queue()
 
+			// Acknowledgement of a fragment of a streamed request
+			if index, _ := frame.Of(response).Fragment(); opCode == frame.OpCodeAck && index > 1 {
+				ackedFragments.Add(1)
+				select {
+				case fragmentAcked <- true:
+				default:
+				}
+				continue
+			}
+
 			// Known responders optimization
 			if req.Multicast {
-				seenQueues[queue] = true
+				seenQueues[queue] = fromID
 				if !doneWaitingForAcks && len(seenQueues) == len(expectedResponders) {
 					match := true
 					for k := range seenQueues {
-						if !expectedResponders[k] {
+						if _, ok := expectedResponders[k]; !ok {
 							match = false
 							break
 						}
@@ -357,11 +559,33 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 					seenIDs[fromID] = frame.OpCodeAck
 				}
 
-				// Send additional fragments (if there are any) in a goroutine
-				if fragger.N() > 1 {
+				// Send additional fragments (if there are any) in a goroutine.
+				// The number of fragments of a streamed request is not known until its final fragment
+				// Fragments are held back while the responder is a full window behind in acknowledging them
+				if fragger.N() != 1 {
+					window := int32(frame.Of(response).FragmentWindow())
 					go func() {
-						for f := 2; f <= fragger.N(); f++ {
+					sending:
+						for f := 2; fragger.N() == 0 || f <= fragger.N(); f++ {
+							for window > 0 && int32(f-2)-ackedFragments.Load() >= window {
+								select {
+								case <-fragmentAcked:
+								case <-awaitCh.Done:
+									break sending
+								case <-ctx.Done():
+									break sending
+								}
+							}
+							if ctx.Err() != nil {
+								break
+							}
 							fragment, err := fragger.Fragment(f)
+							if err == nil {
+								frame.Of(fragment).SetMessageID(msgID)
+								if req.Encrypted {
+									err = c.encryptRequest(fragment)
+								}
+							}
 							if err != nil {
 								err = errors.Trace(err)
 								c.LogError(ctx, "Sending fragments",
@@ -376,7 +600,6 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 							subject := subjectOfRequest(c.plane, fragment.Method, fromID+"."+fragment.URL.Hostname(), port, fragment.URL.Path)
 
 							var buf bytes.Buffer
-							frame.Of(fragment).SetMessageID(msgID)
 							err = fragment.WriteProxy(&buf)
 							if err != nil {
 								err = errors.Trace(err)
@@ -387,7 +610,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 								)
 								break
 							}
-							err = c.natsConn.Publish(subject, buf.Bytes())
+							err = c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
 							if err != nil {
 								err = errors.Trace(err)
 								c.LogError(ctx, "Sending fragments",
@@ -404,7 +627,17 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 
 			// Response
 			if opCode == frame.OpCodeResponse {
+				if stream, ok := response.Body.(*httpx.StreamReader); ok {
+					// End the stream if the caller's context is done before all fragments arrive
+					context.AfterFunc(ctx, func() {
+						stream.CloseWithError(errors.Newc(http.StatusRequestTimeout, "timeout"))
+					})
+				}
 				output = append(output, pub.NewHTTPResponse(response))
+				countSuccesses++
+				if req.UntilMatch != nil && !matched {
+					matched = req.UntilMatch(response)
+				}
 				_ = c.IncrementMetric(
 					"microbus_request_count_total",
 					1,
@@ -429,7 +662,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 				} else {
 					err = errors.Convert(reconstitutedError)
 				}
-				output = append(output, pub.NewErrorResponse(err))
+				output = append(output, pub.NewRemoteErrorResponse(err))
 				statusCode := reconstitutedError.StatusCode
 				if statusCode == 0 {
 					statusCode = http.StatusInternalServerError
@@ -453,6 +686,15 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 			// Response or error (i.e. not an ack)
 			if opCode == frame.OpCodeResponse || opCode == frame.OpCodeError {
 				if !req.Multicast {
+					if hedgeMsgID != "" && frame.Of(response).MessageID() == hedgeMsgID {
+						_ = c.IncrementMetric(
+							"microbus_hedge_won_total",
+							1,
+							httpReq.Method,
+							httpReq.URL.Hostname(),
+							port,
+						)
+					}
 					// Return the first result found immediately
 					return output
 				}
@@ -469,8 +711,76 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 					)
 					return output
 				}
+				if (req.FirstN > 0 && countResponses >= req.FirstN) ||
+					(req.Quorum > 0 && countSuccesses >= req.Quorum) ||
+					matched {
+					// Return early without waiting for the remaining responders.
+					// Late responses are expected and should not invalidate the known responders
+					c.postRequestData.Delete("multicast:" + msgID)
+					c.LogDebug(ctx, "Returning early",
+						"msg", msgID,
+						"subject", subject,
+						"responses", countResponses,
+					)
+					return output
+				}
 			}
 
+		// Hedge timer
+		case <-hedgeTimer.C:
+			// Send a second copy of the request under a new message ID that shares the same channel
+			for {
+				hedgeMsgID = rand.AlphaNum64(8)
+				_, exists := c.reqs.LoadOrStore(hedgeMsgID, awaitCh)
+				if !exists {
+					break
+				}
+			}
+			if bodyReader, ok := httpReq.Body.(*httpx.BodyReader); ok {
+				bodyReader.Reset()
+			}
+			if deadlineOK {
+				frame.Of(httpReq).SetTimeBudget(time.Until(deadline))
+			}
+			var buf bytes.Buffer
+			if req.Encrypted {
+				// The body is bound to the message ID and must be encrypted again under the new one
+				_, err = c.decryptRequest(httpReq)
+				if err == nil {
+					frame.Of(httpReq).SetMessageID(hedgeMsgID)
+					err = c.encryptRequest(httpReq)
+				}
+			} else {
+				frame.Of(httpReq).SetMessageID(hedgeMsgID)
+			}
+			if err == nil {
+				err = httpReq.WriteProxy(&buf)
+			}
+			if err == nil {
+				err = c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
+			}
+			if err != nil {
+				err = errors.Trace(err)
+				c.LogError(ctx, "Hedging request",
+					"error", err,
+					"url", req.Canonical(),
+					"method", req.Method,
+				)
+				break
+			}
+			c.LogDebug(ctx, "Hedging request",
+				"msg", msgID,
+				"hedge", hedgeMsgID,
+				"url", req.Canonical(),
+			)
+			_ = c.IncrementMetric(
+				"microbus_hedge_fired_total",
+				1,
+				httpReq.Method,
+				httpReq.URL.Hostname(),
+				port,
+			)
+
 		// Timeout timer
 		case <-timeoutTimer.C:
 			c.LogDebug(ctx, "Request timeout",
@@ -523,7 +833,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 						"subject", subject,
 					)
 				} else {
-					err = errors.Newc(http.StatusNotFound, "ack timeout")
+					err = errors.Tracec(http.StatusNotFound, pub.ErrAckTimeout)
 					output = append(output, pub.NewErrorResponse(err))
 					_ = c.IncrementMetric(
 						"microbus_request_count_total",
@@ -555,7 +865,17 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) (output [
 }
 
 // onResponse is called when a response to an outgoing request is received.
-func (c *Connector) onResponse(msg *nats.Msg) {
+func (c *Connector) onResponse(msg *transport.Msg) {
+	// Verify the signature
+	err := c.verifyMessage(msg.Subject, msg.Data, signatureTolerance)
+	if err != nil {
+		c.LogWarn(c.lifetimeCtx, "Dropping response",
+			"error", err,
+			"subject", msg.Subject,
+		)
+		return
+	}
+
 	// Parse the response
 	response, err := http.ReadResponse(bufio.NewReaderSize(bytes.NewReader(msg.Data), 64), nil)
 	if err != nil {
@@ -563,6 +883,16 @@ func (c *Connector) onResponse(msg *nats.Msg) {
 		c.LogError(c.lifetimeCtx, "Parsing response", "error", err)
 		return
 	}
+	response.Header.Del(frame.HeaderSignature)
+
+	// Decrypt each fragment separately
+	err = c.decryptResponse(response)
+	if err != nil {
+		err = errors.Trace(err)
+		c.LogError(c.lifetimeCtx, "Decrypting response", "error", err)
+		// Deliver an error to the caller rather than leave it waiting
+		response = c.undecryptableResponse(response, err)
+	}
 
 	// Integrate fragments together
 	response, err = c.defragResponse(response)
@@ -575,6 +905,13 @@ func (c *Connector) onResponse(msg *nats.Msg) {
 		// Not all fragments arrived yet
 		return
 	}
+	err = c.decompressResponse(response)
+	if err != nil {
+		err = errors.Trace(err)
+		c.LogError(c.lifetimeCtx, "Decompressing response", "error", err)
+		return
+	}
+	c.notePeerCBOR(response)
 
 	// Push it to the channel matching the message ID
 	msgID := frame.Of(response).MessageID()
@@ -584,6 +921,7 @@ func (c *Connector) onResponse(msg *nats.Msg) {
 			// First cap(ch.C) messages can be pushed safely without blocking
 			ch.C <- response
 			ch.Pushed++
+			return
 		} else {
 			// More messages can block, so need to listen to the Done channel
 			select {
@@ -597,6 +935,9 @@ func (c *Connector) onResponse(msg *nats.Msg) {
 	}
 
 	// Handle message that arrive after the request is done.
+	if stream, ok := response.Body.(*httpx.StreamReader); ok {
+		stream.Close()
+	}
 	opCode := frame.Of(response).OpCode()
 	if opCode != frame.OpCodeAck {
 		subject, ok := c.postRequestData.Load("multicast:"+msgID, lru.NoBump())
@@ -616,3 +957,42 @@ func (c *Connector) onResponse(msg *nats.Msg) {
 		}
 	}
 }
+
+// serializeRequest serializes the request in full, along with its body, rather than in fragments.
+// It is used for requests that are held on to and delivered later.
+func (c *Connector) serializeRequest(req *pub.Request) (httpReq *http.Request, data []byte, err error) {
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	httpReq, err = http.NewRequest(req.Method, req.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for name, value := range req.Header {
+		httpReq.Header[name] = value
+	}
+	// Stop the http package from setting Go-http-client/1.1 as the user-agent
+	if len(httpReq.Header.Values("User-Agent")) == 0 {
+		httpReq.Header.Set("User-Agent", "")
+	}
+	frame.Of(httpReq).SetMessageID(rand.AlphaNum64(8))
+	if req.Encrypted {
+		err = c.encryptRequest(httpReq)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	var buf bytes.Buffer
+	err = httpReq.WriteProxy(&buf)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if int64(buf.Len()) > c.maxFragmentSize {
+		return nil, nil, errors.Newc(http.StatusRequestEntityTooLarge, "request too large")
+	}
+	return httpReq, buf.Bytes(), nil
+}
@@ -904,6 +904,110 @@ func TestConnector_MulticastError(t *testing.T) {
 	testarossa.Equal(t, 1, countOKs)
 }
 
+func TestConnector_MulticastEarlyReturn(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	delays := []time.Duration{0, 300 * time.Millisecond, 600 * time.Millisecond, 1500 * time.Millisecond}
+	var responders []*Connector
+	for i := range delays {
+		con := New("early.return.connector")
+		con.Subscribe("GET", "cast", func(w http.ResponseWriter, r *http.Request) error {
+			time.Sleep(delays[i])
+			if i == 1 {
+				return errors.New("bad situation")
+			}
+			w.Header().Set("Responder", strconv.Itoa(i))
+			return nil
+		}, sub.NoQueue())
+		responders = append(responders, con)
+	}
+	client := New("client.early.return.connector")
+
+	// Startup the microservices
+	for _, con := range append(responders, client) {
+		err := con.Startup()
+		testarossa.NoError(t, err)
+		defer con.Shutdown()
+	}
+
+	publish := func(options ...pub.Option) (countOKs int, countErrs int, dur time.Duration) {
+		t0 := time.Now()
+		options = append(options, pub.GET("https://early.return.connector/cast"))
+		for r := range client.Publish(ctx, options...) {
+			_, err := r.Get()
+			if err != nil {
+				countErrs++
+			} else {
+				countOKs++
+			}
+		}
+		return countOKs, countErrs, time.Since(t0)
+	}
+
+	// All responses are collected by default, which also caches the known responders
+	countOKs, countErrs, dur := publish()
+	testarossa.Equal(t, 3, countOKs)
+	testarossa.Equal(t, 1, countErrs)
+	testarossa.True(t, dur >= delays[3])
+	subject := subjectOfRequest(client.plane, "GET", "early.return.connector", "443", "/cast")
+	_, ok := client.knownResponders.Load(subject)
+	testarossa.True(t, ok)
+
+	// First responses, whether successful or errors
+	countOKs, countErrs, dur = publish(pub.FirstN(2))
+	testarossa.Equal(t, 1, countOKs)
+	testarossa.Equal(t, 1, countErrs)
+	testarossa.True(t, dur >= delays[1] && dur < delays[3])
+
+	// Successful responses
+	countOKs, countErrs, dur = publish(pub.Quorum(2))
+	testarossa.Equal(t, 2, countOKs)
+	testarossa.Equal(t, 1, countErrs)
+	testarossa.True(t, dur >= delays[2] && dur < delays[3])
+
+	// Quorum not reached
+	countOKs, countErrs, _ = publish(pub.Quorum(4))
+	testarossa.Equal(t, 3, countOKs)
+	testarossa.Equal(t, 2, countErrs)
+	first := <-client.Publish(ctx, pub.GET("https://early.return.connector/cast"), pub.Quorum(4))
+	_, err := first.Get()
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+
+	// Matching response
+	countOKs, countErrs, dur = publish(pub.UntilMatch(func(res *http.Response) bool {
+		return res.Header.Get("Responder") == "2"
+	}))
+	testarossa.Equal(t, 2, countOKs)
+	testarossa.Equal(t, 1, countErrs)
+	testarossa.True(t, dur >= delays[2] && dur < delays[3])
+
+	// Late responses should not invalidate the known responders
+	time.Sleep(delays[3])
+	_, ok = client.knownResponders.Load(subject)
+	testarossa.True(t, ok)
+
+	// Invalid options
+	_, countErrs, _ = publish(pub.FirstN(0))
+	testarossa.Equal(t, 1, countErrs)
+	_, countErrs, _ = publish(pub.Quorum(-1))
+	testarossa.Equal(t, 1, countErrs)
+	_, countErrs, _ = publish(pub.UntilMatch(nil))
+	testarossa.Equal(t, 1, countErrs)
+
+	// Unicast requests do not accept the options
+	for _, opt := range []pub.Option{
+		pub.FirstN(1),
+		pub.Quorum(1),
+		pub.UntilMatch(func(res *http.Response) bool { return true }),
+	} {
+		_, err = client.Request(ctx, pub.GET("https://early.return.connector/cast"), opt)
+		testarossa.Error(t, err)
+	}
+}
+
 func TestConnector_MulticastNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -1006,7 +1110,7 @@ func BenchmarkConnector_NATSDirectPublishing(b *testing.B) {
 	body := make([]byte, 512*1024)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		con.natsConn.Publish("somewhere", body)
+		con.transportConn.Publish("somewhere", body)
 	}
 	b.StopTimer()
 
@@ -1346,3 +1450,172 @@ func TestConnector_MultiValueHeader(t *testing.T) {
 	testarossa.NoError(t, err)
 	testarossa.SliceLen(t, response.Header["Multi-Value-Out"], 3)
 }
+
+func TestConnector_Retry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	var attempts atomic.Int32
+	con := New("retry.connector")
+	con.Subscribe("POST", "flaky", func(w http.ResponseWriter, r *http.Request) error {
+		body, _ := io.ReadAll(r.Body)
+		if attempts.Add(1)%3 != 0 {
+			return errors.Newc(http.StatusServiceUnavailable, "unavailable")
+		}
+		w.Write(body)
+		return nil
+	})
+	con.Subscribe("GET", "fail", func(w http.ResponseWriter, r *http.Request) error {
+		attempts.Add(1)
+		return errors.New("failed")
+	})
+	con.Subscribe("GET", "missing", func(w http.ResponseWriter, r *http.Request) error {
+		attempts.Add(1)
+		return errors.Newc(http.StatusNotFound, "not found")
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Succeed on the third attempt, sending the body each time
+	attempts.Store(0)
+	res, err := con.Request(ctx,
+		pub.POST("https://retry.connector/flaky"),
+		pub.Body("Hello"),
+		pub.Retry(3, 10*time.Millisecond, nil),
+	)
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "Hello", string(b))
+	}
+	testarossa.Equal(t, int32(3), attempts.Load())
+
+	// Give up after the max attempts
+	attempts.Store(0)
+	_, err = con.Request(ctx,
+		pub.POST("https://retry.connector/flaky"),
+		pub.Retry(2, 10*time.Millisecond, nil),
+	)
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	testarossa.Equal(t, int32(2), attempts.Load())
+
+	// Non-retriable status code
+	attempts.Store(0)
+	_, err = con.Request(ctx,
+		pub.GET("https://retry.connector/fail"),
+		pub.Retry(3, 10*time.Millisecond, nil),
+	)
+	testarossa.Equal(t, http.StatusInternalServerError, errors.StatusCode(err))
+	testarossa.Equal(t, int32(1), attempts.Load())
+
+	// A 404 returned by the handler is not an ack timeout
+	attempts.Store(0)
+	_, err = con.Request(ctx,
+		pub.GET("https://retry.connector/missing"),
+		pub.Retry(3, 10*time.Millisecond, nil),
+	)
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	testarossa.False(t, pub.IsAckTimeout(err))
+	testarossa.Equal(t, int32(1), attempts.Load())
+
+	// Custom predicate
+	attempts.Store(0)
+	_, err = con.Request(ctx,
+		pub.GET("https://retry.connector/fail"),
+		pub.Retry(3, 10*time.Millisecond, func(statusCode int) bool {
+			return statusCode == http.StatusInternalServerError
+		}),
+	)
+	testarossa.Error(t, err)
+	testarossa.Equal(t, int32(3), attempts.Load())
+
+	// Ack timeouts are retried by default
+	t0 := time.Now()
+	_, err = con.Request(ctx,
+		pub.GET("https://retry.connector/nowhere"),
+		pub.Retry(2, 10*time.Millisecond, nil),
+	)
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	testarossa.True(t, pub.IsAckTimeout(err))
+	testarossa.True(t, time.Since(t0) >= 2*con.ackTimeout)
+
+	// Retries stay within the time budget
+	attempts.Store(0)
+	shortCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = con.Request(shortCtx,
+		pub.POST("https://retry.connector/flaky"),
+		pub.Retry(3, 2*time.Second, nil),
+	)
+	testarossa.Equal(t, http.StatusServiceUnavailable, errors.StatusCode(err))
+	testarossa.Equal(t, int32(1), attempts.Load())
+}
+
+func TestConnector_Hedge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	var calls atomic.Int32
+	con := New("hedge.connector")
+	con.Subscribe("GET", "slow-first", func(w http.ResponseWriter, r *http.Request) error {
+		if calls.Add(1) == 1 {
+			time.Sleep(time.Second)
+			w.Write([]byte("first"))
+			return nil
+		}
+		w.Write([]byte("hedge"))
+		return nil
+	})
+	con.Subscribe("GET", "fast", func(w http.ResponseWriter, r *http.Request) error {
+		calls.Add(1)
+		return nil
+	})
+	con.Subscribe("POST", "fast", func(w http.ResponseWriter, r *http.Request) error {
+		calls.Add(1)
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// The hedged copy responds first
+	calls.Store(0)
+	t0 := time.Now()
+	res, err := con.Request(ctx,
+		pub.GET("https://hedge.connector/slow-first"),
+		pub.Hedge(100*time.Millisecond),
+	)
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "hedge", string(b))
+	}
+	testarossa.True(t, time.Since(t0) < time.Second)
+	testarossa.Equal(t, int32(2), calls.Load())
+
+	// No hedge if the response arrives in time
+	calls.Store(0)
+	_, err = con.Request(ctx,
+		pub.GET("https://hedge.connector/fast"),
+		pub.Hedge(200*time.Millisecond),
+	)
+	testarossa.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+	testarossa.Equal(t, int32(1), calls.Load())
+
+	// Non-idempotent methods cannot be hedged
+	calls.Store(0)
+	_, err = con.Request(ctx,
+		pub.POST("https://hedge.connector/fast"),
+		pub.Hedge(100*time.Millisecond),
+	)
+	testarossa.Error(t, err)
+	testarossa.Equal(t, int32(0), calls.Load())
+}
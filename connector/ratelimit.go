@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// replicasRefreshInterval is the interval at which the replicas of the microservice are counted
+// in order to divide cluster-wide rate limits among them.
+const replicasRefreshInterval = 10 * time.Second
+
+// tokenBucket limits the rate of requests to a subscription.
+// The bucket fills up with tokens at a constant rate up to the burst size, and each request takes one token.
+type tokenBucket struct {
+	policy *sub.RateLimitPolicy
+	mux    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a new token bucket based on the rate limit of the subscription, or nil if it has none.
+func newTokenBucket(s *sub.Subscription) *tokenBucket {
+	if s.RateLimit == nil {
+		return nil
+	}
+	return &tokenBucket{
+		policy: s.RateLimit,
+	}
+}
+
+// take takes a token from the bucket, if one is available.
+// The rate and the burst of a cluster-wide limit are divided among the replicas.
+// If no token is available, retryAfter indicates when the next one will be.
+func (tb *tokenBucket) take(now time.Time, replicas int) (ok bool, retryAfter time.Duration) {
+	rate := tb.policy.Rate
+	burst := float64(tb.policy.Burst)
+	if tb.policy.Cluster && replicas > 1 {
+		rate /= float64(replicas)
+		burst = math.Max(1, burst/float64(replicas))
+	}
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	if tb.last.IsZero() {
+		tb.tokens = burst
+	} else {
+		tb.tokens = math.Min(burst, tb.tokens+now.Sub(tb.last).Seconds()*rate)
+	}
+	tb.last = now
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - tb.tokens) / rate * float64(time.Second))
+}
+
+// replicaCount returns the number of replicas of the microservice, including this one, as last counted.
+// The count is refreshed in the background when it grows stale.
+func (c *Connector) replicaCount() int {
+	c.replicasLock.Lock()
+	defer c.replicasLock.Unlock()
+	if time.Since(c.replicasCountedAt) >= replicasRefreshInterval {
+		c.replicasCountedAt = time.Now()
+		go c.countReplicas()
+	}
+	if c.replicas < 1 {
+		return 1
+	}
+	return c.replicas
+}
+
+// countReplicas counts the replicas of the microservice by pinging them.
+// The count is discarded if a count that started later completed first.
+func (c *Connector) countReplicas() {
+	startedAt := time.Now()
+	// Forget the known responders in order to wait for replicas that joined since the last count
+	c.knownResponders.Delete(subjectOfRequest(c.plane, "POST", c.hostname, "888", "/ping"))
+	n := 0
+	for r := range c.Publish(c.lifetimeCtx, pub.POST("https://"+c.hostname+":888/ping"), pub.Body(struct{}{})) {
+		_, err := r.Get()
+		if err == nil {
+			n++
+		}
+	}
+	c.replicasLock.Lock()
+	if startedAt.After(c.replicasAsOf) {
+		c.replicas = n
+		c.replicasAsOf = startedAt
+	}
+	c.replicasLock.Unlock()
+}
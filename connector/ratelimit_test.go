@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_TokenBucket(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Now()
+	tb := newTokenBucket(&sub.Subscription{RateLimit: &sub.RateLimitPolicy{Rate: 10, Burst: 3}})
+
+	// Burst
+	for i := 0; i < 3; i++ {
+		ok, _ := tb.take(t0, 1)
+		testarossa.True(t, ok)
+	}
+	ok, retryAfter := tb.take(t0, 1)
+	testarossa.False(t, ok)
+	testarossa.Equal(t, 100*time.Millisecond, retryAfter)
+
+	// Refill at the rate
+	ok, _ = tb.take(t0.Add(100*time.Millisecond), 1)
+	testarossa.True(t, ok)
+	ok, _ = tb.take(t0.Add(100*time.Millisecond), 1)
+	testarossa.False(t, ok)
+
+	// Refill up to the burst
+	for i := 0; i < 3; i++ {
+		ok, _ := tb.take(t0.Add(time.Hour), 1)
+		testarossa.True(t, ok)
+	}
+	ok, _ = tb.take(t0.Add(time.Hour), 1)
+	testarossa.False(t, ok)
+
+	// Local limits are not divided among replicas
+	tb = newTokenBucket(&sub.Subscription{RateLimit: &sub.RateLimitPolicy{Rate: 10, Burst: 4}})
+	for i := 0; i < 4; i++ {
+		ok, _ := tb.take(t0, 2)
+		testarossa.True(t, ok)
+	}
+
+	// Cluster-wide limits are divided among replicas
+	tb = newTokenBucket(&sub.Subscription{RateLimit: &sub.RateLimitPolicy{Rate: 10, Burst: 4, Cluster: true}})
+	for i := 0; i < 2; i++ {
+		ok, _ := tb.take(t0, 2)
+		testarossa.True(t, ok)
+	}
+	ok, retryAfter = tb.take(t0, 2)
+	testarossa.False(t, ok)
+	testarossa.Equal(t, 200*time.Millisecond, retryAfter)
+
+	// No limit
+	tb = newTokenBucket(&sub.Subscription{})
+	testarossa.Nil(t, tb)
+}
+
+func TestConnector_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("rate.limit.connector")
+	con.Subscribe("GET", "limited", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}, sub.RateLimit(20, 3))
+	con.Subscribe("GET", "unlimited", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Burst
+	for i := 0; i < 3; i++ {
+		_, err = con.GET(ctx, "https://rate.limit.connector/limited")
+		testarossa.NoError(t, err)
+	}
+	_, err = con.GET(ctx, "https://rate.limit.connector/limited")
+	testarossa.Equal(t, http.StatusTooManyRequests, errors.StatusCode(err))
+	for i := 0; i < 10; i++ {
+		_, err = con.GET(ctx, "https://rate.limit.connector/unlimited")
+		testarossa.NoError(t, err)
+	}
+
+	// Refill
+	time.Sleep(100 * time.Millisecond)
+	_, err = con.GET(ctx, "https://rate.limit.connector/limited")
+	testarossa.NoError(t, err)
+}
+
+func TestConnector_ClusterRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservices
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}
+	replica1 := New("cluster.rate.limit.connector")
+	replica1.Subscribe("GET", "limited", handler, sub.ClusterRateLimit(0.001, 4))
+	replica2 := New("cluster.rate.limit.connector")
+	replica2.Subscribe("GET", "limited", handler, sub.ClusterRateLimit(0.001, 4))
+	client := New("cluster.rate.limit.client.connector")
+
+	// Startup the microservices
+	err := replica1.Startup()
+	testarossa.NoError(t, err)
+	defer replica1.Shutdown()
+	err = replica2.Startup()
+	testarossa.NoError(t, err)
+	defer replica2.Shutdown()
+	err = client.Startup()
+	testarossa.NoError(t, err)
+	defer client.Shutdown()
+
+	// The replicas count each other
+	replica1.countReplicas()
+	replica2.countReplicas()
+	testarossa.Equal(t, 2, replica1.replicaCount())
+	testarossa.Equal(t, 2, replica2.replicaCount())
+
+	// Each replica allows half of the burst
+	admitted := 0
+	for i := 0; i < 20; i++ {
+		_, err = client.GET(ctx, "https://cluster.rate.limit.connector/limited")
+		if err == nil {
+			admitted++
+		} else {
+			testarossa.Equal(t, http.StatusTooManyRequests, errors.StatusCode(err))
+		}
+	}
+	testarossa.Equal(t, 4, admitted)
+}
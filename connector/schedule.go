@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// schedulerHost is the hostname of the scheduler core microservice
+const schedulerHost = "scheduler.core"
+
+// scheduleRequest hands the request over to the scheduler core microservice to be delivered at a later time.
+// The response is that of the scheduler.
+func (c *Connector) scheduleRequest(ctx context.Context, req *pub.Request) <-chan *pub.Response {
+	deliverAt := req.DeliverAt
+	if req.DeliverAfter > 0 {
+		deliverAt = c.Now(ctx).Add(req.DeliverAfter)
+	}
+	_, data, err := c.serializeRequest(req)
+	if err != nil {
+		errOutput := make(chan *pub.Response, 1)
+		errOutput <- pub.NewErrorResponse(errors.Trace(err))
+		close(errOutput)
+		return errOutput
+	}
+	c.LogDebug(ctx, "Scheduling request",
+		"url", req.Canonical(),
+		"method", req.Method,
+		"deliverAt", deliverAt,
+	)
+	var schedule struct {
+		DeliverAt time.Time `json:"deliverAt"`
+		Request   []byte    `json:"request"`
+		Multicast bool      `json:"multicast"`
+	}
+	schedule.DeliverAt = deliverAt
+	schedule.Request = data
+	schedule.Multicast = req.Multicast
+	return c.Publish(
+		ctx,
+		pub.POST("https://"+schedulerHost+"/schedule"),
+		pub.Body(schedule),
+		pub.Unicast(),
+	)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+)
+
+// signatureTolerance is the duration by which the time of signing of a message may differ from the local time,
+// to allow for clock skew between hosts and for the time the message is in transit.
+const signatureTolerance = time.Minute
+
+// signatureLinePrefix starts the header line that carries the signature of a message.
+// The line is inserted immediately after the request or status line of the serialized HTTP message.
+var signatureLinePrefix = []byte(frame.HeaderSignature + ": ")
+
+/*
+SetSigningKeys sets the secret keys with which messages are signed and verified, separated by commas or spaces.
+The keys must be shared by all microservices of the plane. The first key signs outgoing messages and all keys are
+accepted when verifying incoming messages, which allows for key rotation.
+Explicitly setting keys overrides any value specified by the MICROBUS_SIGNING_KEYS environment variable.
+
+Signing authenticates the messages that travel over the bus, so that a process that is not in possession of the keys
+cannot pose as another microservice by setting its Microbus-From-Host header.
+*/
+func (c *Connector) SetSigningKeys(keys string) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.signingKeys = parseKeys(keys)
+	return nil
+}
+
+// SetStrictSigning drops incoming messages that are not signed when set to true.
+// Messages whose signature is invalid are dropped regardless.
+// Strict mode can also be enabled by the MICROBUS_SIGNING_STRICT environment variable.
+// The microservice fails to start in strict mode if no signing keys are set.
+func (c *Connector) SetStrictSigning(strict bool) error {
+	if c.IsStarted() {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.strictSigning = strict
+	return nil
+}
+
+// initSigning determines the signing keys and strict mode from the environment, unless explicitly set.
+// Strict mode requires signing keys, without which the microservice could not accept even its own messages.
+func (c *Connector) initSigning() error {
+	if c.signingKeys == nil {
+		c.signingKeys = parseKeys(env.Get("MICROBUS_SIGNING_KEYS"))
+	}
+	if !c.strictSigning {
+		c.strictSigning, _ = strconv.ParseBool(env.Get("MICROBUS_SIGNING_STRICT"))
+	}
+	if c.strictSigning && len(c.signingKeys) == 0 {
+		return errors.New("strict signing requires signing keys")
+	}
+	return nil
+}
+
+// parseKeys splits a list of keys separated by commas or spaces.
+func parseKeys(keys string) [][]byte {
+	result := [][]byte{}
+	for _, k := range strings.FieldsFunc(keys, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		result = append(result, []byte(k))
+	}
+	return result
+}
+
+// signMessage inserts a signature header line into a serialized HTTP message that is published to a subject,
+// if a signing key is set.
+// The signature line carries the time of signing and an HMAC-SHA256 of the plane, the subject, the time of signing
+// and the entire message, headers and body.
+func (c *Connector) signMessage(subject string, data []byte) []byte {
+	if len(c.signingKeys) == 0 {
+		return data
+	}
+	eol := bytes.Index(data, []byte("\r\n"))
+	if eol < 0 {
+		return data
+	}
+	eol += 2
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := computeSignature(c.plane, subject, timestamp, data, c.signingKeys[0])
+	signed := make([]byte, 0, len(data)+len(signatureLinePrefix)+len(timestamp)+1+len(signature)+2)
+	signed = append(signed, data[:eol]...)
+	signed = append(signed, signatureLinePrefix...)
+	signed = append(signed, timestamp...)
+	signed = append(signed, '.')
+	signed = append(signed, signature...)
+	signed = append(signed, "\r\n"...)
+	signed = append(signed, data[eol:]...)
+	return signed
+}
+
+// verifyMessage verifies the signature of a serialized HTTP message that arrived on a subject against the signing keys.
+// The time of signing must be no later than the tolerance into the future and no earlier than the max age into the past,
+// so that a captured message cannot be replayed indefinitely.
+// Messages that are not signed are accepted unless in strict mode.
+// Signed messages are rejected if no signing keys are set because they cannot be verified.
+func (c *Connector) verifyMessage(subject string, data []byte, maxAge time.Duration) error {
+	eol := bytes.Index(data, []byte("\r\n"))
+	if eol < 0 {
+		return errors.New("malformed message")
+	}
+	eol += 2
+	if !bytes.HasPrefix(data[eol:], signatureLinePrefix) {
+		if c.strictSigning {
+			return errors.New("unsigned message")
+		}
+		return nil
+	}
+	if len(c.signingKeys) == 0 {
+		return errors.New("signed message cannot be verified without signing keys")
+	}
+	end := bytes.Index(data[eol:], []byte("\r\n"))
+	if end < 0 {
+		return errors.New("malformed message")
+	}
+	end += eol
+	timestamp, signature, ok := bytes.Cut(data[eol+len(signatureLinePrefix):end], []byte("."))
+	if !ok {
+		return errors.New("malformed message signature")
+	}
+	signedAt, err := strconv.ParseInt(string(timestamp), 10, 64)
+	if err != nil {
+		return errors.New("malformed message signature")
+	}
+	now := time.Now()
+	if signedAt > now.Add(signatureTolerance).Unix() || signedAt < now.Add(-maxAge).Unix() {
+		return errors.New("message signature is outside the tolerance window")
+	}
+	unsigned := make([]byte, 0, len(data)-(end+2-eol))
+	unsigned = append(unsigned, data[:eol]...)
+	unsigned = append(unsigned, data[end+2:]...)
+	for _, key := range c.signingKeys {
+		if hmac.Equal(signature, computeSignature(c.plane, subject, string(timestamp), unsigned, key)) {
+			return nil
+		}
+	}
+	return errors.New("invalid message signature")
+}
+
+// computeSignature returns the base64url-encoded HMAC-SHA256 of the plane, the subject, the time of signing
+// and the serialized message.
+func computeSignature(plane string, subject string, timestamp string, data []byte, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(plane))
+	mac.Write([]byte{0})
+	mac.Write([]byte(subject))
+	mac.Write([]byte{0})
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	signature := make([]byte, base64.RawURLEncoding.EncodedLen(len(sum)))
+	base64.RawURLEncoding.Encode(signature, sum)
+	return signature
+}
@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_SignMessage(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("GET https://example.com/path HTTP/1.1\r\nHost: example.com\r\nMicrobus-From-Host: alpha.example\r\n\r\n")
+	subject := "plane.443.com.example.|.GET.path"
+
+	signer := New("signer.sign.message.connector")
+	signer.plane = "plane"
+	signer.SetSigningKeys("key1")
+	signed := signer.signMessage(subject, msg)
+	testarossa.True(t, bytes.Contains(signed, []byte("\r\n"+frame.HeaderSignature+": ")))
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(signed)))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "alpha.example", frame.Of(req).FromHost())
+		testarossa.NotEqual(t, "", req.Header.Get(frame.HeaderSignature))
+	}
+
+	verifier := New("verifier.sign.message.connector")
+	verifier.plane = "plane"
+	verifier.SetSigningKeys("key0, key1")
+	testarossa.NoError(t, verifier.verifyMessage(subject, signed, signatureTolerance))
+	testarossa.NoError(t, verifier.verifyMessage(subject, msg, signatureTolerance)) // Not strict
+
+	// Tampering with the message invalidates the signature
+	tampered := bytes.Replace(signed, []byte("alpha.example"), []byte("omega.example"), 1)
+	testarossa.Error(t, verifier.verifyMessage(subject, tampered, signatureTolerance))
+
+	// Messages are bound to the subject
+	testarossa.Error(t, verifier.verifyMessage("plane.443.com.example.|.DELETE.path", signed, signatureTolerance))
+
+	// Messages are bound to the time of signing
+	signature := req.Header.Get(frame.HeaderSignature)
+	timestamp, _, _ := strings.Cut(signature, ".")
+	signedAt, _ := strconv.ParseInt(timestamp, 10, 64)
+	backdated := bytes.Replace(signed, []byte(timestamp+"."), []byte(strconv.FormatInt(signedAt-1, 10)+"."), 1)
+	testarossa.Error(t, verifier.verifyMessage(subject, backdated, signatureTolerance))
+	for _, signedAt := range []int64{
+		time.Now().Add(-2 * signatureTolerance).Unix(),
+		time.Now().Add(2 * signatureTolerance).Unix(),
+	} {
+		ts := strconv.FormatInt(signedAt, 10)
+		outdated := bytes.Replace(signed, []byte(signature), []byte(ts+"."+string(computeSignature("plane", subject, ts, msg, []byte("key1")))), 1)
+		testarossa.Error(t, verifier.verifyMessage(subject, outdated, signatureTolerance))
+	}
+	ts := strconv.FormatInt(time.Now().Add(-2*signatureTolerance).Unix(), 10)
+	delayed := bytes.Replace(signed, []byte(signature), []byte(ts+"."+string(computeSignature("plane", subject, ts, msg, []byte("key1")))), 1)
+	testarossa.NoError(t, verifier.verifyMessage(subject, delayed, durableMaxAge)) // Durable messages are kept longer
+
+	// Strict mode drops unsigned messages
+	verifier.SetStrictSigning(true)
+	testarossa.Error(t, verifier.verifyMessage(subject, msg, signatureTolerance))
+	testarossa.NoError(t, verifier.verifyMessage(subject, signed, signatureTolerance))
+
+	// Keys must match
+	verifier.SetSigningKeys("key2")
+	testarossa.Error(t, verifier.verifyMessage(subject, signed, signatureTolerance))
+
+	// Messages are bound to the plane
+	verifier.SetSigningKeys("key1")
+	verifier.plane = "other"
+	testarossa.Error(t, verifier.verifyMessage(subject, signed, signatureTolerance))
+
+	// Signed messages cannot be verified without keys
+	verifier.SetSigningKeys("")
+	verifier.SetStrictSigning(false)
+	testarossa.Error(t, verifier.verifyMessage(subject, signed, signatureTolerance))
+	testarossa.NoError(t, verifier.verifyMessage(subject, msg, signatureTolerance))
+}
+
+func TestConnector_StrictSigningWithoutKeys(t *testing.T) {
+	// No parallel
+
+	env.Push("MICROBUS_SIGNING_KEYS", "")
+	defer env.Pop("MICROBUS_SIGNING_KEYS")
+
+	// Strict mode requires keys
+	con := New("strict.signing.without.keys.connector")
+	con.SetStrictSigning(true)
+	err := con.Startup()
+	testarossa.Error(t, err)
+	testarossa.False(t, con.IsStarted())
+
+	// Strict mode set in the environment also requires keys
+	env.Push("MICROBUS_SIGNING_STRICT", "true")
+	defer env.Pop("MICROBUS_SIGNING_STRICT")
+	con = New("strict.signing.without.keys.connector")
+	err = con.Startup()
+	testarossa.Error(t, err)
+	con.SetSigningKeys("secret")
+	err = con.Startup()
+	if testarossa.NoError(t, err) {
+		con.Shutdown()
+	}
+}
+
+func TestConnector_SignedMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	plane := rand.AlphaNum64(12)
+
+	// Create the microservices
+	alpha := New("alpha.signed.messages.connector")
+	alpha.SetPlane(plane)
+	alpha.SetSigningKeys("secret")
+	alpha.SetStrictSigning(true)
+	alpha.Subscribe("GET", "echo", func(w http.ResponseWriter, r *http.Request) error {
+		testarossa.Equal(t, "", r.Header.Get(frame.HeaderSignature))
+		w.Write([]byte(frame.Of(r).FromHost()))
+		return nil
+	})
+
+	beta := New("beta.signed.messages.connector")
+	beta.SetPlane(plane)
+	beta.SetSigningKeys("secret")
+
+	unsigned := New("unsigned.signed.messages.connector")
+	unsigned.SetPlane(plane)
+
+	spoofer := New("spoofer.signed.messages.connector")
+	spoofer.SetPlane(plane)
+	spoofer.SetSigningKeys("guess")
+
+	// Startup the microservices
+	for _, con := range []*Connector{alpha, beta, unsigned, spoofer} {
+		err := con.Startup()
+		testarossa.NoError(t, err)
+		defer con.Shutdown()
+	}
+
+	// Signed requests are accepted
+	res, err := beta.Request(ctx, pub.GET("https://alpha.signed.messages.connector/echo"))
+	if testarossa.NoError(t, err) {
+		b, _ := io.ReadAll(res.Body)
+		testarossa.Equal(t, "beta.signed.messages.connector", string(b))
+	}
+
+	// Unsigned requests and requests with an invalid signature are dropped
+	_, err = unsigned.Request(ctx, pub.GET("https://alpha.signed.messages.connector/echo"))
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+	_, err = spoofer.Request(ctx, pub.GET("https://alpha.signed.messages.connector/echo"))
+	testarossa.Equal(t, http.StatusNotFound, errors.StatusCode(err))
+}
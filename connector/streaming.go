@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+// Ensure interfaces
+var (
+	_ = http.ResponseWriter(&responseStreamer{})
+	_ = http.Flusher(&responseStreamer{})
+)
+
+// responseStreamer records the response of a handler while also implementing the http.Flusher interface.
+// Flushing sends the status code, headers and body written so far to the caller as fragments of a streamed response.
+// The status code and headers can no longer be changed after the first flush.
+type responseStreamer struct {
+	*httpx.ResponseRecorder
+	publish         func(res *http.Response, opCode string) error
+	maxFragmentSize int64
+	index           int
+	statusCode      int
+	header          http.Header
+	sentBytes       int64
+	err             error
+}
+
+// newResponseStreamer creates a new response streamer that sends fragments using the publish function.
+func newResponseStreamer(maxFragmentSize int64, publish func(res *http.Response, opCode string) error) *responseStreamer {
+	return &responseStreamer{
+		ResponseRecorder: httpx.NewResponseRecorder(),
+		publish:          publish,
+		maxFragmentSize:  maxFragmentSize,
+	}
+}
+
+// Flush sends the body written so far to the caller.
+// It implements the http.Flusher interface.
+func (rs *responseStreamer) Flush() {
+	if rs.err == nil {
+		rs.err = rs.send(false)
+	}
+}
+
+// Streaming indicates if the response was flushed at least once.
+func (rs *responseStreamer) Streaming() bool {
+	return rs.index > 0
+}
+
+// StatusCode returns the status code of the response.
+// Once streaming, it is the status code that was sent with the first fragment.
+func (rs *responseStreamer) StatusCode() int {
+	if rs.Streaming() {
+		return rs.statusCode
+	}
+	return rs.ResponseRecorder.StatusCode()
+}
+
+// ContentLength returns the total number of bytes written to the body of the response, including bytes already sent.
+func (rs *responseStreamer) ContentLength() int {
+	return int(rs.sentBytes) + rs.ResponseRecorder.ContentLength()
+}
+
+// End sends the remainder of the body and marks the end of the stream.
+func (rs *responseStreamer) End() error {
+	if rs.err != nil {
+		return rs.err
+	}
+	return rs.send(true)
+}
+
+// Abort ends the stream with an error response.
+func (rs *responseStreamer) Abort(errResponse *http.Response) error {
+	if rs.err != nil {
+		return rs.err
+	}
+	rs.index++
+	frame.Of(errResponse).SetFragment(rs.index, rs.index)
+	return rs.publish(errResponse, frame.OpCodeError)
+}
+
+// send sends the body written since the last flush as one or more fragments,
+// optionally marking the last of them as the final fragment of the stream.
+func (rs *responseStreamer) send(final bool) error {
+	if rs.index == 0 {
+		// Freeze the status code and headers
+		rs.statusCode = rs.ResponseRecorder.StatusCode()
+		rs.header = rs.ResponseRecorder.Header().Clone()
+		rs.header.Del("Content-Length")
+	}
+	var body []byte
+	if res := rs.ResponseRecorder.Result(); res.Body != nil {
+		body = res.Body.(*httpx.BodyReader).Bytes()
+	}
+	rs.ResponseRecorder.ClearBody()
+	for first := true; first || len(body) > 0; first = false {
+		n := min(int64(len(body)), rs.maxFragmentSize)
+		fragment := httpx.NewResponseRecorder()
+		for k, v := range rs.header {
+			fragment.Header()[k] = v
+		}
+		fragment.WriteHeader(rs.statusCode)
+		fragment.Write(body[:n])
+		body = body[n:]
+		rs.index++
+		res := fragment.Result()
+		if final && len(body) == 0 {
+			frame.Of(res).SetFragment(rs.index, rs.index)
+		} else {
+			frame.Of(res).SetFragment(rs.index, 0)
+		}
+		err := rs.publish(res, frame.OpCodeResponse)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rs.sentBytes += n
+	}
+	return nil
+}
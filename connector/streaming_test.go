@@ -0,0 +1,264 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_StreamResponse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	step := make(chan bool)
+	con := New("stream.response.connector")
+	con.Subscribe("GET", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("Hello "))
+			w.(http.Flusher).Flush()
+			<-step
+		}
+		w.Write([]byte("World"))
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// The response arrives before the handler returns
+	res, err := con.GET(ctx, "https://stream.response.connector/stream")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusAccepted, res.StatusCode)
+		testarossa.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+		testarossa.Equal(t, -1, int(res.ContentLength))
+		buf := make([]byte, 64)
+		for i := 0; i < 3; i++ {
+			n, err := res.Body.Read(buf)
+			testarossa.NoError(t, err)
+			testarossa.Equal(t, "Hello ", string(buf[:n]))
+			step <- true
+		}
+		b, err := io.ReadAll(res.Body)
+		testarossa.NoError(t, err)
+		testarossa.Equal(t, "World", string(b))
+	}
+}
+
+func TestConnector_StreamResponseFragmented(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("stream.response.fragmented.connector")
+	var bodySent []byte
+	con.Subscribe("GET", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write(bodySent[:len(bodySent)/2])
+		w.(http.Flusher).Flush()
+		w.Write(bodySent[len(bodySent)/2:])
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+	con.maxFragmentSize = 128
+	bodySent = []byte(rand.AlphaNum64(int(con.maxFragmentSize)*4 + 16))
+
+	// Flushes larger than a fragment are split
+	res, err := con.GET(ctx, "https://stream.response.fragmented.connector/stream")
+	if testarossa.NoError(t, err) {
+		b, err := io.ReadAll(res.Body)
+		testarossa.NoError(t, err)
+		testarossa.SliceEqual(t, bodySent, b)
+	}
+}
+
+func TestConnector_StreamResponseError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("stream.response.error.connector")
+	con.Subscribe("GET", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("Hello"))
+		w.(http.Flusher).Flush()
+		return errors.Newc(http.StatusConflict, "oops")
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// The error ends the stream after the status code was already sent
+	res, err := con.GET(ctx, "https://stream.response.error.connector/stream")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusOK, res.StatusCode)
+		b, err := io.ReadAll(res.Body)
+		testarossa.ErrorContains(t, err, "oops")
+		testarossa.Equal(t, http.StatusConflict, errors.StatusCode(err))
+		testarossa.Equal(t, "Hello", string(b))
+	}
+}
+
+func TestConnector_StreamResponseTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Create the microservice
+	done := make(chan bool)
+	con := New("stream.response.timeout.connector")
+	con.Subscribe("GET", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("Hello"))
+		w.(http.Flusher).Flush()
+		<-done
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+	defer close(done)
+
+	// The stream ends when the caller's context is done
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	res, err := con.GET(ctx, "https://stream.response.timeout.connector/stream")
+	if testarossa.NoError(t, err) {
+		b, err := io.ReadAll(res.Body)
+		testarossa.ErrorContains(t, err, "timeout")
+		testarossa.Equal(t, "Hello", string(b))
+	}
+}
+
+func TestConnector_StreamRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("stream.request.connector")
+	firstRead := make(chan bool)
+	con.Subscribe("PUT", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		testarossa.Equal(t, -1, int(r.ContentLength))
+		buf := make([]byte, con.maxFragmentSize)
+		_, err := io.ReadFull(r.Body, buf)
+		testarossa.NoError(t, err)
+		firstRead <- true
+		rest, err := io.ReadAll(r.Body)
+		testarossa.NoError(t, err)
+		w.Write(buf)
+		w.Write(rest)
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+	con.maxFragmentSize = 128
+
+	// The handler reads the first fragments before the entire body is available
+	bodySent := []byte(rand.AlphaNum64(int(con.maxFragmentSize)*6 + 16))
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write(bodySent[:con.maxFragmentSize*3])
+		<-firstRead
+		pw.Write(bodySent[con.maxFragmentSize*3:])
+		pw.Close()
+	}()
+	res, err := con.Request(ctx, pub.PUT("https://stream.request.connector/stream"), pub.Body(pr))
+	if testarossa.NoError(t, err) {
+		b, err := io.ReadAll(res.Body)
+		testarossa.NoError(t, err)
+		testarossa.SliceEqual(t, bodySent, b)
+	}
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n.Add(int64(n))
+	return n, err
+}
+
+func TestConnector_StreamRequestBackpressure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Create the microservice
+	con := New("stream.request.backpressure.connector")
+	release := make(chan bool)
+	con.Subscribe("PUT", "stream", func(w http.ResponseWriter, r *http.Request) error {
+		buf := make([]byte, con.maxFragmentSize)
+		_, err := io.ReadFull(r.Body, buf)
+		testarossa.NoError(t, err)
+		<-release
+		rest, err := io.ReadAll(r.Body)
+		testarossa.NoError(t, err)
+		w.Write(buf)
+		w.Write(rest)
+		return nil
+	})
+
+	// Startup the microservice
+	err := con.Startup()
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+	con.maxFragmentSize = 128
+
+	// A slow handler holds back the sender rather than having the body pile up in memory
+	bodySent := []byte(rand.AlphaNum64(int(con.maxFragmentSize) * 100))
+	source := &countingReader{r: bytes.NewReader(bodySent)}
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		testarossa.True(t, source.n.Load() <= con.maxFragmentSize*(streamFragmentWindow+4))
+		close(release)
+	}()
+	res, err := con.Request(ctx, pub.PUT("https://stream.request.backpressure.connector/stream"), pub.Body(source))
+	if testarossa.NoError(t, err) {
+		b, err := io.ReadAll(res.Body)
+		testarossa.NoError(t, err)
+		testarossa.SliceEqual(t, bodySent, b)
+	}
+}
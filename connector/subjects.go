@@ -60,6 +60,24 @@ func subjectOfRequest(plane string, method string, hostname string, port string,
 	return subjectOf(false, plane, method, hostname, port, path)
 }
 
+// subjectOfDurableSubscription is the NATS subject that a durable subscription consumes from JetStream for a given path.
+// For POST https://example.com:417/on-event the subject is microbus.d.417.com.example.|.POST.on-event .
+func subjectOfDurableSubscription(plane string, method string, hostname string, port string, path string) string {
+	return subjectOf(true, plane+".d", method, hostname, port, path)
+}
+
+// subjectOfDurableRequest is the NATS subject where a microservice publishes an outgoing durable request to be stored in JetStream.
+// For POST https://example.com:417/on-event that subject looks like microbus.d.417.com.example.|.POST.on-event .
+func subjectOfDurableRequest(plane string, method string, hostname string, port string, path string) string {
+	return subjectOf(false, plane+".d", method, hostname, port, path)
+}
+
+// subjectOfDeadLetter is the NATS subject where a durable request is moved after its deliveries are exhausted.
+// For the durable request microbus.d.417.com.example.|.POST.on-event the subject is microbus.dl.417.com.example.|.POST.on-event .
+func subjectOfDeadLetter(plane string, durableSubject string) string {
+	return plane + ".dl." + strings.TrimPrefix(durableSubject, plane+".d.")
+}
+
 // subjectOf composes the NATS subject of subscriptions and requests.
 func subjectOf(wildcards bool, plane string, method string, hostname string, port string, path string) string {
 	var sb strings.Builder
@@ -65,6 +65,12 @@ func TestConnector_SubjectOfRequest(t *testing.T) {
 	testarossa.Equal(t, "p0.80.com.example.|.GET.empty._._._", subjectOfRequest("p0", "GET", "EXAMPLE.com", "80", "empty///"))
 }
 
+func TestConnector_SubjectOfDurable(t *testing.T) {
+	testarossa.Equal(t, "p0.d.417.com.example.|.POST.on-event", subjectOfDurableRequest("p0", "POST", "EXAMPLE.com", "417", "/on-event"))
+	testarossa.Equal(t, "p0.d.417.com.example.|.POST.on-event.*", subjectOfDurableSubscription("p0", "POST", "example.com", "417", "/on-event/{id}"))
+	testarossa.Equal(t, "p0.dl.417.com.example.|.POST.on-event", subjectOfDeadLetter("p0", "p0.d.417.com.example.|.POST.on-event"))
+}
+
 func TestConnector_subjectOfResponses(t *testing.T) {
 	testarossa.Equal(t, "p0.r.com.example.1234", subjectOfResponses("p0", "example.com", "1234"))
 	testarossa.Equal(t, "p0.r.com.example.www.abcd1234", subjectOfResponses("p0", "www.example.com", "abcd1234"))
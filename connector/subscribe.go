@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -32,9 +33,9 @@ import (
 	"github.com/microbus-io/fabric/frame"
 	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
 	"github.com/microbus-io/fabric/trc"
 
-	"github.com/nats-io/nats.go"
 	"go.opentelemetry.io/otel/propagation"
 )
 
@@ -62,6 +63,19 @@ Examples of valid paths:
 	https://www.example.com:1080/path
 */
 func (c *Connector) Subscribe(method string, path string, handler sub.HTTPHandler, options ...sub.Option) error {
+	err := c.subscribe(method, path, handler, options...)
+	if err != nil {
+		return err // No trace
+	}
+	if c.IsStarted() {
+		time.Sleep(20 * time.Millisecond) // Give time for subscription activation by NATS
+	}
+	return nil
+}
+
+// subscribe assigns a function to handle web requests to the given path.
+// It does not wait for the subscription to be activated by NATS.
+func (c *Connector) subscribe(method string, path string, handler sub.HTTPHandler, options ...sub.Option) error {
 	if c.hostname == "" {
 		return c.captureInitErr(errors.New("hostname is not set"))
 	}
@@ -69,12 +83,23 @@ func (c *Connector) Subscribe(method string, path string, handler sub.HTTPHandle
 	if err != nil {
 		return c.captureInitErr(errors.Trace(err))
 	}
+	if newSub.Actor != nil {
+		err = c.defineKeysConfig(actorKeysConfig, actorKeysDescription)
+		if err != nil {
+			return c.captureInitErr(errors.Trace(err))
+		}
+	}
+	if newSub.Encrypted {
+		err = c.defineKeysConfig(payloadKeysConfig, payloadKeysDescription)
+		if err != nil {
+			return c.captureInitErr(errors.Trace(err))
+		}
+	}
 	if c.IsStarted() {
 		err := c.activateSub(newSub)
 		if err != nil {
 			return c.captureInitErr(errors.Trace(err))
 		}
-		time.Sleep(20 * time.Millisecond) // Give time for subscription activation by NATS
 	}
 	key := method + "|" + newSub.Canonical()
 	c.subsLock.Lock()
@@ -105,15 +130,23 @@ func (c *Connector) Unsubscribe(method string, path string) error {
 }
 
 // onRequest handles an incoming request. It acks it, then calls the handler to process it and responds to the caller.
-func (c *Connector) onRequest(msg *nats.Msg, s *sub.Subscription) {
-	err := c.ackRequest(msg, s)
+func (c *Connector) onRequest(msg *transport.Msg, s *sub.Subscription, tb *tokenBucket, cl *concurrencyLimiter) {
+	err := c.verifyMessage(msg.Subject, msg.Data, signatureTolerance)
+	if err != nil {
+		c.LogWarn(c.lifetimeCtx, "Dropping request",
+			"error", err,
+			"subject", msg.Subject,
+		)
+		return
+	}
+	err = c.ackRequest(msg, s)
 	if err != nil {
 		err = errors.Trace(err)
 		c.LogError(c.lifetimeCtx, "Acking request", "error", err)
 		return
 	}
 	go func() {
-		err := c.handleRequest(msg, s)
+		err := c.handleRequest(msg, s, tb, cl)
 		if err != nil {
 			err = errors.Trace(err)
 			c.LogError(c.lifetimeCtx, "Processing request", "error", err)
@@ -121,7 +154,7 @@ func (c *Connector) onRequest(msg *nats.Msg, s *sub.Subscription) {
 	}()
 }
 
-// activateSub will subscribe to NATS
+// activateSub will subscribe to the transport
 func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 	if len(s.Subs) > 0 {
 		return nil
@@ -131,14 +164,26 @@ func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	// Create the NATS subscriptions
-	handler := func(msg *nats.Msg) {
-		c.onRequest(msg, s)
+	// Durable subscriptions consume from JetStream
+	if s.Durable != nil {
+		return c.activateDurableSub(s)
+	}
+	// Create the transport subscriptions
+	tb := newTokenBucket(s)
+	if tb != nil && tb.policy.Cluster {
+		c.replicaCount() // Count the replicas ahead of the first request
+	}
+	cl := newConcurrencyLimiter(s)
+	handler := func(msg *transport.Msg) {
+		c.onRequest(msg, s, tb, cl)
 	}
 	prefixes := []string{
 		"",
 		c.id,
 	}
+	if c.version > 0 {
+		prefixes = append(prefixes, versionPrefix(c.version))
+	}
 	if c.locality != "" {
 		loc := strings.Split(c.locality, ".")
 		for i := len(loc) - 1; i >= 0; i-- {
@@ -146,19 +191,19 @@ func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 		}
 	}
 	for _, prefix := range prefixes {
-		var natsSub *nats.Subscription
+		var transportSub *transport.Subscription
 		if prefix != "" {
 			prefix += "."
 		}
 		if s.Queue != "" {
-			natsSub, err = c.natsConn.QueueSubscribe(subjectOfSubscription(c.plane, s.Method, prefix+s.Host, s.Port, s.Path), s.Queue, handler)
+			transportSub, err = c.transportConn.QueueSubscribe(subjectOfSubscription(c.plane, s.Method, prefix+s.Host, s.Port, s.Path), s.Queue, handler)
 		} else {
-			natsSub, err = c.natsConn.Subscribe(subjectOfSubscription(c.plane, s.Method, prefix+s.Host, s.Port, s.Path), handler)
+			transportSub, err = c.transportConn.Subscribe(subjectOfSubscription(c.plane, s.Method, prefix+s.Host, s.Port, s.Path), handler)
 		}
 		if err != nil {
 			break
 		}
-		s.Subs = append(s.Subs, natsSub)
+		s.Subs = append(s.Subs, transportSub)
 	}
 	if err != nil {
 		c.LogError(c.lifetimeCtx, "Activating sub",
@@ -176,7 +221,7 @@ func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 	return nil
 }
 
-// deactivateSubs unsubscribes from NATS.
+// deactivateSubs unsubscribes from the transport.
 func (c *Connector) deactivateSubs() error {
 	c.subsLock.Lock()
 	var lastErr error
@@ -190,11 +235,11 @@ func (c *Connector) deactivateSubs() error {
 	return errors.Trace(lastErr)
 }
 
-// deactivateSub unsubscribes from NATS.
+// deactivateSub unsubscribes from the transport.
 func (c *Connector) deactivateSub(s *sub.Subscription) error {
 	var lastErr error
-	for _, natsSub := range s.Subs {
-		err := natsSub.Unsubscribe()
+	for _, transportSub := range s.Subs {
+		err := transportSub.Unsubscribe()
 		if err != nil {
 			lastErr = errors.Trace(err)
 		}
@@ -217,7 +262,7 @@ func (c *Connector) deactivateSub(s *sub.Subscription) error {
 // ackRequest sends an ack response back to the caller.
 // Acks are sent as soon as a request is received to let the caller know it is
 // being processed
-func (c *Connector) ackRequest(msg *nats.Msg, s *sub.Subscription) error {
+func (c *Connector) ackRequest(msg *transport.Msg, s *sub.Subscription) error {
 	// Parse only the headers of the request
 	headerData := msg.Data
 	eoh := bytes.Index(headerData, []byte("\r\n\r\n"))
@@ -253,22 +298,50 @@ func (c *Connector) ackRequest(msg *nats.Msg, s *sub.Subscription) error {
 		queue = c.id + "." + c.hostname
 	}
 
+	// The ack prompts the caller to send the remaining fragments, which may be processed before the first one
+	if fragmentMax != 1 {
+		c.expectRequestFragments(fromID + "|" + msgID)
+	}
+
 	// Prepare and send the ack
+	status := "202 Accepted"
+	header := map[string]string{
+		frame.HeaderQueue:    queue,
+		frame.HeaderLocality: c.locality,
+	}
+	if fragmentMax != 1 {
+		status = "100 Continue"
+	}
+	if window := frame.Of(httpReq).FragmentWindow(); fragmentMax == 0 && window > 0 {
+		// The fragments of a streamed request are acknowledged as they are taken in
+		header[frame.HeaderFragmentWindow] = strconv.Itoa(window)
+	}
+	return c.publishAck(fromHost, fromID, msgID, status, header) // No trace
+}
+
+// ackFragment acknowledges that a fragment of a streamed request was taken in,
+// allowing the caller to send another fragment.
+func (c *Connector) ackFragment(fromHost string, fromID string, msgID string, index int) error {
+	header := map[string]string{
+		frame.HeaderFragment: strconv.Itoa(index) + "/0",
+	}
+	return c.publishAck(fromHost, fromID, msgID, "100 Continue", header) // No trace
+}
+
+// publishAck sends an ack with the indicated status and additional headers back to the caller.
+func (c *Connector) publishAck(fromHost string, fromID string, msgID string, status string, additionalHeader map[string]string) error {
 	var buf bytes.Buffer
 	buf.WriteString("HTTP/1.1 ")
-	if fragmentMax > 1 {
-		buf.WriteString("100 Continue")
-	} else {
-		buf.WriteString("202 Accepted")
-	}
+	buf.WriteString(status)
 	buf.WriteString("\r\nConnection: close")
 	header := map[string]string{
 		frame.HeaderOpCode:   frame.OpCodeAck,
 		frame.HeaderFromHost: c.hostname,
 		frame.HeaderFromId:   c.id,
 		frame.HeaderMsgId:    msgID,
-		frame.HeaderQueue:    queue,
-		frame.HeaderLocality: c.locality,
+	}
+	for k, v := range additionalHeader {
+		header[k] = v
 	}
 	for k, v := range header {
 		if v != "" {
@@ -280,7 +353,8 @@ func (c *Connector) ackRequest(msg *nats.Msg, s *sub.Subscription) error {
 	}
 	buf.WriteString("\r\n\r\n")
 
-	err = c.natsConn.Publish(subjectOfResponses(c.plane, fromHost, fromID), buf.Bytes())
+	subject := subjectOfResponses(c.plane, fromHost, fromID)
+	err := c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -290,7 +364,7 @@ func (c *Connector) ackRequest(msg *nats.Msg, s *sub.Subscription) error {
 
 // handleRequest is called when an incoming HTTP request is received.
 // The message is dispatched to the appropriate web handler and the response is serialized and sent back to the response channel of the sender
-func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
+func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription, tb *tokenBucket, cl *concurrencyLimiter) error {
 	ctx := c.lifetimeCtx
 
 	atomic.AddInt32(&c.pendingOps, 1)
@@ -305,6 +379,8 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 	if httpReq.Header.Get("User-Agent") == "Go-http-client/1.1" {
 		httpReq.Header.Del("User-Agent")
 	}
+	// The signature was verified on arrival and must not be passed along
+	httpReq.Header.Del(frame.HeaderSignature)
 
 	// Get the sender hostname and message ID
 	fromHost := frame.Of(httpReq).FromHost()
@@ -327,15 +403,35 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		return errors.Newc(http.StatusRequestTimeout, "timeout")
 	}
 
+	// Decrypt each fragment separately
+	encryptionKeyID, err := c.decryptRequest(httpReq)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// Integrate fragments together
+	fragmentIndex, fragmentMax := frame.Of(httpReq).Fragment()
+	fragmentWindow := frame.Of(httpReq).FragmentWindow()
 	httpReq, err = c.defragRequest(httpReq)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if fragmentIndex > 1 && fragmentMax == 0 && fragmentWindow > 0 {
+		// The fragment of the streamed request was taken in
+		err = c.ackFragment(fromHost, fromId, msgID, fragmentIndex)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	if httpReq == nil {
 		// Not all fragments arrived yet
 		return nil
 	}
+	frame.Of(httpReq).SetFragmentWindow(0)
+	err = c.decompressRequest(httpReq)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	// OpenTelemetry: create a child span
 	spanOptions := []trc.Option{
@@ -350,9 +446,37 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 	ctx, span := c.StartSpan(ctx, fmt.Sprintf(":%s%s", s.Port, s.Path), spanOptions...)
 	defer span.End()
 
+	// sendResponse sets the control headers on the response and sends it back to the caller
+	sendResponse := func(httpResponse *http.Response, opCode string) error {
+		frame.Of(httpResponse).SetMessageID(msgID)
+		frame.Of(httpResponse).SetFromHost(c.hostname)
+		frame.Of(httpResponse).SetFromID(c.id)
+		frame.Of(httpResponse).SetFromVersion(c.version)
+		frame.Of(httpResponse).SetQueue(queue)
+		frame.Of(httpResponse).SetOpCode(opCode)
+		frame.Of(httpResponse).SetLocality(c.locality)
+		frame.Of(httpResponse).SetAcceptCompression(acceptedCompressions)
+		frame.Of(httpResponse).SetAcceptCBOR(true)
+		if encryptionKeyID != "" {
+			err := c.encryptResponse(httpResponse, encryptionKeyID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		var buf bytes.Buffer
+		err := httpResponse.Write(&buf)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		subject := subjectOfResponses(c.plane, fromHost, fromId)
+		err = c.transportConn.Publish(subject, c.signMessage(subject, buf.Bytes()))
+		return errors.Trace(err)
+	}
+
 	// Execute the request
 	handlerStartTime := time.Now()
-	httpRecorder := httpx.NewResponseRecorder()
+	streamer := newResponseStreamer(c.maxFragmentSize, sendResponse)
+	httpRecorder := streamer.ResponseRecorder
 	var handlerErr error
 
 	// Prepare the context
@@ -363,11 +487,29 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		ctx, cancel = context.WithTimeout(ctx, budget-c.networkHop)
 	}
 	httpReq = httpReq.WithContext(ctx)
-
-	// Call the handler
-	handlerErr = errors.CatchPanic(func() error {
-		return s.Handler.(HTTPHandler)(httpRecorder, httpReq)
-	})
+	if stream, ok := httpReq.Body.(*httpx.StreamReader); ok {
+		// End the stream if the handler's context is done before all fragments arrive
+		stopAfter := context.AfterFunc(ctx, func() {
+			stream.CloseWithError(errors.Newc(http.StatusRequestTimeout, "timeout"))
+		})
+		defer stopAfter()
+		defer stream.Close()
+	}
+
+	// Call the handler once the request is admitted
+	var retryAfter time.Duration
+	if s.Encrypted && encryptionKeyID == "" {
+		handlerErr = errors.Newc(http.StatusBadRequest, "encryption required")
+	} else if frame.Of(httpReq).Preflight() {
+		// Only verify that the request would be allowed, without admitting it
+		handlerErr = c.preflightRequest(streamer, httpReq, s)
+	} else {
+		retryAfter, handlerErr = c.admitRequest(ctx, s, tb, cl)
+		if handlerErr == nil {
+			handlerErr = c.callHandler(streamer, httpReq, s)
+			c.releaseRequest(s, cl)
+		}
+	}
 	cancel()
 
 	if handlerErr != nil {
@@ -400,10 +542,27 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
+		if retryAfter > 0 {
+			httpRecorder.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
 		httpRecorder.WriteHeader(statusCode)
 		httpRecorder.Write(body)
 	}
 
+	// Terminate a streamed response, whose status code and headers were already sent
+	statusCode := httpRecorder.StatusCode()
+	contentLength := httpRecorder.ContentLength()
+	var sendErr error
+	if streamer.Streaming() {
+		statusCode = streamer.StatusCode()
+		contentLength = streamer.ContentLength()
+		if handlerErr != nil {
+			sendErr = streamer.Abort(httpRecorder.Result())
+		} else {
+			sendErr = streamer.End()
+		}
+	}
+
 	// Meter
 	_ = c.ObserveMetric(
 		"microbus_response_duration_seconds",
@@ -411,7 +570,7 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		s.Canonical(),
 		s.Port,
 		httpReq.Method,
-		strconv.Itoa(httpRecorder.StatusCode()),
+		strconv.Itoa(statusCode),
 		func() string {
 			if handlerErr != nil {
 				return "ERROR"
@@ -421,11 +580,11 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 	)
 	_ = c.ObserveMetric(
 		"microbus_response_size_bytes",
-		float64(httpRecorder.ContentLength()),
+		float64(contentLength),
 		s.Canonical(),
 		s.Port,
 		httpReq.Method,
-		strconv.Itoa(httpRecorder.StatusCode()),
+		strconv.Itoa(statusCode),
 		func() string {
 			if handlerErr != nil {
 				return "ERROR"
@@ -434,25 +593,26 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		}(),
 	)
 
-	// Set control headers on the response
-	httpResponse := httpRecorder.Result()
-	frame.Of(httpResponse).SetMessageID(msgID)
-	frame.Of(httpResponse).SetFromHost(c.hostname)
-	frame.Of(httpResponse).SetFromID(c.id)
-	frame.Of(httpResponse).SetFromVersion(c.version)
-	frame.Of(httpResponse).SetQueue(queue)
-	frame.Of(httpResponse).SetOpCode(frame.OpCodeResponse)
-	frame.Of(httpResponse).SetLocality(c.locality)
-	if handlerErr != nil {
-		frame.Of(httpResponse).SetOpCode(frame.OpCodeError)
-	}
-
 	// OpenTelemetry: record the status code
 	if handlerErr == nil {
-		span.SetOK(httpResponse.StatusCode)
+		span.SetOK(statusCode)
+	}
+
+	if streamer.Streaming() {
+		return errors.Trace(sendErr)
 	}
 
 	// Send back the response, in fragments if needed
+	opCode := frame.OpCodeResponse
+	if handlerErr != nil {
+		opCode = frame.OpCodeError
+	}
+	// Compress large responses before fragmenting them
+	httpResponse := httpRecorder.Result()
+	err = c.compressResponse(httpReq, httpResponse)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	fragger, err := httpx.NewFragResponse(httpResponse, c.maxFragmentSize)
 	if err != nil {
 		return errors.Trace(err)
@@ -462,12 +622,7 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
-		var buf bytes.Buffer
-		err = fragment.Write(&buf)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		err = c.natsConn.Publish(subjectOfResponses(c.plane, fromHost, fromId), buf.Bytes())
+		err = sendResponse(fragment, opCode)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -475,3 +630,44 @@ func (c *Connector) handleRequest(msg *nats.Msg, s *sub.Subscription) error {
 
 	return nil
 }
+
+// admitRequest admits a request to the subscription if it is within the rate limit and once a slot is available.
+// A request that is over the rate limit is rejected with a 429 error and an indication of when to retry.
+// A request that cannot obtain a slot is rejected with a 503 error.
+// An admitted request must be released when done.
+func (c *Connector) admitRequest(ctx context.Context, s *sub.Subscription, tb *tokenBucket, cl *concurrencyLimiter) (retryAfter time.Duration, err error) {
+	reason := "concurrency"
+	if tb != nil {
+		replicas := 1
+		if tb.policy.Cluster {
+			replicas = c.replicaCount()
+		}
+		var ok bool
+		ok, retryAfter = tb.take(time.Now(), replicas)
+		if !ok {
+			err = errors.Newc(http.StatusTooManyRequests, "rate limit exceeded")
+			reason = "rate"
+		}
+	}
+	if err == nil {
+		err = cl.acquire(ctx)
+	}
+	if err != nil {
+		_ = c.IncrementMetric(
+			"microbus_requests_rejected_total",
+			1,
+			s.Canonical(),
+			s.Port,
+			s.Method,
+			reason,
+		)
+	}
+	c.meterConcurrency(s, cl)
+	return retryAfter, err
+}
+
+// releaseRequest releases the slot obtained by an admitted request.
+func (c *Connector) releaseRequest(s *sub.Subscription, cl *concurrencyLimiter) {
+	cl.release()
+	c.meterConcurrency(s, cl)
+}
@@ -24,14 +24,15 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/frame"
 	"github.com/microbus-io/fabric/pub"
 	"github.com/microbus-io/fabric/rand"
 	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
 	"github.com/microbus-io/testarossa"
-	"github.com/nats-io/nats.go"
 )
 
 func TestConnector_DirectorySubscription(t *testing.T) {
@@ -494,9 +495,11 @@ func TestConnector_SubPendingOps(t *testing.T) {
 
 	<-hold
 	<-end
+	time.Sleep(10 * time.Millisecond) // The op ends shortly after the response is delivered
 	testarossa.Equal(t, int32(1), con.pendingOps)
 	<-hold
 	<-end
+	time.Sleep(10 * time.Millisecond)
 	testarossa.Zero(t, con.pendingOps)
 }
 
@@ -665,7 +668,7 @@ func BenchmarkConnection_AckRequest(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		con.ackRequest(&nats.Msg{
+		con.ackRequest(&transport.Msg{
 			Data: msgData,
 		}, &sub.Subscription{})
 	}
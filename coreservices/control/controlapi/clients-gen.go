@@ -63,6 +63,12 @@ var (
 	URLOfPing = httpx.JoinHostAndPath(Hostname, `:888/ping`)
 	URLOfConfigRefresh = httpx.JoinHostAndPath(Hostname, `:888/config-refresh`)
 	URLOfTrace = httpx.JoinHostAndPath(Hostname, `:888/trace`)
+	URLOfHealth = httpx.JoinHostAndPath(Hostname, `:888/health`)
+	URLOfReady = httpx.JoinHostAndPath(Hostname, `:888/ready`)
+	URLOfLameDuck = httpx.JoinHostAndPath(Hostname, `:888/lame-duck`)
+	URLOfEvict = httpx.JoinHostAndPath(Hostname, `:888/evict`)
+	URLOfInfo = httpx.JoinHostAndPath(Hostname, `:888/info`)
+	URLOfAnnounce = httpx.JoinHostAndPath(Hostname, `:888/announce`)
 )
 
 // Client is an interface to calling the endpoints of the control.core microservice.
@@ -387,3 +393,598 @@ func (_c *Client) Trace(ctx context.Context, id string) (err error) {
 	}
 	return
 }
+
+// HealthIn are the input arguments of Health.
+type HealthIn struct {
+}
+
+// HealthOut are the return values of Health.
+type HealthOut struct {
+	Status string `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// HealthResponse is the response to Health.
+type HealthResponse struct {
+	data HealthOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *HealthResponse) Get() (status string, checks map[string]string, err error) {
+	status = _out.data.Status
+	checks = _out.data.Checks
+	err = _out.err
+	return
+}
+
+/*
+Health runs the health checks of the microservice and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (_c *MulticastClient) Health(ctx context.Context) <-chan *HealthResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/health`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := HealthIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *HealthResponse, cap(_ch))
+	for _i := range _ch {
+		var _r HealthResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Health runs the health checks of the microservice and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (_c *Client) Health(ctx context.Context) (status string, checks map[string]string, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/health`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := HealthIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out HealthOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	status = _out.Status
+	checks = _out.Checks
+	return
+}
+
+// ReadyIn are the input arguments of Ready.
+type ReadyIn struct {
+}
+
+// ReadyOut are the return values of Ready.
+type ReadyOut struct {
+	Status string `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// ReadyResponse is the response to Ready.
+type ReadyResponse struct {
+	data ReadyOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *ReadyResponse) Get() (status string, checks map[string]string, err error) {
+	status = _out.data.Status
+	checks = _out.data.Checks
+	err = _out.err
+	return
+}
+
+/*
+Ready runs the health checks of the microservice, along with the checks that indicate if it is ready
+to take requests, and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (_c *MulticastClient) Ready(ctx context.Context) <-chan *ReadyResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/ready`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := ReadyIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *ReadyResponse, cap(_ch))
+	for _i := range _ch {
+		var _r ReadyResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Ready runs the health checks of the microservice, along with the checks that indicate if it is ready
+to take requests, and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (_c *Client) Ready(ctx context.Context) (status string, checks map[string]string, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/ready`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := ReadyIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out ReadyOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	status = _out.Status
+	checks = _out.Checks
+	return
+}
+
+// LameDuckIn are the input arguments of LameDuck.
+type LameDuckIn struct {
+}
+
+// LameDuckOut are the return values of LameDuck.
+type LameDuckOut struct {
+}
+
+// LameDuckResponse is the response to LameDuck.
+type LameDuckResponse struct {
+	data LameDuckOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *LameDuckResponse) Get() (err error) {
+	err = _out.err
+	return
+}
+
+/*
+LameDuck drains the microservice in preparation for shutdown.
+The microservice stops taking new work and completes its pending operations.
+*/
+func (_c *MulticastClient) LameDuck(ctx context.Context) <-chan *LameDuckResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/lame-duck`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := LameDuckIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *LameDuckResponse, cap(_ch))
+	for _i := range _ch {
+		var _r LameDuckResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+LameDuck drains the microservice in preparation for shutdown.
+The microservice stops taking new work and completes its pending operations.
+*/
+func (_c *Client) LameDuck(ctx context.Context) (err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/lame-duck`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := LameDuckIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out LameDuckOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	return
+}
+
+// EvictIn are the input arguments of Evict.
+type EvictIn struct {
+	ID string `json:"id"`
+	Host string `json:"host"`
+}
+
+// EvictOut are the return values of Evict.
+type EvictOut struct {
+}
+
+// EvictResponse is the response to Evict.
+type EvictResponse struct {
+	data EvictOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *EvictResponse) Get() (err error) {
+	err = _out.err
+	return
+}
+
+/*
+Evict informs the microservice that an instance of another microservice is draining,
+and that requests should no longer be routed to it.
+*/
+func (_c *MulticastClient) Evict(ctx context.Context, id string, host string) <-chan *EvictResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/evict`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`id`: id,
+		`host`: host,
+	})
+	_in := EvictIn{
+		id,
+		host,
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *EvictResponse, cap(_ch))
+	for _i := range _ch {
+		var _r EvictResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Evict informs the microservice that an instance of another microservice is draining,
+and that requests should no longer be routed to it.
+*/
+func (_c *Client) Evict(ctx context.Context, id string, host string) (err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/evict`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`id`: id,
+		`host`: host,
+	})
+	_in := EvictIn{
+		id,
+		host,
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out EvictOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	return
+}
+
+// InfoIn are the input arguments of Info.
+type InfoIn struct {
+}
+
+// InfoOut are the return values of Info.
+type InfoOut struct {
+	Instance *Instance `json:"instance"`
+}
+
+// InfoResponse is the response to Info.
+type InfoResponse struct {
+	data InfoOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *InfoResponse) Get() (instance *Instance, err error) {
+	instance = _out.data.Instance
+	err = _out.err
+	return
+}
+
+/*
+Info returns information about the instance of the microservice, including its subscriptions.
+Multicasting this request to a hostname, or to "all", discovers the live instances of the microservices.
+*/
+func (_c *MulticastClient) Info(ctx context.Context) <-chan *InfoResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/info`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := InfoIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *InfoResponse, cap(_ch))
+	for _i := range _ch {
+		var _r InfoResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Info returns information about the instance of the microservice, including its subscriptions.
+Multicasting this request to a hostname, or to "all", discovers the live instances of the microservices.
+*/
+func (_c *Client) Info(ctx context.Context) (instance *Instance, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/info`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := InfoIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out InfoOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	instance = _out.Instance
+	return
+}
+
+// AnnounceIn are the input arguments of Announce.
+type AnnounceIn struct {
+	Event string `json:"event"`
+	Instance *Instance `json:"instance"`
+}
+
+// AnnounceOut are the return values of Announce.
+type AnnounceOut struct {
+}
+
+// AnnounceResponse is the response to Announce.
+type AnnounceResponse struct {
+	data AnnounceOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *AnnounceResponse) Get() (err error) {
+	err = _out.err
+	return
+}
+
+/*
+Announce informs the microservice that an instance of a microservice has started up or is shutting down,
+as indicated by the event "startup" or "shutdown".
+*/
+func (_c *MulticastClient) Announce(ctx context.Context, event string, instance *Instance) <-chan *AnnounceResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:888/announce`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`event`: event,
+		`instance`: instance,
+	})
+	_in := AnnounceIn{
+		event,
+		instance,
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *AnnounceResponse, cap(_ch))
+	for _i := range _ch {
+		var _r AnnounceResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Announce informs the microservice that an instance of a microservice has started up or is shutting down,
+as indicated by the event "startup" or "shutdown".
+*/
+func (_c *Client) Announce(ctx context.Context, event string, instance *Instance) (err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:888/announce`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`event`: event,
+		`instance`: instance,
+	})
+	_in := AnnounceIn{
+		event,
+		instance,
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out AnnounceOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	return
+}
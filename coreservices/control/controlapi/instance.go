@@ -0,0 +1,47 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlapi
+
+import "time"
+
+// Instance describes a live instance of a microservice.
+type Instance struct {
+	ID            string          `json:"id,omitempty"`
+	Host          string          `json:"host,omitempty"`
+	Version       int             `json:"version,omitempty"`
+	Locality      string          `json:"locality,omitempty"`
+	Deployment    string          `json:"deployment,omitempty"`
+	StartupTime   time.Time       `json:"startupTime,omitempty"`
+	Subscriptions []*Subscription `json:"subscriptions,omitempty"`
+}
+
+// Uptime is the duration of time since the instance started up.
+func (inst *Instance) Uptime() time.Duration {
+	if inst.StartupTime.IsZero() {
+		return 0
+	}
+	return time.Since(inst.StartupTime)
+}
+
+// Subscription describes an endpoint of an instance of a microservice.
+type Subscription struct {
+	Method string `json:"method,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Port   string `json:"port,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Queue  string `json:"queue,omitempty"`
+}
@@ -79,6 +79,12 @@ type ToDo interface {
 	Ping(ctx context.Context) (pong int, err error)
 	ConfigRefresh(ctx context.Context) (err error)
 	Trace(ctx context.Context, id string) (err error)
+	Health(ctx context.Context) (status string, checks map[string]string, err error)
+	Ready(ctx context.Context) (status string, checks map[string]string, err error)
+	LameDuck(ctx context.Context) (err error)
+	Evict(ctx context.Context, id string, host string) (err error)
+	Info(ctx context.Context) (instance *controlapi.Instance, err error)
+	Announce(ctx context.Context, event string, instance *controlapi.Instance) (err error)
 }
 
 // Intermediate extends and customizes the generic base connector.
@@ -109,6 +115,12 @@ The microservice itself does nothing and should not be included in applications.
 	svc.Subscribe(`ANY`, `:888/ping`, svc.doPing, sub.NoQueue())
 	svc.Subscribe(`ANY`, `:888/config-refresh`, svc.doConfigRefresh, sub.NoQueue())
 	svc.Subscribe(`ANY`, `:888/trace`, svc.doTrace, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/health`, svc.doHealth, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/ready`, svc.doReady, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/lame-duck`, svc.doLameDuck, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/evict`, svc.doEvict, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/info`, svc.doInfo, sub.NoQueue())
+	svc.Subscribe(`ANY`, `:888/announce`, svc.doAnnounce, sub.NoQueue())
 
 	// Resources file system
 	svc.SetResFS(resources.FS)
@@ -250,3 +262,223 @@ func (svc *Intermediate) doTrace(w http.ResponseWriter, r *http.Request) error {
 	}
 	return nil
 }
+
+// doHealth handles marshaling for the Health function.
+func (svc *Intermediate) doHealth(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.HealthIn
+	var o controlapi.HealthOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/health`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/health`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Status, o.Checks, err = svc.impl.Health(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doReady handles marshaling for the Ready function.
+func (svc *Intermediate) doReady(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.ReadyIn
+	var o controlapi.ReadyOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/ready`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/ready`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Status, o.Checks, err = svc.impl.Ready(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doLameDuck handles marshaling for the LameDuck function.
+func (svc *Intermediate) doLameDuck(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.LameDuckIn
+	var o controlapi.LameDuckOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/lame-duck`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/lame-duck`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	err = svc.impl.LameDuck(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doEvict handles marshaling for the Evict function.
+func (svc *Intermediate) doEvict(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.EvictIn
+	var o controlapi.EvictOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/evict`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/evict`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	err = svc.impl.Evict(
+		r.Context(),
+		i.ID,
+		i.Host,
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doInfo handles marshaling for the Info function.
+func (svc *Intermediate) doInfo(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.InfoIn
+	var o controlapi.InfoOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/info`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/info`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Instance, err = svc.impl.Info(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doAnnounce handles marshaling for the Announce function.
+func (svc *Intermediate) doAnnounce(w http.ResponseWriter, r *http.Request) error {
+	var i controlapi.AnnounceIn
+	var o controlapi.AnnounceOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:888/announce`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:888/announce`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	err = svc.impl.Announce(
+		r.Context(),
+		i.Event,
+		i.Instance,
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
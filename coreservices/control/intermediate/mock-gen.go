@@ -43,6 +43,12 @@ type Mock struct {
 	mockPing func(ctx context.Context) (pong int, err error)
 	mockConfigRefresh func(ctx context.Context) (err error)
 	mockTrace func(ctx context.Context, id string) (err error)
+	mockHealth func(ctx context.Context) (status string, checks map[string]string, err error)
+	mockReady func(ctx context.Context) (status string, checks map[string]string, err error)
+	mockLameDuck func(ctx context.Context) (err error)
+	mockEvict func(ctx context.Context, id string, host string) (err error)
+	mockInfo func(ctx context.Context) (instance *controlapi.Instance, err error)
+	mockAnnounce func(ctx context.Context, event string, instance *controlapi.Instance) (err error)
 }
 
 // NewMock creates a new mockable version of the microservice.
@@ -109,3 +115,93 @@ func (svc *Mock) Trace(ctx context.Context, id string) (err error) {
 	}
 	return svc.mockTrace(ctx, id)
 }
+
+// MockHealth sets up a mock handler for the Health endpoint.
+func (svc *Mock) MockHealth(handler func(ctx context.Context) (status string, checks map[string]string, err error)) *Mock {
+	svc.mockHealth = handler
+	return svc
+}
+
+// Health runs the mock handler set by MockHealth.
+func (svc *Mock) Health(ctx context.Context) (status string, checks map[string]string, err error) {
+	if svc.mockHealth == nil {
+		err = errors.New("mocked endpoint 'Health' not implemented")
+		return
+	}
+	return svc.mockHealth(ctx)
+}
+
+// MockReady sets up a mock handler for the Ready endpoint.
+func (svc *Mock) MockReady(handler func(ctx context.Context) (status string, checks map[string]string, err error)) *Mock {
+	svc.mockReady = handler
+	return svc
+}
+
+// Ready runs the mock handler set by MockReady.
+func (svc *Mock) Ready(ctx context.Context) (status string, checks map[string]string, err error) {
+	if svc.mockReady == nil {
+		err = errors.New("mocked endpoint 'Ready' not implemented")
+		return
+	}
+	return svc.mockReady(ctx)
+}
+
+// MockLameDuck sets up a mock handler for the LameDuck endpoint.
+func (svc *Mock) MockLameDuck(handler func(ctx context.Context) (err error)) *Mock {
+	svc.mockLameDuck = handler
+	return svc
+}
+
+// LameDuck runs the mock handler set by MockLameDuck.
+func (svc *Mock) LameDuck(ctx context.Context) (err error) {
+	if svc.mockLameDuck == nil {
+		err = errors.New("mocked endpoint 'LameDuck' not implemented")
+		return
+	}
+	return svc.mockLameDuck(ctx)
+}
+
+// MockEvict sets up a mock handler for the Evict endpoint.
+func (svc *Mock) MockEvict(handler func(ctx context.Context, id string, host string) (err error)) *Mock {
+	svc.mockEvict = handler
+	return svc
+}
+
+// Evict runs the mock handler set by MockEvict.
+func (svc *Mock) Evict(ctx context.Context, id string, host string) (err error) {
+	if svc.mockEvict == nil {
+		err = errors.New("mocked endpoint 'Evict' not implemented")
+		return
+	}
+	return svc.mockEvict(ctx, id, host)
+}
+
+// MockInfo sets up a mock handler for the Info endpoint.
+func (svc *Mock) MockInfo(handler func(ctx context.Context) (instance *controlapi.Instance, err error)) *Mock {
+	svc.mockInfo = handler
+	return svc
+}
+
+// Info runs the mock handler set by MockInfo.
+func (svc *Mock) Info(ctx context.Context) (instance *controlapi.Instance, err error) {
+	if svc.mockInfo == nil {
+		err = errors.New("mocked endpoint 'Info' not implemented")
+		return
+	}
+	return svc.mockInfo(ctx)
+}
+
+// MockAnnounce sets up a mock handler for the Announce endpoint.
+func (svc *Mock) MockAnnounce(handler func(ctx context.Context, event string, instance *controlapi.Instance) (err error)) *Mock {
+	svc.mockAnnounce = handler
+	return svc
+}
+
+// Announce runs the mock handler set by MockAnnounce.
+func (svc *Mock) Announce(ctx context.Context, event string, instance *controlapi.Instance) (err error) {
+	if svc.mockAnnounce == nil {
+		err = errors.New("mocked endpoint 'Announce' not implemented")
+		return
+	}
+	return svc.mockAnnounce(ctx, event, instance)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/microbus-io/fabric/errors"
 
+	"github.com/microbus-io/fabric/coreservices/control/controlapi"
 	"github.com/microbus-io/fabric/coreservices/control/intermediate"
 )
 
@@ -70,3 +71,52 @@ Trace forces exporting the indicated tracing span.
 func (svc *Service) Trace(ctx context.Context, id string) (err error) {
 	return nil
 }
+
+/*
+Health runs the health checks of the microservice and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (svc *Service) Health(ctx context.Context) (status string, checks map[string]string, err error) {
+	return "", nil, nil
+}
+
+/*
+Ready runs the health checks of the microservice, along with the checks that indicate if it is ready
+to take requests, and reports their results.
+The status is "ok" if all checks passed, or "fail" otherwise, in which case the status code is 503.
+*/
+func (svc *Service) Ready(ctx context.Context) (status string, checks map[string]string, err error) {
+	return "", nil, nil
+}
+
+/*
+LameDuck drains the microservice in preparation for shutdown.
+The microservice stops taking new work and completes its pending operations.
+*/
+func (svc *Service) LameDuck(ctx context.Context) (err error) {
+	return nil
+}
+
+/*
+Evict informs the microservice that an instance of another microservice is draining,
+and that requests should no longer be routed to it.
+*/
+func (svc *Service) Evict(ctx context.Context, id string, host string) (err error) {
+	return nil
+}
+
+/*
+Info returns information about the instance of the microservice, including its subscriptions.
+Multicasting this request to a hostname, or to "all", discovers the live instances of the microservices.
+*/
+func (svc *Service) Info(ctx context.Context) (instance *controlapi.Instance, err error) {
+	return nil, nil
+}
+
+/*
+Announce informs the microservice that an instance of a microservice has started up or is shutting down,
+as indicated by the event "startup" or "shutdown".
+*/
+func (svc *Service) Announce(ctx context.Context, event string, instance *controlapi.Instance) (err error) {
+	return nil
+}
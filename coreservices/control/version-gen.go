@@ -18,12 +18,12 @@ limitations under the License.
 
 package control
 
-const Version = 174
-const SourceCodeSHA256 = "d9c04c15491c6a644557bfa56894a34aa9e7fb8136550739fc74cdb5d3a069ac"
-const Timestamp = "2024-08-31T21:14:17.583841Z"
+const Version = 182
+const SourceCodeSHA256 = "7176cab74501892919e3793a1fde688e5c467fcf6b6a92d7e03c3728ddaa1f24"
+const Timestamp = "2026-10-17T10:57:23.099441253Z"
 
 /* {
-	"ver": 174,
-	"sha256": "d9c04c15491c6a644557bfa56894a34aa9e7fb8136550739fc74cdb5d3a069ac",
-	"ts": "2024-08-31T21:14:17.583841Z"
+	"ver": 182,
+	"sha256": "7176cab74501892919e3793a1fde688e5c467fcf6b6a92d7e03c3728ddaa1f24",
+	"ts": "2026-10-17T10:57:23.099441253Z"
 } */
@@ -525,3 +525,60 @@ func OnChangedBlockedPaths(t *testing.T, ctx context.Context) *OnChangedBlockedP
 	tc.dur = time.Since(t0)
 	return tc
 }
+
+// OnChangedCanaryWeightsTestCase assists in asserting against the results of executing OnChangedCanaryWeights.
+type OnChangedCanaryWeightsTestCase struct {
+	t *testing.T
+	dur time.Duration
+	err error
+}
+
+// Error asserts an error.
+func (tc *OnChangedCanaryWeightsTestCase) Error(errContains string) *OnChangedCanaryWeightsTestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Contains(tc.t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *OnChangedCanaryWeightsTestCase) ErrorCode(statusCode int) *OnChangedCanaryWeightsTestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Equal(tc.t, statusCode, errors.Convert(tc.err).StatusCode)
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *OnChangedCanaryWeightsTestCase) NoError() *OnChangedCanaryWeightsTestCase {
+	testarossa.NoError(tc.t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *OnChangedCanaryWeightsTestCase) CompletedIn(threshold time.Duration) *OnChangedCanaryWeightsTestCase {
+	testarossa.True(tc.t, tc.dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *OnChangedCanaryWeightsTestCase) Assert(asserter func(t *testing.T, err error)) *OnChangedCanaryWeightsTestCase {
+	asserter(tc.t, tc.err)
+	return tc
+}
+
+// Get returns the result of executing CanaryWeights.
+func (tc *OnChangedCanaryWeightsTestCase) Get() (err error) {
+	return tc.err
+}
+
+// OnChangedCanaryWeights executes the on changed callback and returns a corresponding test case.
+func OnChangedCanaryWeights(t *testing.T, ctx context.Context) *OnChangedCanaryWeightsTestCase {
+	tc := &OnChangedCanaryWeightsTestCase{t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		return Svc.OnChangedCanaryWeights(ctx)
+	})
+	tc.dur = time.Since(t0)
+	return tc
+}
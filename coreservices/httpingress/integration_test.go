@@ -27,11 +27,14 @@ import (
 
 	"github.com/microbus-io/testarossa"
 
+	"github.com/microbus-io/fabric/cfg"
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/coreservices/httpingress/middleware"
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/sub"
 )
 
 // Initialize starts up the testing app.
@@ -493,6 +496,67 @@ func TestHttpingress_InternalHeaders(t *testing.T) {
 	}
 }
 
+func TestHttpingress_Actor(t *testing.T) {
+	// No parallel
+	Svc.SetActorKeys("key1,key2")
+	defer Svc.SetActorKeys("")
+	Svc.SetBearerKeys("bearer1")
+	defer Svc.SetBearerKeys("")
+
+	con := connector.New("actor.ingress")
+	con.Subscribe("GET", "admin", func(w http.ResponseWriter, r *http.Request) error {
+		actor := frame.Of(r).Actor()
+		w.Write([]byte(actor.Subject() + "|" + actor.Issuer()))
+		return nil
+	}, sub.Actor("roles.admin"))
+	con.SetConfig("ActorKeys", "key1,key2")
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Bearer tokens are signed by an external issuer with keys other than the actor keys
+	issuer := connector.New("issuer.actor.ingress")
+	issuer.DefineConfig("ActorKeys", cfg.Secret())
+	issuer.SetConfig("ActorKeys", "bearer1")
+	bearer, err := issuer.SignActor(frame.Actor{"sub": "harry", "iss": "hogwarts", "roles": []string{"admin"}})
+	testarossa.NoError(t, err)
+	internal, err := Svc.SignActor(frame.Actor{"sub": "harry", "iss": "hogwarts", "roles": []string{"admin"}})
+	testarossa.NoError(t, err)
+
+	client := http.Client{Timeout: time.Second * 2}
+	testCases := []struct {
+		authorization string
+		actorHeader   string
+		status        int
+		body          string
+	}{
+		{"", "", http.StatusUnauthorized, ""},
+		{"Bearer " + bearer, "", http.StatusOK, "harry|" + Hostname},
+		{"Bearer " + bearer[:len(bearer)-2] + "xx", "", http.StatusUnauthorized, ""},
+		{"Bearer 123456", "", http.StatusUnauthorized, ""},
+		{"Bearer " + internal, "", http.StatusUnauthorized, ""}, // Actor keys do not validate bearer tokens
+		{"", bearer, http.StatusUnauthorized, ""},               // Internal header is not accepted from the client
+	}
+	for _, tc := range testCases {
+		req, err := http.NewRequest("GET", "http://localhost:4040/actor.ingress/admin", nil)
+		testarossa.NoError(t, err)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		if tc.actorHeader != "" {
+			req.Header.Set(frame.HeaderActor, tc.actorHeader)
+		}
+		res, err := client.Do(req)
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, tc.status, res.StatusCode)
+			if tc.status == http.StatusOK {
+				b, _ := io.ReadAll(res.Body)
+				testarossa.Equal(t, tc.body, string(b))
+			}
+		}
+	}
+}
+
 func TestHttpingress_OnRoute(t *testing.T) {
 	t.Parallel()
 
@@ -596,6 +660,23 @@ func TestHttpingress_DefaultFavIcon(t *testing.T) {
 	}
 }
 
+func TestHttpingress_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	client := http.Client{Timeout: time.Second * 2}
+
+	res, err := client.Get("http://localhost:4040/healthz")
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusOK, res.StatusCode)
+		testarossa.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		body, err := io.ReadAll(res.Body)
+		if testarossa.NoError(t, err) {
+			// The details of the checks are not exposed
+			testarossa.Equal(t, `{"status":"ok"}`, strings.TrimSpace(string(body)))
+		}
+	}
+}
+
 func TestHttpingress_NoCache(t *testing.T) {
 	t.Parallel()
 
@@ -684,3 +765,110 @@ func TestHttpingress_MultiValueHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestHttpingress_StreamedResponse(t *testing.T) {
+	t.Parallel()
+
+	step := make(chan bool)
+	con := connector.New("streamed.response")
+	con.Subscribe("GET", "ok", func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set(frame.HeaderPrefix+"Internal", "Secret")
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("data: Hello\n\n"))
+			w.(http.Flusher).Flush()
+			<-step
+		}
+		return nil
+	})
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Each flush reaches the client before the handler returns
+	client := http.Client{Timeout: time.Second * 2}
+	req, err := http.NewRequest("GET", "http://localhost:4040/streamed.response/ok", nil)
+	testarossa.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := client.Do(req)
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		testarossa.Equal(t, "", res.Header.Get("Content-Length"))
+		testarossa.Equal(t, "", res.Header.Get(frame.HeaderPrefix+"Internal"))
+		testarossa.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+		buf := make([]byte, 64)
+		for i := 0; i < 3; i++ {
+			n, err := res.Body.Read(buf)
+			testarossa.NoError(t, err)
+			testarossa.Equal(t, "data: Hello\n\n", string(buf[:n]))
+			step <- true
+		}
+		b, err := io.ReadAll(res.Body)
+		testarossa.NoError(t, err)
+		testarossa.Zero(t, len(b))
+	}
+}
+
+func TestHttpingress_StreamedRequest(t *testing.T) {
+	t.Parallel()
+
+	con := connector.New("streamed.request")
+	con.Subscribe("POST", "ok", func(w http.ResponseWriter, r *http.Request) error {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, streamed := r.Body.(*httpx.StreamReader)
+		w.Header().Set("Streamed", strconv.FormatBool(streamed))
+		w.Write(b)
+		return nil
+	})
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	client := http.Client{Timeout: time.Second * 4}
+
+	// A large body of unknown length is streamed
+	payload := rand.AlphaNum64(3*1024*1024 + 16)
+	res, err := client.Post("http://localhost:4040/streamed.request/ok", "text/plain", io.MultiReader(strings.NewReader(payload)))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, http.StatusOK, res.StatusCode)
+		testarossa.Equal(t, "true", res.Header.Get("Streamed"))
+		b, err := io.ReadAll(res.Body)
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, payload, string(b))
+		}
+	}
+
+	// A small body of known length is not
+	payload = rand.AlphaNum64(1024)
+	res, err = client.Post("http://localhost:4040/streamed.request/ok", "text/plain", strings.NewReader(payload))
+	if testarossa.NoError(t, err) {
+		testarossa.Equal(t, "false", res.Header.Get("Streamed"))
+		b, err := io.ReadAll(res.Body)
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, payload, string(b))
+		}
+	}
+}
+
+func TestHttpingress_OnChangedCanaryWeights(t *testing.T) {
+	// No parallel
+
+	err := Svc.SetCanaryWeights("canary.weights.ingress:7=95, 8=5\nCanary.Split.Ingress: 0=1,9=1\ninvalid\ninvalid.ingress:7=x")
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, map[int]int{7: 95, 8: 5}, Svc.VersionWeights("canary.weights.ingress"))
+	testarossa.Equal(t, map[int]int{0: 1, 9: 1}, Svc.VersionWeights("canary.split.ingress"))
+	testarossa.Nil(t, Svc.VersionWeights("invalid.ingress"))
+
+	// Splits that are no longer configured are removed
+	err = Svc.SetCanaryWeights("canary.weights.ingress:8=1")
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, map[int]int{8: 1}, Svc.VersionWeights("canary.weights.ingress"))
+	testarossa.Nil(t, Svc.VersionWeights("canary.split.ingress"))
+
+	err = Svc.SetCanaryWeights("")
+	testarossa.NoError(t, err)
+	testarossa.Nil(t, Svc.VersionWeights("canary.weights.ingress"))
+}
@@ -82,6 +82,7 @@ type ToDo interface {
 	OnChangedWriteTimeout(ctx context.Context) (err error)
 	OnChangedReadHeaderTimeout(ctx context.Context) (err error)
 	OnChangedBlockedPaths(ctx context.Context) (err error)
+	OnChangedCanaryWeights(ctx context.Context) (err error)
 }
 
 // Intermediate extends and customizes the generic base connector.
@@ -120,10 +121,18 @@ A value of 0 or less indicates no time budget.`),
 	)
 	svc.DefineConfig(
 		"RequestMemoryLimit",
-		cfg.Description(`RequestMemoryLimit is the memory capacity used to hold pending requests, in megabytes.`),
+		cfg.Description(`RequestMemoryLimit is the memory capacity used to hold pending requests, in megabytes.
+Requests with bodies larger than MaxBufferedBodySize or of unknown length are streamed and do not count against this limit.`),
 		cfg.Validation(`int [1,]`),
 		cfg.DefaultValue(`4096`),
 	)
+	svc.DefineConfig(
+		"MaxBufferedBodySize",
+		cfg.Description(`MaxBufferedBodySize is the size of the body of a request, in kilobytes, above which it is streamed
+to the microservice rather than read into memory.`),
+		cfg.Validation(`int [0,]`),
+		cfg.DefaultValue(`1024`),
+	)
 	svc.DefineConfig(
 		"AllowedOrigins",
 		cfg.Description(`AllowedOrigins is a comma-separated list of CORS origins to allow requests from.
@@ -225,6 +234,36 @@ Extensions are specified with "*.ext" and are matched against the extension of t
 *.esp
 *.exe`),
 	)
+	svc.DefineConfig(
+		"HealthzPath",
+		cfg.Description(`HealthzPath is the path on which the ingress proxy reports its own health to external probes,
+such as those of Kubernetes. Only the overall status is reported, with a 503 status code if any of the health checks failed.
+An empty value disables the endpoint.`),
+		cfg.DefaultValue(`/healthz`),
+	)
+	svc.DefineConfig(
+		"CanaryWeights",
+		cfg.Description(`CanaryWeights is a newline-separated list of canary splits in the form host:version=weight,version=weight.
+Requests to the host are split between versions of the microservice in proportion to their weights.
+Version 0 stands for any version.
+For example, calculator.example:7=95,8=5 sends 5% of the requests to version 8 and the rest to version 7.
+The splits apply only to requests that enter through the ingress proxy.`),
+	)
+	svc.DefineConfig(
+		"ActorKeys",
+		cfg.Description(`ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The ingress proxy signs the claims of a valid bearer token with these keys as the actor of the request.
+An empty value disables actor tokens.`),
+		cfg.Secret(),
+	)
+	svc.DefineConfig(
+		"BearerKeys",
+		cfg.Description(`BearerKeys are the secret keys of the external issuer with which bearer tokens in the Authorization header
+of incoming requests are validated, separated by commas or spaces.
+They must differ from the ActorKeys so that internal actor tokens are not accepted from outside the system.
+An empty value disables bearer tokens.`),
+		cfg.Secret(),
+	)
 
 	// OpenAPI
 	svc.Subscribe("GET", `:0/openapi.json`, svc.doOpenAPI)
@@ -300,6 +339,12 @@ func (svc *Intermediate) doOnConfigChanged(ctx context.Context, changed func(str
 			return err // No trace
 		}
 	}
+	if changed("CanaryWeights") {
+		err := svc.impl.OnChangedCanaryWeights(ctx)
+		if err != nil {
+			return err // No trace
+		}
+	}
 	return nil
 }
 
@@ -342,6 +387,7 @@ func (svc *Intermediate) SetPorts(port string) error {
 
 /*
 RequestMemoryLimit is the memory capacity used to hold pending requests, in megabytes.
+Requests with bodies larger than MaxBufferedBodySize or of unknown length are streamed and do not count against this limit.
 */
 func (svc *Intermediate) RequestMemoryLimit() (megaBytes int) {
 	_val := svc.Config("RequestMemoryLimit")
@@ -353,11 +399,32 @@ func (svc *Intermediate) RequestMemoryLimit() (megaBytes int) {
 SetRequestMemoryLimit sets the value of the configuration property.
 
 RequestMemoryLimit is the memory capacity used to hold pending requests, in megabytes.
+Requests with bodies larger than MaxBufferedBodySize or of unknown length are streamed and do not count against this limit.
 */
 func (svc *Intermediate) SetRequestMemoryLimit(megaBytes int) error {
 	return svc.SetConfig("RequestMemoryLimit", fmt.Sprintf("%v", megaBytes))
 }
 
+/*
+MaxBufferedBodySize is the size of the body of a request, in kilobytes, above which it is streamed
+to the microservice rather than read into memory.
+*/
+func (svc *Intermediate) MaxBufferedBodySize() (kiloBytes int) {
+	_val := svc.Config("MaxBufferedBodySize")
+	_i, _ := strconv.ParseInt(_val, 10, 64)
+	return int(_i)
+}
+
+/*
+SetMaxBufferedBodySize sets the value of the configuration property.
+
+MaxBufferedBodySize is the size of the body of a request, in kilobytes, above which it is streamed
+to the microservice rather than read into memory.
+*/
+func (svc *Intermediate) SetMaxBufferedBodySize(kiloBytes int) error {
+	return svc.SetConfig("MaxBufferedBodySize", fmt.Sprintf("%v", kiloBytes))
+}
+
 /*
 AllowedOrigins is a comma-separated list of CORS origins to allow requests from.
 The * origin can be used to allow CORS request from all origins.
@@ -482,3 +549,93 @@ Extensions are specified with "*.ext" and are matched against the extension of t
 func (svc *Intermediate) SetBlockedPaths(blockedPaths string) error {
 	return svc.SetConfig("BlockedPaths", fmt.Sprintf("%v", blockedPaths))
 }
+
+/*
+HealthzPath is the path on which the ingress proxy reports its own health to external probes,
+such as those of Kubernetes. Only the overall status is reported, with a 503 status code if any of the health checks failed.
+An empty value disables the endpoint.
+*/
+func (svc *Intermediate) HealthzPath() (path string) {
+	_val := svc.Config("HealthzPath")
+	return _val
+}
+
+/*
+SetHealthzPath sets the value of the configuration property.
+
+HealthzPath is the path on which the ingress proxy reports its own health to external probes,
+such as those of Kubernetes. Only the overall status is reported, with a 503 status code if any of the health checks failed.
+An empty value disables the endpoint.
+*/
+func (svc *Intermediate) SetHealthzPath(path string) error {
+	return svc.SetConfig("HealthzPath", fmt.Sprintf("%v", path))
+}
+
+/*
+CanaryWeights is a newline-separated list of canary splits in the form host:version=weight,version=weight.
+Requests to the host are split between versions of the microservice in proportion to their weights.
+Version 0 stands for any version.
+For example, calculator.example:7=95,8=5 sends 5% of the requests to version 8 and the rest to version 7.
+The splits apply only to requests that enter through the ingress proxy.
+*/
+func (svc *Intermediate) CanaryWeights() (weights string) {
+	_val := svc.Config("CanaryWeights")
+	return _val
+}
+
+/*
+SetCanaryWeights sets the value of the configuration property.
+
+CanaryWeights is a newline-separated list of canary splits in the form host:version=weight,version=weight.
+Requests to the host are split between versions of the microservice in proportion to their weights.
+Version 0 stands for any version.
+For example, calculator.example:7=95,8=5 sends 5% of the requests to version 8 and the rest to version 7.
+The splits apply only to requests that enter through the ingress proxy.
+*/
+func (svc *Intermediate) SetCanaryWeights(weights string) error {
+	return svc.SetConfig("CanaryWeights", fmt.Sprintf("%v", weights))
+}
+
+/*
+ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The ingress proxy signs the claims of a valid bearer token with these keys as the actor of the request.
+An empty value disables actor tokens.
+*/
+func (svc *Intermediate) ActorKeys() (keys string) {
+	_val := svc.Config("ActorKeys")
+	return _val
+}
+
+/*
+SetActorKeys sets the value of the configuration property.
+
+ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The ingress proxy signs the claims of a valid bearer token with these keys as the actor of the request.
+An empty value disables actor tokens.
+*/
+func (svc *Intermediate) SetActorKeys(keys string) error {
+	return svc.SetConfig("ActorKeys", fmt.Sprintf("%v", keys))
+}
+
+/*
+BearerKeys are the secret keys of the external issuer with which bearer tokens in the Authorization header
+of incoming requests are validated, separated by commas or spaces.
+They must differ from the ActorKeys so that internal actor tokens are not accepted from outside the system.
+An empty value disables bearer tokens.
+*/
+func (svc *Intermediate) BearerKeys() (keys string) {
+	_val := svc.Config("BearerKeys")
+	return _val
+}
+
+/*
+SetBearerKeys sets the value of the configuration property.
+
+BearerKeys are the secret keys of the external issuer with which bearer tokens in the Authorization header
+of incoming requests are validated, separated by commas or spaces.
+They must differ from the ActorKeys so that internal actor tokens are not accepted from outside the system.
+An empty value disables bearer tokens.
+*/
+func (svc *Intermediate) SetBearerKeys(keys string) error {
+	return svc.SetConfig("BearerKeys", fmt.Sprintf("%v", keys))
+}
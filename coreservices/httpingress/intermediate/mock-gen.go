@@ -96,3 +96,8 @@ func (svc *Mock) OnChangedReadHeaderTimeout(ctx context.Context) (err error) {
 func (svc *Mock) OnChangedBlockedPaths(ctx context.Context) (err error) {
 	return nil
 }
+
+// OnChangedCanaryWeights is a no op.
+func (svc *Mock) OnChangedCanaryWeights(ctx context.Context) (err error) {
+	return nil
+}
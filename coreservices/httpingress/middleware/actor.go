@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/frame"
+)
+
+// Actor returns a middleware that attaches to the request the signed token of the actor on behalf of which it is made.
+// The token is carried by the frame on every downstream hop. An empty token attaches no actor.
+// An error returned by the issuer, typically with a 401 status code, stops the request.
+func Actor(issuer func(r *http.Request) (token string, err error)) Middleware {
+	return func(next connector.HTTPHandler) connector.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			token, err := issuer(r)
+			if err != nil {
+				return err // No trace
+			}
+			frame.Of(r).SetActorToken(token)
+			return next(w, r) // No trace
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/microbus-io/fabric/connector"
+)
+
+// HealthCheck returns a middleware that responds to requests to the health check path with the outcome
+// of the health checks, rather than passing them along. The status code is 503 if any of the checks failed.
+// Only the overall status is reported so as to not expose the details of the checks to external callers.
+// An empty path disables the health check endpoint.
+func HealthCheck(path func() string, check func(ctx context.Context) map[string]error) Middleware {
+	return func(next connector.HTTPHandler) connector.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			p := path()
+			if p == "" || r.URL.Path != p {
+				return next(w, r) // No trace
+			}
+			report := connector.NewHealthReport(check(r.Context()))
+			report.Checks = nil
+			return report.Write(w) // No trace
+		}
+	}
+}
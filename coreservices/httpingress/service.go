@@ -57,6 +57,7 @@ type Service struct {
 	reqMemoryUsed  int64
 	secure443      bool
 	blockedPaths   map[string]bool
+	canaryHosts    map[string]bool
 	middleware     *middleware.Chain
 	handler        connector.HTTPHandler
 }
@@ -66,6 +67,18 @@ func (svc *Service) OnStartup(ctx context.Context) (err error) {
 	svc.OnChangedAllowedOrigins(ctx)
 	svc.OnChangedPortMappings(ctx)
 	svc.OnChangedBlockedPaths(ctx)
+	svc.OnChangedCanaryWeights(ctx)
+
+	// Report the status of the HTTP listeners in the health checks
+	svc.SetHealthCheck("http", func(ctx context.Context) error {
+		svc.mux.Lock()
+		n := len(svc.httpServers)
+		svc.mux.Unlock()
+		if n == 0 {
+			return errors.New("no HTTP listeners")
+		}
+		return nil
+	})
 
 	// Setup the middleware chain
 	svc.handler = svc.serveHTTP
@@ -115,6 +128,7 @@ func (svc *Service) Middleware() *middleware.Chain {
 
 		// Warning: renaming or removing middleware is a breaking change because the names are used as location markers
 		m.Append("ErrorPrinter", middleware.ErrorPrinter())
+		m.Append("HealthCheck", middleware.HealthCheck(svc.HealthzPath, svc.CheckHealth))
 		m.Append("BlockedPaths", middleware.BlockedPaths(func(path string) bool {
 			if svc.blockedPaths[path] {
 				return true
@@ -135,6 +149,7 @@ func (svc *Service) Middleware() *middleware.Chain {
 		}))
 		m.Append("XForward", middleware.XForwarded())
 		m.Append("InternalHeaders", middleware.InternalHeaders())
+		m.Append("Actor", middleware.Actor(svc.issueActorToken))
 		m.Append("RootPath", middleware.RewriteRootPath("/root"))
 		m.Append("Timeout", middleware.RequestTimeout(func() time.Duration {
 			return svc.TimeBudget()
@@ -311,6 +326,7 @@ func (svc *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var span trc.Span
 	ctx, span = svc.StartSpan(ctx, ":"+port+r.URL.Path, spanOptions...)
 	defer span.End()
+	ctx, relay := withStreamRelay(ctx)
 	r = r.WithContext(ctx)
 
 	ww := httpx.NewResponseRecorder() // This recorder allows modifying the response after it was written
@@ -327,7 +343,33 @@ func (svc *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// OpenTelemetry: record the status code
 		span.SetOK(ww.StatusCode())
 	}
-	_ = httpx.Copy(w, ww.Result())
+	contentLength := int64(ww.ContentLength())
+	if relay.body != nil && err != nil {
+		relay.body.Close()
+		relay.cancel()
+		relay.body = nil
+	}
+	if relay.body != nil {
+		// Relay the streamed response to the client as it arrives
+		res := ww.Result()
+		res.Header.Del("Content-Length")
+		for k, vv := range res.Header {
+			for _, v := range vv {
+				w.Header().Add(k, v)
+			}
+		}
+		n, relayErr := relay.Relay(w, res.StatusCode)
+		contentLength += n
+		if relayErr != nil {
+			svc.LogWarn(ctx, "Relaying stream",
+				"error", relayErr,
+				"path", r.URL.Path,
+			)
+			span.SetError(relayErr)
+		}
+	} else {
+		_ = httpx.Copy(w, ww.Result())
+	}
 
 	// Meter
 	_ = svc.ObserveMetric(
@@ -346,7 +388,7 @@ func (svc *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 	_ = svc.ObserveMetric(
 		"microbus_response_size_bytes",
-		float64(ww.ContentLength()),
+		float64(contentLength),
 		r.Host+"/",
 		port,
 		r.Method,
@@ -381,21 +423,27 @@ func (svc *Service) serveHTTP(w http.ResponseWriter, r *http.Request) error {
 	}
 	internalURL := u.String()
 
-	// Read the body fully
-	body, err := svc.readRequestBody(r)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	defer svc.releaseRequestBody(body)
-
 	// Prepare the internal request options
 	options := []pub.Option{
 		pub.Method(r.Method),
 		pub.URL(internalURL),
-		pub.Body(body),
 		pub.Unicast(),
-		pub.CopyHeaders(r.Header),    // Copy all headers
-		pub.ContentLength(len(body)), // Overwrite the Content-Length header
+		pub.CopyHeaders(r.Header), // Copy all headers
+	}
+	if r.Body != nil && (r.ContentLength < 0 || r.ContentLength > int64(svc.MaxBufferedBodySize())*1024) {
+		// Stream large bodies, or bodies of unknown length, without holding them in memory
+		options = append(options, pub.Body(r.Body))
+	} else {
+		// Read the body fully
+		body, err := svc.readRequestBody(r)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer svc.releaseRequestBody(body)
+		options = append(options,
+			pub.Body(body),
+			pub.ContentLength(len(body)), // Overwrite the Content-Length header
+		)
 	}
 
 	// OpenTelemetry: pass the span in the headers
@@ -405,17 +453,41 @@ func (svc *Service) serveHTTP(w http.ResponseWriter, r *http.Request) error {
 		options = append(options, pub.Header(k, v[0]))
 	}
 
+	// Streamed responses are relayed by ServeHTTP after the middleware returns
+	// so the request must outlive the cancellation of the middleware's context
+	reqCtx := ctx
+	relay := streamRelayOf(ctx)
+	if relay != nil {
+		var cancel context.CancelFunc
+		if deadline, ok := ctx.Deadline(); ok {
+			reqCtx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		} else {
+			reqCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+		}
+		defer func() {
+			if relay.body == nil {
+				cancel()
+			}
+		}()
+		relay.cancel = cancel
+	}
+
 	// Delegate the request over NATS
-	internalRes, err := svc.Request(ctx, options...)
+	internalRes, err := svc.Request(reqCtx, options...)
 	if err != nil {
 		return err // No trace
 	}
+	if stream, ok := internalRes.Body.(*httpx.StreamReader); ok && relay != nil {
+		// Copy only the status code and headers, leaving the body to ServeHTTP
+		relay.body = stream
+		internalRes.Body = nil
+	}
 	err = httpx.Copy(w, internalRes)
 	return errors.Trace(err)
 }
 
 // readRequestBody reads the body of the request into memory, within the memory limit
-// set for the proxy.
+// set for the proxy. Only bodies of known length up to MaxBufferedBodySize are read into memory.
 func (svc *Service) readRequestBody(r *http.Request) (body []byte, err error) {
 	if r.Body == nil || r.ContentLength == 0 {
 		return []byte{}, nil
@@ -566,3 +638,51 @@ func (svc *Service) OnChangedBlockedPaths(ctx context.Context) (err error) {
 	svc.blockedPaths = newPaths
 	return nil
 }
+
+// OnChangedCanaryWeights is triggered when the value of the CanaryWeights config property changes.
+func (svc *Service) OnChangedCanaryWeights(ctx context.Context) (err error) {
+	value := svc.CanaryWeights() // e.g. "calculator.example:7=95,8=5"
+	newHosts := map[string]bool{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		host, weights, err := connector.ParseVersionWeights(line)
+		if err == nil {
+			err = svc.SetVersionWeights(host, weights)
+		}
+		if err != nil {
+			svc.LogWarn(ctx, "Invalid canary weights",
+				"weights", line,
+				"error", err,
+			)
+			continue
+		}
+		newHosts[host] = true
+	}
+	// Remove the splits that are no longer configured
+	for host := range svc.canaryHosts {
+		if !newHosts[host] {
+			svc.SetVersionWeights(host, nil)
+		}
+	}
+	svc.canaryHosts = newHosts
+	return nil
+}
+
+// issueActorToken validates the bearer token in the Authorization header of the request against the bearer keys
+// and signs its claims with the actor keys as the actor of the request, with the ingress proxy as the issuer.
+func (svc *Service) issueActorToken(r *http.Request) (token string, err error) {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || svc.ActorKeys() == "" || svc.BearerKeys() == "" {
+		return "", nil
+	}
+	actor, err := connector.VerifyActorToken(strings.TrimSpace(bearer), svc.BearerKeys())
+	if err != nil {
+		return "", err // No trace
+	}
+	actor["iss"] = svc.Hostname()
+	token, err = svc.SignActor(actor)
+	return token, errors.Trace(err)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpingress
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/microbus-io/fabric/errors"
+)
+
+type streamRelayKey struct{}
+
+// streamRelay hands over the body of a streamed response from serveHTTP to ServeHTTP,
+// which relays it to the client after the middleware had a chance to process the status code and headers.
+type streamRelay struct {
+	body   io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// withStreamRelay returns a child context that carries a stream relay.
+func withStreamRelay(ctx context.Context) (context.Context, *streamRelay) {
+	relay := &streamRelay{}
+	return context.WithValue(ctx, streamRelayKey{}, relay), relay
+}
+
+// streamRelayOf returns the stream relay carried by the context, or nil if there is none.
+func streamRelayOf(ctx context.Context) *streamRelay {
+	relay, _ := ctx.Value(streamRelayKey{}).(*streamRelay)
+	return relay
+}
+
+// Relay writes the body of the streamed response to the writer, flushing each chunk as soon as it arrives.
+// The status code and headers are expected to already be set on the writer.
+func (relay *streamRelay) Relay(w http.ResponseWriter, statusCode int) (n int64, err error) {
+	defer relay.cancel()
+	defer relay.body.Close()
+	flusher, _ := w.(http.Flusher)
+	w.WriteHeader(statusCode)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		m, readErr := relay.body.Read(buf)
+		if m > 0 {
+			_, err = w.Write(buf[:m])
+			if err != nil {
+				return n, errors.Trace(err)
+			}
+			n += int64(m)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr == io.EOF {
+			return n, nil
+		}
+		if readErr != nil {
+			return n, errors.Trace(readErr)
+		}
+	}
+}
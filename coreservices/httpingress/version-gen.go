@@ -18,12 +18,12 @@ limitations under the License.
 
 package httpingress
 
-const Version = 266
-const SourceCodeSHA256 = "9d3acb60de26eb82be47063fbfe71e4568f0e2fe597136a832f6487bfa42161a"
-const Timestamp = "2024-09-15T02:36:58.195283Z"
+const Version = 280
+const SourceCodeSHA256 = "c261bbeb7e20eceb3f49fd576a15450719caf97024b0f41d13a69637292cb82f"
+const Timestamp = "2026-10-17T10:57:22.733584868Z"
 
 /* {
-	"ver": 266,
-	"sha256": "9d3acb60de26eb82be47063fbfe71e4568f0e2fe597136a832f6487bfa42161a",
-	"ts": "2024-09-15T02:36:58.195283Z"
+	"ver": 280,
+	"sha256": "c261bbeb7e20eceb3f49fd576a15450719caf97024b0f41d13a69637292cb82f",
+	"ts": "2026-10-17T10:57:22.733584868Z"
 } */
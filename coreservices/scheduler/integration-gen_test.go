@@ -0,0 +1,386 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by Microbus. DO NOT EDIT.
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/microbus-io/fabric/application"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/fabric/utils"
+	"github.com/microbus-io/testarossa"
+	"golang.org/x/net/html"
+
+	"github.com/microbus-io/fabric/coreservices/scheduler/schedulerapi"
+)
+
+var (
+	_ bytes.Buffer
+	_ context.Context
+	_ fmt.Stringer
+	_ io.Reader
+	_ *http.Request
+	_ os.File
+	_ time.Time
+	_ strings.Builder
+	_ cascadia.Sel
+	_ *connector.Connector
+	_ *errors.TracedError
+	_ frame.Frame
+	_ *httpx.BodyReader
+	_ pub.Option
+	_ rand.Void
+	_ utils.SyncMap[string, string]
+	_ testarossa.TestingT
+	_ *html.Node
+	_ *schedulerapi.Client
+)
+
+var (
+	// App manages the lifecycle of the microservices used in the test
+	App *application.Application
+	// Svc is the scheduler.core microservice being tested
+	Svc *Service
+)
+
+func TestMain(m *testing.M) {
+	var code int
+
+	// Initialize the application
+	err := func() error {
+		var err error
+		App = application.NewTesting()
+		Svc = NewService()
+		err = Initialize()
+		if err != nil {
+			return err
+		}
+		err = App.Startup()
+		if err != nil {
+			return err
+		}
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "--- FAIL: %+v\n", err)
+		code = 19
+	}
+
+	// Run the tests
+	if err == nil {
+		code = m.Run()
+	}
+
+	// Terminate the app
+	err = func() error {
+		var err error
+		var lastErr error
+		err = App.Shutdown()
+		if err != nil {
+			lastErr = err
+		}
+		err = Terminate()
+		if err != nil {
+			lastErr = err
+		}
+		return lastErr
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "--- FAIL: %+v\n", err)
+	}
+
+	os.Exit(code)
+}
+
+// Context creates a new context for a test.
+func Context() context.Context {
+	return frame.ContextWithFrame(context.Background())
+}
+
+// ScheduleTestCase assists in asserting against the results of executing Schedule.
+type ScheduleTestCase struct {
+	_t *testing.T
+	_dur time.Duration
+	id string
+	err error
+}
+
+// Expect asserts no error and exact return values.
+func (_tc *ScheduleTestCase) Expect(id string) *ScheduleTestCase {
+	if testarossa.NoError(_tc._t, _tc.err) {
+		testarossa.Equal(_tc._t, id, _tc.id)
+	}
+	return _tc
+}
+
+// Error asserts an error.
+func (tc *ScheduleTestCase) Error(errContains string) *ScheduleTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Contains(tc._t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *ScheduleTestCase) ErrorCode(statusCode int) *ScheduleTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Equal(tc._t, statusCode, errors.StatusCode(tc.err))
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *ScheduleTestCase) NoError() *ScheduleTestCase {
+	testarossa.NoError(tc._t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *ScheduleTestCase) CompletedIn(threshold time.Duration) *ScheduleTestCase {
+	testarossa.True(tc._t, tc._dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *ScheduleTestCase) Assert(asserter func(t *testing.T, id string, err error)) *ScheduleTestCase {
+	asserter(tc._t, tc.id, tc.err)
+	return tc
+}
+
+// Get returns the result of executing Schedule.
+func (tc *ScheduleTestCase) Get() (id string, err error) {
+	return tc.id, tc.err
+}
+
+// Schedule executes the function and returns a corresponding test case.
+func Schedule(t *testing.T, ctx context.Context, deliverAt time.Time, request []byte, multicast bool) *ScheduleTestCase {
+	tc := &ScheduleTestCase{_t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		tc.id, tc.err = Svc.Schedule(ctx, deliverAt, request, multicast)
+		return tc.err
+	})
+	tc._dur = time.Since(t0)
+	return tc
+}
+
+// CancelTestCase assists in asserting against the results of executing Cancel.
+type CancelTestCase struct {
+	_t *testing.T
+	_dur time.Duration
+	cancelled bool
+	err error
+}
+
+// Expect asserts no error and exact return values.
+func (_tc *CancelTestCase) Expect(cancelled bool) *CancelTestCase {
+	if testarossa.NoError(_tc._t, _tc.err) {
+		testarossa.Equal(_tc._t, cancelled, _tc.cancelled)
+	}
+	return _tc
+}
+
+// Error asserts an error.
+func (tc *CancelTestCase) Error(errContains string) *CancelTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Contains(tc._t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *CancelTestCase) ErrorCode(statusCode int) *CancelTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Equal(tc._t, statusCode, errors.StatusCode(tc.err))
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *CancelTestCase) NoError() *CancelTestCase {
+	testarossa.NoError(tc._t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *CancelTestCase) CompletedIn(threshold time.Duration) *CancelTestCase {
+	testarossa.True(tc._t, tc._dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *CancelTestCase) Assert(asserter func(t *testing.T, cancelled bool, err error)) *CancelTestCase {
+	asserter(tc._t, tc.cancelled, tc.err)
+	return tc
+}
+
+// Get returns the result of executing Cancel.
+func (tc *CancelTestCase) Get() (cancelled bool, err error) {
+	return tc.cancelled, tc.err
+}
+
+// Cancel executes the function and returns a corresponding test case.
+func Cancel(t *testing.T, ctx context.Context, id string) *CancelTestCase {
+	tc := &CancelTestCase{_t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		tc.cancelled, tc.err = Svc.Cancel(ctx, id)
+		return tc.err
+	})
+	tc._dur = time.Since(t0)
+	return tc
+}
+
+// DeliverDueTestCase assists in asserting against the results of executing DeliverDue.
+type DeliverDueTestCase struct {
+	_t *testing.T
+	_dur time.Duration
+	delivered int
+	err error
+}
+
+// Expect asserts no error and exact return values.
+func (_tc *DeliverDueTestCase) Expect(delivered int) *DeliverDueTestCase {
+	if testarossa.NoError(_tc._t, _tc.err) {
+		testarossa.Equal(_tc._t, delivered, _tc.delivered)
+	}
+	return _tc
+}
+
+// Error asserts an error.
+func (tc *DeliverDueTestCase) Error(errContains string) *DeliverDueTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Contains(tc._t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *DeliverDueTestCase) ErrorCode(statusCode int) *DeliverDueTestCase {
+	if testarossa.Error(tc._t, tc.err) {
+		testarossa.Equal(tc._t, statusCode, errors.StatusCode(tc.err))
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *DeliverDueTestCase) NoError() *DeliverDueTestCase {
+	testarossa.NoError(tc._t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *DeliverDueTestCase) CompletedIn(threshold time.Duration) *DeliverDueTestCase {
+	testarossa.True(tc._t, tc._dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *DeliverDueTestCase) Assert(asserter func(t *testing.T, delivered int, err error)) *DeliverDueTestCase {
+	asserter(tc._t, tc.delivered, tc.err)
+	return tc
+}
+
+// Get returns the result of executing DeliverDue.
+func (tc *DeliverDueTestCase) Get() (delivered int, err error) {
+	return tc.delivered, tc.err
+}
+
+// DeliverDue executes the function and returns a corresponding test case.
+func DeliverDue(t *testing.T, ctx context.Context) *DeliverDueTestCase {
+	tc := &DeliverDueTestCase{_t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		tc.delivered, tc.err = Svc.DeliverDue(ctx)
+		return tc.err
+	})
+	tc._dur = time.Since(t0)
+	return tc
+}
+
+// PeriodicDeliveryTestCase assists in asserting against the results of executing PeriodicDelivery.
+type PeriodicDeliveryTestCase struct {
+	t *testing.T
+	dur time.Duration
+	err error
+}
+
+// Error asserts an error.
+func (tc *PeriodicDeliveryTestCase) Error(errContains string) *PeriodicDeliveryTestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Contains(tc.t, tc.err.Error(), errContains)
+	}
+	return tc
+}
+
+// ErrorCode asserts an error by its status code.
+func (tc *PeriodicDeliveryTestCase) ErrorCode(statusCode int) *PeriodicDeliveryTestCase {
+	if testarossa.Error(tc.t, tc.err) {
+		testarossa.Equal(tc.t, statusCode, errors.Convert(tc.err).StatusCode)
+	}
+	return tc
+}
+
+// NoError asserts no error.
+func (tc *PeriodicDeliveryTestCase) NoError() *PeriodicDeliveryTestCase {
+	testarossa.NoError(tc.t, tc.err)
+	return tc
+}
+
+// CompletedIn checks that the duration of the operation is less than or equal the threshold.
+func (tc *PeriodicDeliveryTestCase) CompletedIn(threshold time.Duration) *PeriodicDeliveryTestCase {
+	testarossa.True(tc.t, tc.dur <= threshold)
+	return tc
+}
+
+// Assert asserts using a provided function.
+func (tc *PeriodicDeliveryTestCase) Assert(asserter func(t *testing.T, err error)) *PeriodicDeliveryTestCase {
+	asserter(tc.t, tc.err)
+	return tc
+}
+
+// Get returns the result of executing PeriodicDelivery.
+func (tc *PeriodicDeliveryTestCase) Get() (err error) {
+	return tc.err
+}
+
+// PeriodicDelivery executes the ticker and returns a corresponding test case.
+func PeriodicDelivery(t *testing.T, ctx context.Context) *PeriodicDeliveryTestCase {
+	tc := &PeriodicDeliveryTestCase{t: t}
+	t0 := time.Now()
+	tc.err = errors.CatchPanic(func() error {
+		return Svc.PeriodicDelivery(ctx)
+	})
+	tc.dur = time.Since(t0)
+	return tc
+}
@@ -0,0 +1,324 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/service"
+	"github.com/microbus-io/fabric/sub"
+
+	"github.com/microbus-io/fabric/coreservices/scheduler/schedulerapi"
+)
+
+var (
+	_ *testing.T
+	_ testarossa.TestingT
+	_ service.Service
+	_ *schedulerapi.Client
+)
+
+// Initialize starts up the testing app.
+func Initialize() (err error) {
+	App.Init(func(svc service.Service) {
+		// Initialize all microservices
+	})
+
+	// Add microservices to the testing app
+	err = App.AddAndStartup(
+		Svc.Init(func(svc *Service) {
+			// Initialize the microservice under test
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Terminate gets called after the testing app shut down.
+func Terminate() (err error) {
+	return nil
+}
+
+// serialize serializes a request to be scheduled.
+func serialize(t *testing.T, method string, url string, body []byte) []byte {
+	r, err := http.NewRequest(method, url, bytes.NewReader(body))
+	testarossa.NoError(t, err)
+	var buf bytes.Buffer
+	err = r.WriteProxy(&buf)
+	testarossa.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context()
+	deliverAt := time.Now().Add(1000 * time.Hour)
+	request := serialize(t, "POST", "https://schedule.target/ok", []byte("Hello"))
+
+	id, err := Schedule(t, ctx, deliverAt, request, false).NoError().Get()
+	if testarossa.NoError(t, err) {
+		testarossa.NotEqual(t, "", id)
+	}
+	id2, _ := Schedule(t, ctx, deliverAt, request, false).NoError().Get()
+	testarossa.NotEqual(t, id, id2)
+
+	// Bad input
+	Schedule(t, ctx, time.Time{}, request, false).ErrorCode(http.StatusBadRequest)
+	Schedule(t, ctx, deliverAt, nil, false).ErrorCode(http.StatusBadRequest)
+	Schedule(t, ctx, deliverAt, []byte("Not a request"), false).ErrorCode(http.StatusBadRequest)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := Context()
+	deliverAt := time.Now().Add(1000 * time.Hour)
+	request := serialize(t, "GET", "https://cancel.target/ok", nil)
+
+	id, _ := Schedule(t, ctx, deliverAt, request, false).NoError().Get()
+	Cancel(t, ctx, id).Expect(true)
+	Cancel(t, ctx, id).Expect(false)
+	Cancel(t, ctx, "nonexistent").Expect(false)
+
+	// Only the scheduler of the message may cancel it
+	schedulerCtx := Context()
+	frame.Of(schedulerCtx).SetFromHost("scheduler.of.cancel")
+	otherCtx := Context()
+	frame.Of(otherCtx).SetFromHost("other.than.scheduler.of.cancel")
+	id, _ = Schedule(t, schedulerCtx, deliverAt, request, false).NoError().Get()
+	Cancel(t, otherCtx, id).ErrorCode(http.StatusForbidden)
+	Cancel(t, ctx, id).ErrorCode(http.StatusForbidden)
+	Cancel(t, schedulerCtx, id).Expect(true)
+}
+
+func TestScheduler_DeliverDue(t *testing.T) {
+	// No parallel
+
+	ctx := Context()
+
+	var delivered atomic.Int32
+	var lastBody atomic.Value
+	con := connector.New("deliver.due.target")
+	con.Subscribe("POST", "/ok", func(w http.ResponseWriter, r *http.Request) error {
+		var buf bytes.Buffer
+		buf.ReadFrom(r.Body)
+		lastBody.Store(buf.String())
+		delivered.Add(1)
+		return nil
+	})
+	con.Subscribe("POST", "/fail", func(w http.ResponseWriter, r *http.Request) error {
+		delivered.Add(1)
+		return errors.New("oops")
+	})
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Schedule two messages an hour and two hours from now
+	now := time.Now()
+	Schedule(t, ctx, now.Add(time.Hour), serialize(t, "POST", "https://deliver.due.target/ok", []byte("Hello")), false).NoError()
+	Schedule(t, ctx, now.Add(2*time.Hour), serialize(t, "POST", "https://deliver.due.target/fail", nil), false).NoError()
+
+	// Nothing is due yet
+	DeliverDue(t, ctx).Expect(0)
+	testarossa.Equal(t, int32(0), delivered.Load())
+
+	// Fast-forward
+	frame.Of(ctx).SetClockShift(time.Hour + time.Minute)
+	DeliverDue(t, ctx).Expect(1)
+	testarossa.Equal(t, int32(1), delivered.Load())
+	testarossa.Equal(t, "Hello", lastBody.Load())
+
+	// Delivered messages are removed even if they fail
+	frame.Of(ctx).SetClockShift(3 * time.Hour)
+	DeliverDue(t, ctx).Expect(1)
+	testarossa.Equal(t, int32(2), delivered.Load())
+	DeliverDue(t, ctx).Expect(0)
+	testarossa.Equal(t, int32(2), delivered.Load())
+}
+
+func TestScheduler_PeriodicDelivery(t *testing.T) {
+	// No parallel
+
+	ctx := Context()
+
+	var delivered atomic.Int32
+	con := connector.New("periodic.delivery.target")
+	con.Subscribe("POST", "/ok", func(w http.ResponseWriter, r *http.Request) error {
+		delivered.Add(1)
+		return nil
+	})
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	Schedule(t, ctx, time.Now().Add(time.Hour), serialize(t, "POST", "https://periodic.delivery.target/ok", nil), false).NoError()
+	PeriodicDelivery(t, ctx).NoError()
+	testarossa.Equal(t, int32(0), delivered.Load())
+
+	frame.Of(ctx).SetClockShift(2 * time.Hour)
+	PeriodicDelivery(t, ctx).NoError()
+	testarossa.Equal(t, int32(1), delivered.Load())
+}
+
+func TestScheduler_DeliverAfter(t *testing.T) {
+	// No parallel
+
+	ctx := Context()
+
+	var delivered atomic.Int32
+	var lastBody atomic.Value
+	con := connector.New("deliver.after.target")
+	con.Subscribe("POST", "/ok", func(w http.ResponseWriter, r *http.Request) error {
+		var buf bytes.Buffer
+		buf.ReadFrom(r.Body)
+		lastBody.Store(buf.String())
+		delivered.Add(1)
+		return nil
+	})
+	con.Subscribe("POST", "/multicast", func(w http.ResponseWriter, r *http.Request) error {
+		delivered.Add(1)
+		return nil
+	}, sub.NoQueue())
+	err := App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// Schedule with the connector
+	_, err = con.Request(ctx, pub.POST("https://deliver.after.target/ok"), pub.Body("Later"), pub.DeliverAfter(time.Hour))
+	testarossa.NoError(t, err)
+	_, err = con.Request(ctx, pub.POST("https://deliver.after.target/multicast"), pub.Multicast(), pub.DeliverAt(time.Now().Add(time.Hour)))
+	testarossa.NoError(t, err)
+	_, err = con.Request(ctx, pub.POST("https://deliver.after.target/ok"), pub.DeliverAfter(time.Hour), pub.Durable())
+	testarossa.Error(t, err)
+
+	DeliverDue(t, ctx).Expect(0)
+	testarossa.Equal(t, int32(0), delivered.Load())
+
+	// The time of delivery is relative to the clock shift of the context that scheduled the request
+	shiftedCtx := frame.ContextWithFrame(context.Background())
+	frame.Of(shiftedCtx).SetClockShift(24 * time.Hour)
+	_, err = con.Request(shiftedCtx, pub.POST("https://deliver.after.target/ok"), pub.Body("Later"), pub.DeliverAt(time.Now().Add(25*time.Hour)))
+	testarossa.NoError(t, err)
+
+	frame.Of(ctx).SetClockShift(2 * time.Hour)
+	DeliverDue(t, ctx).Expect(3)
+	testarossa.Equal(t, int32(3), delivered.Load())
+	testarossa.Equal(t, "Later", lastBody.Load())
+}
+
+func TestScheduler_OnBehalfOf(t *testing.T) {
+	// No parallel
+
+	ctx := Context()
+
+	var delivered atomic.Int32
+	var onBehalfOf atomic.Value
+	target := connector.New("on.behalf.of.target")
+	target.Subscribe("POST", "/restricted", func(w http.ResponseWriter, r *http.Request) error {
+		onBehalfOf.Store(frame.Of(r).OnBehalfOf())
+		delivered.Add(1)
+		return nil
+	}, sub.AllowCallers("allowed.on.behalf.of"))
+	allowed := connector.New("allowed.on.behalf.of")
+	denied := connector.New("denied.on.behalf.of")
+	err := App.AddAndStartup(target, allowed, denied)
+	testarossa.NoError(t, err)
+	defer target.Shutdown()
+	defer allowed.Shutdown()
+	defer denied.Shutdown()
+
+	// The callers of the destination are verified when the request is scheduled
+	_, err = denied.Request(ctx, pub.POST("https://on.behalf.of.target/restricted"), pub.DeliverAfter(time.Hour))
+	testarossa.Equal(t, http.StatusForbidden, errors.StatusCode(err))
+	_, err = allowed.Request(ctx, pub.POST("https://on.behalf.of.target/restricted"), pub.DeliverAfter(time.Hour))
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, int32(0), delivered.Load())
+
+	// The request is delivered on behalf of the original caller
+	frame.Of(ctx).SetClockShift(2 * time.Hour)
+	DeliverDue(t, ctx).Expect(1)
+	testarossa.Equal(t, int32(1), delivered.Load())
+	testarossa.Equal(t, "allowed.on.behalf.of", onBehalfOf.Load())
+}
+
+func TestScheduler_ActorExpiration(t *testing.T) {
+	// No parallel
+
+	ctx := Context()
+
+	Svc.SetActorKeys("scheduler1234")
+	defer Svc.SetActorKeys("")
+
+	var delivered atomic.Int32
+	var exp atomic.Value
+	con := connector.New("actor.expiration.target")
+	con.Subscribe("POST", "/ok", func(w http.ResponseWriter, r *http.Request) error {
+		exp.Store(frame.Of(r).Actor()["exp"])
+		delivered.Add(1)
+		return nil
+	}, sub.Actor("sub"))
+	err := con.SetConfig("ActorKeys", "scheduler1234")
+	testarossa.NoError(t, err)
+	err = App.AddAndStartup(con)
+	testarossa.NoError(t, err)
+	defer con.Shutdown()
+
+	// The token is signed again with its original expiration
+	expAt := time.Now().Add(time.Hour).Unix()
+	token, err := Svc.SignActor(frame.Actor{"sub": "harry", "exp": expAt})
+	testarossa.NoError(t, err)
+	httpReq, err := http.NewRequest("POST", "https://actor.expiration.target/ok", nil)
+	testarossa.NoError(t, err)
+	frame.Of(httpReq).SetActorToken(token)
+	var buf bytes.Buffer
+	httpReq.WriteProxy(&buf)
+	Schedule(t, ctx, time.Now().Add(time.Minute), buf.Bytes(), false).NoError()
+
+	shiftedCtx := Context()
+	frame.Of(shiftedCtx).SetClockShift(2 * time.Minute)
+	DeliverDue(t, shiftedCtx).Expect(1)
+	testarossa.Equal(t, int32(1), delivered.Load())
+	testarossa.Equal(t, float64(expAt), exp.Load())
+
+	// A message is not delivered once its actor expired
+	token, err = Svc.SignActor(frame.Actor{"sub": "harry", "exp": time.Now().Add(time.Second).Unix()})
+	testarossa.NoError(t, err)
+	frame.Of(httpReq).SetActorToken(token)
+	buf.Reset()
+	httpReq.WriteProxy(&buf)
+	Schedule(t, ctx, time.Now().Add(time.Minute), buf.Bytes(), false).NoError()
+
+	time.Sleep(2 * time.Second)
+	DeliverDue(t, shiftedCtx).Expect(1)
+	testarossa.Equal(t, int32(1), delivered.Load())
+	DeliverDue(t, shiftedCtx).Expect(0)
+}
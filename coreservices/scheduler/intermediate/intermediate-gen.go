@@ -0,0 +1,315 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by Microbus. DO NOT EDIT.
+
+/*
+Package intermediate serves as the foundation of the scheduler.core microservice.
+
+The scheduler is a core microservice that holds on to messages published with pub.DeliverAt or pub.DeliverAfter
+and forwards them to their destination when they are due.
+*/
+package intermediate
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/cfg"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/openapi"
+	"github.com/microbus-io/fabric/service"
+	"github.com/microbus-io/fabric/sub"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/microbus-io/fabric/coreservices/scheduler/resources"
+	"github.com/microbus-io/fabric/coreservices/scheduler/schedulerapi"
+)
+
+var (
+	_ context.Context
+	_ *embed.FS
+	_ *json.Decoder
+	_ fmt.Stringer
+	_ *http.Request
+	_ filepath.WalkFunc
+	_ strconv.NumError
+	_ strings.Reader
+	_ time.Duration
+	_ cfg.Option
+	_ *errors.TracedError
+	_ frame.Frame
+	_ *httpx.ResponseRecorder
+	_ *openapi.Service
+	_ service.Service
+	_ sub.Option
+	_ yaml.Encoder
+	_ schedulerapi.Client
+)
+
+// ToDo defines the interface that the microservice must implement.
+// The intermediate delegates handling to this interface.
+type ToDo interface {
+	OnStartup(ctx context.Context) (err error)
+	OnShutdown(ctx context.Context) (err error)
+	Schedule(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error)
+	Cancel(ctx context.Context, id string) (cancelled bool, err error)
+	DeliverDue(ctx context.Context) (delivered int, err error)
+	PeriodicDelivery(ctx context.Context) (err error)
+}
+
+// Intermediate extends and customizes the generic base connector.
+// Code generated microservices then extend the intermediate.
+type Intermediate struct {
+	*connector.Connector
+	impl ToDo
+}
+
+// NewService creates a new intermediate service.
+func NewService(impl ToDo, version int) *Intermediate {
+	svc := &Intermediate{
+		Connector: connector.New("scheduler.core"),
+		impl: impl,
+	}
+	svc.SetVersion(version)
+	svc.SetDescription(`The scheduler is a core microservice that holds on to messages published with pub.DeliverAt or pub.DeliverAfter
+and forwards them to their destination when they are due.`)
+	
+	// Lifecycle
+	svc.SetOnStartup(svc.impl.OnStartup)
+	svc.SetOnShutdown(svc.impl.OnShutdown)
+
+	// Configs
+	svc.SetOnConfigChanged(svc.doOnConfigChanged)
+	svc.DefineConfig(
+		"SQL",
+		cfg.Description(`SQL is the connection string to the MySQL database in which pending messages are persisted.
+It is required, except in the TESTING deployment in which pending messages are held in memory if left empty.`),
+	)
+	svc.DefineConfig(
+		"ActorKeys",
+		cfg.Description(`ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The actor token of a request is validated when the request is scheduled and is signed again when it is delivered,
+so that it does not expire in the meantime. Actor tokens are delivered as is if left empty.`),
+		cfg.Secret(),
+	)
+
+	// OpenAPI
+	svc.Subscribe("GET", `:0/openapi.json`, svc.doOpenAPI)	
+
+	// Functions
+	svc.Subscribe(`ANY`, `:443/schedule`, svc.doSchedule)
+	svc.Subscribe(`ANY`, `:443/cancel`, svc.doCancel)
+	svc.Subscribe(`ANY`, `:443/deliver-due`, svc.doDeliverDue)
+
+	// Tickers
+	intervalPeriodicDelivery, _ := time.ParseDuration("1s")
+	svc.StartTicker("PeriodicDelivery", intervalPeriodicDelivery, svc.impl.PeriodicDelivery)
+
+	// Resources file system
+	svc.SetResFS(resources.FS)
+
+	return svc
+}
+
+// doOpenAPI renders the OpenAPI document of the microservice.
+func (svc *Intermediate) doOpenAPI(w http.ResponseWriter, r *http.Request) error {
+	oapiSvc := openapi.Service{
+		ServiceName: svc.Hostname(),
+		Description: svc.Description(),
+		Version:     svc.Version(),
+		Endpoints:   []*openapi.Endpoint{},
+		RemoteURI:   frame.Of(r).XForwardedFullURL(),
+	}
+
+	if len(oapiSvc.Endpoints) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(&oapiSvc)
+	return errors.Trace(err)
+}
+
+// doOnConfigChanged is called when the config of the microservice changes.
+func (svc *Intermediate) doOnConfigChanged(ctx context.Context, changed func(string) bool) (err error) {
+	return nil
+}
+
+/*
+SQL is the connection string to the MySQL database in which pending messages are persisted.
+It is required, except in the TESTING deployment in which pending messages are held in memory if left empty.
+*/
+func (svc *Intermediate) SQL() (dsn string) {
+	_val := svc.Config("SQL")
+	return _val
+}
+
+/*
+SetSQL sets the value of the configuration property.
+
+SQL is the connection string to the MySQL database in which pending messages are persisted.
+It is required, except in the TESTING deployment in which pending messages are held in memory if left empty.
+*/
+func (svc *Intermediate) SetSQL(dsn string) error {
+	return svc.SetConfig("SQL", fmt.Sprintf("%v", dsn))
+}
+
+/*
+ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The actor token of a request is validated when the request is scheduled and is signed again when it is delivered,
+so that it does not expire in the meantime. Actor tokens are delivered as is if left empty.
+*/
+func (svc *Intermediate) ActorKeys() (keys string) {
+	_val := svc.Config("ActorKeys")
+	return _val
+}
+
+/*
+SetActorKeys sets the value of the configuration property.
+
+ActorKeys are the secret keys with which actor tokens are signed and validated, separated by commas or spaces.
+The actor token of a request is validated when the request is scheduled and is signed again when it is delivered,
+so that it does not expire in the meantime. Actor tokens are delivered as is if left empty.
+*/
+func (svc *Intermediate) SetActorKeys(keys string) error {
+	return svc.SetConfig("ActorKeys", fmt.Sprintf("%v", keys))
+}
+
+// doSchedule handles marshaling for the Schedule function.
+func (svc *Intermediate) doSchedule(w http.ResponseWriter, r *http.Request) error {
+	var i schedulerapi.ScheduleIn
+	var o schedulerapi.ScheduleOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:443/schedule`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:443/schedule`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.ID, err = svc.impl.Schedule(
+		r.Context(),
+		i.DeliverAt,
+		i.Request,
+		i.Multicast,
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doCancel handles marshaling for the Cancel function.
+func (svc *Intermediate) doCancel(w http.ResponseWriter, r *http.Request) error {
+	var i schedulerapi.CancelIn
+	var o schedulerapi.CancelOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:443/cancel`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:443/cancel`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Cancelled, err = svc.impl.Cancel(
+		r.Context(),
+		i.ID,
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// doDeliverDue handles marshaling for the DeliverDue function.
+func (svc *Intermediate) doDeliverDue(w http.ResponseWriter, r *http.Request) error {
+	var i schedulerapi.DeliverDueIn
+	var o schedulerapi.DeliverDueOut
+	err := httpx.ParseRequestData(r, &i)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if strings.ContainsAny(`:443/deliver-due`, "{}") {
+		pathArgs, err := httpx.ExtractPathArguments(httpx.JoinHostAndPath("host", `:443/deliver-due`), r.URL.Path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = httpx.DecodeDeepObject(pathArgs, &i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	o.Delivered, err = svc.impl.DeliverDue(
+		r.Context(),
+	)
+	if err != nil {
+		return err // No trace
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if svc.Deployment() == connector.LOCAL {
+		encoder.SetIndent("", "  ")
+	}
+	err = encoder.Encode(o)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
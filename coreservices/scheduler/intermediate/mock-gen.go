@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by Microbus. DO NOT EDIT.
+
+package intermediate
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
+
+	"github.com/microbus-io/fabric/coreservices/scheduler/schedulerapi"
+)
+
+var (
+	_ context.Context
+	_ *http.Request
+	_ time.Duration
+	_ *errors.TracedError
+	_ schedulerapi.Client
+)
+
+// Mock is a mockable version of the scheduler.core microservice, allowing functions, event sinks and web handlers to be mocked.
+type Mock struct {
+	*Intermediate
+	mockSchedule func(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error)
+	mockCancel func(ctx context.Context, id string) (cancelled bool, err error)
+	mockDeliverDue func(ctx context.Context) (delivered int, err error)
+}
+
+// NewMock creates a new mockable version of the microservice.
+func NewMock() *Mock {
+	m := &Mock{}
+	m.Intermediate = NewService(m, 7357) // Stands for TEST
+	return m
+}
+
+// OnStartup makes sure that the mock is not executed in a non-dev environment.
+func (svc *Mock) OnStartup(ctx context.Context) (err error) {
+	if svc.Deployment() != connector.LOCAL && svc.Deployment() != connector.TESTING {
+		return errors.Newf("mocking disallowed in '%s' deployment", svc.Deployment())
+	}
+	return nil
+}
+
+// OnShutdown is a no op.
+func (svc *Mock) OnShutdown(ctx context.Context) (err error) {
+	return nil
+}
+
+// MockSchedule sets up a mock handler for the Schedule endpoint.
+func (svc *Mock) MockSchedule(handler func(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error)) *Mock {
+	svc.mockSchedule = handler
+	return svc
+}
+
+// Schedule runs the mock handler set by MockSchedule.
+func (svc *Mock) Schedule(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error) {
+	if svc.mockSchedule == nil {
+		err = errors.New("mocked endpoint 'Schedule' not implemented")
+		return
+	}
+	return svc.mockSchedule(ctx, deliverAt, request, multicast)
+}
+
+// MockCancel sets up a mock handler for the Cancel endpoint.
+func (svc *Mock) MockCancel(handler func(ctx context.Context, id string) (cancelled bool, err error)) *Mock {
+	svc.mockCancel = handler
+	return svc
+}
+
+// Cancel runs the mock handler set by MockCancel.
+func (svc *Mock) Cancel(ctx context.Context, id string) (cancelled bool, err error) {
+	if svc.mockCancel == nil {
+		err = errors.New("mocked endpoint 'Cancel' not implemented")
+		return
+	}
+	return svc.mockCancel(ctx, id)
+}
+
+// MockDeliverDue sets up a mock handler for the DeliverDue endpoint.
+func (svc *Mock) MockDeliverDue(handler func(ctx context.Context) (delivered int, err error)) *Mock {
+	svc.mockDeliverDue = handler
+	return svc
+}
+
+// DeliverDue runs the mock handler set by MockDeliverDue.
+func (svc *Mock) DeliverDue(ctx context.Context) (delivered int, err error) {
+	if svc.mockDeliverDue == nil {
+		err = errors.New("mocked endpoint 'DeliverDue' not implemented")
+		return
+	}
+	return svc.mockDeliverDue(ctx)
+}
+
+// PeriodicDelivery is a no op.
+func (svc *Mock) PeriodicDelivery(ctx context.Context) (err error) {
+	return nil
+}
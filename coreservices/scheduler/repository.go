@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/microbus-io/fabric/errors"
+)
+
+// pending is a message that is pending delivery.
+type pending struct {
+	id        string
+	deliverAt time.Time
+	request   []byte
+	multicast bool
+}
+
+// repository persists the pending messages in a SQL database, or holds them in memory if no database is configured.
+type repository struct {
+	db      *sql.DB
+	mem     map[string]*pending
+	claimed map[string]time.Time
+	mux     sync.Mutex
+}
+
+// Open connects to the SQL database and creates the table of pending messages if it does not already exist.
+// The repository holds the pending messages in memory if the data source name is empty.
+func (r *repository) Open(ctx context.Context, dsn string) error {
+	r.mem = map[string]*pending{}
+	r.claimed = map[string]time.Time{}
+	if dsn == "" {
+		return nil
+	}
+	// Times are scanned from the database into time.Time
+	mysqlConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	mysqlConfig.ParseTime = true
+	db, err := sql.Open("mysql", mysqlConfig.FormatDSN())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS scheduler_messages (
+			message_id VARCHAR(32) CHARACTER SET ascii NOT NULL,
+			deliver_at DATETIME(6) NOT NULL,
+			claimed_until DATETIME(6),
+			multicast BOOLEAN NOT NULL,
+			request LONGBLOB NOT NULL,
+			PRIMARY KEY (message_id),
+			INDEX (deliver_at)
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return errors.Trace(err)
+	}
+	r.db = db
+	return nil
+}
+
+// Close closes the connection to the SQL database.
+func (r *repository) Close() error {
+	if r.db == nil {
+		return nil
+	}
+	err := r.db.Close()
+	r.db = nil
+	return errors.Trace(err)
+}
+
+// Insert adds a pending message.
+func (r *repository) Insert(ctx context.Context, p *pending) error {
+	if r.db == nil {
+		r.mux.Lock()
+		r.mem[p.id] = p
+		r.mux.Unlock()
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO scheduler_messages (message_id,deliver_at,multicast,request) VALUES (?,?,?,?)`,
+		p.id, p.deliverAt.UTC(), p.multicast, p.request,
+	)
+	return errors.Trace(err)
+}
+
+// Load returns a pending message, or nil if it was not found.
+func (r *repository) Load(ctx context.Context, id string) (*pending, error) {
+	if r.db == nil {
+		r.mux.Lock()
+		p := r.mem[id]
+		r.mux.Unlock()
+		return p, nil
+	}
+	var p pending
+	err := r.db.QueryRowContext(ctx,
+		`SELECT message_id,deliver_at,multicast,request FROM scheduler_messages WHERE message_id=?`,
+		id,
+	).Scan(&p.id, &p.deliverAt, &p.multicast, &p.request)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &p, nil
+}
+
+// Delete removes a pending message, returning false if it was not found.
+func (r *repository) Delete(ctx context.Context, id string) (bool, error) {
+	if r.db == nil {
+		r.mux.Lock()
+		_, ok := r.mem[id]
+		delete(r.mem, id)
+		delete(r.claimed, id)
+		r.mux.Unlock()
+		return ok, nil
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM scheduler_messages WHERE message_id=?`, id)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return affected > 0, nil
+}
+
+// ClaimDue claims up to a limit of pending messages that are due by the indicated time, in the order they are due.
+// A claimed message is not claimed again until the claim expires, allowing replicas to share the database.
+func (r *repository) ClaimDue(ctx context.Context, now time.Time, claimFor time.Duration, limit int) ([]*pending, error) {
+	var result []*pending
+	if r.db == nil {
+		r.mux.Lock()
+		for id, p := range r.mem {
+			if p.deliverAt.After(now) {
+				continue
+			}
+			if until, ok := r.claimed[id]; ok && time.Now().Before(until) {
+				continue
+			}
+			result = append(result, p)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].deliverAt.Before(result[j].deliverAt)
+		})
+		if len(result) > limit {
+			result = result[:limit]
+		}
+		for _, p := range result {
+			r.claimed[p.id] = time.Now().Add(claimFor)
+		}
+		r.mux.Unlock()
+		return result, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT message_id,deliver_at,multicast,request FROM scheduler_messages
+		WHERE deliver_at<=? AND (claimed_until IS NULL OR claimed_until<?)
+		ORDER BY deliver_at LIMIT ?`,
+		now.UTC(), time.Now().UTC(), limit,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var candidates []*pending
+	for rows.Next() {
+		var p pending
+		err = rows.Scan(&p.id, &p.deliverAt, &p.multicast, &p.request)
+		if err != nil {
+			rows.Close()
+			return nil, errors.Trace(err)
+		}
+		candidates = append(candidates, &p)
+	}
+	rows.Close()
+	for _, p := range candidates {
+		// Another replica may have claimed the message in the meantime
+		res, err := r.db.ExecContext(ctx,
+			`UPDATE scheduler_messages SET claimed_until=?
+			WHERE message_id=? AND (claimed_until IS NULL OR claimed_until<?)`,
+			time.Now().Add(claimFor).UTC(), p.id, time.Now().UTC(),
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if affected > 0 {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,416 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by Microbus. DO NOT EDIT.
+
+/*
+Package schedulerapi implements the public API of the scheduler.core microservice,
+including clients and data structures.
+
+The scheduler is a core microservice that holds on to messages published with pub.DeliverAt or pub.DeliverAfter
+and forwards them to their destination when they are due.
+*/
+package schedulerapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/service"
+	"github.com/microbus-io/fabric/sub"
+)
+
+var (
+	_ context.Context
+	_ *json.Decoder
+	_ io.Reader
+	_ *http.Request
+	_ *url.URL
+	_ strings.Reader
+	_ time.Duration
+	_ *errors.TracedError
+	_ *httpx.BodyReader
+	_ pub.Option
+	_ sub.Option
+)
+
+// Hostname is the default hostname of the microservice: scheduler.core.
+const Hostname = "scheduler.core"
+
+// Fully-qualified URLs of the microservice's endpoints.
+var (
+	URLOfSchedule = httpx.JoinHostAndPath(Hostname, `:443/schedule`)
+	URLOfCancel = httpx.JoinHostAndPath(Hostname, `:443/cancel`)
+	URLOfDeliverDue = httpx.JoinHostAndPath(Hostname, `:443/deliver-due`)
+)
+
+// Client is an interface to calling the endpoints of the scheduler.core microservice.
+// This simple version is for unicast calls.
+type Client struct {
+	svc  service.Publisher
+	host string
+}
+
+// NewClient creates a new unicast client to the scheduler.core microservice.
+func NewClient(caller service.Publisher) *Client {
+	return &Client{
+		svc:  caller,
+		host: "scheduler.core",
+	}
+}
+
+// ForHost replaces the default hostname of this client.
+func (_c *Client) ForHost(host string) *Client {
+	_c.host = host
+	return _c
+}
+
+// MulticastClient is an interface to calling the endpoints of the scheduler.core microservice.
+// This advanced version is for multicast calls.
+type MulticastClient struct {
+	svc  service.Publisher
+	host string
+}
+
+// NewMulticastClient creates a new multicast client to the scheduler.core microservice.
+func NewMulticastClient(caller service.Publisher) *MulticastClient {
+	return &MulticastClient{
+		svc:  caller,
+		host: "scheduler.core",
+	}
+}
+
+// ForHost replaces the default hostname of this client.
+func (_c *MulticastClient) ForHost(host string) *MulticastClient {
+	_c.host = host
+	return _c
+}
+
+// ScheduleIn are the input arguments of Schedule.
+type ScheduleIn struct {
+	DeliverAt time.Time `json:"deliverAt"`
+	Request []byte `json:"request"`
+	Multicast bool `json:"multicast"`
+}
+
+// ScheduleOut are the return values of Schedule.
+type ScheduleOut struct {
+	ID string `json:"id"`
+}
+
+// ScheduleResponse is the response to Schedule.
+type ScheduleResponse struct {
+	data ScheduleOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *ScheduleResponse) Get() (id string, err error) {
+	id = _out.data.ID
+	err = _out.err
+	return
+}
+
+/*
+Schedule holds on to a serialized HTTP request until it is due for delivery.
+The time of delivery follows the clock shift of the context.
+*/
+func (_c *MulticastClient) Schedule(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) <-chan *ScheduleResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:443/schedule`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`deliverAt`: deliverAt,
+		`request`: request,
+		`multicast`: multicast,
+	})
+	_in := ScheduleIn{
+		deliverAt,
+		request,
+		multicast,
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *ScheduleResponse, cap(_ch))
+	for _i := range _ch {
+		var _r ScheduleResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Schedule holds on to a serialized HTTP request until it is due for delivery.
+The time of delivery follows the clock shift of the context.
+*/
+func (_c *Client) Schedule(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:443/schedule`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`deliverAt`: deliverAt,
+		`request`: request,
+		`multicast`: multicast,
+	})
+	_in := ScheduleIn{
+		deliverAt,
+		request,
+		multicast,
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out ScheduleOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	id = _out.ID
+	return
+}
+
+// CancelIn are the input arguments of Cancel.
+type CancelIn struct {
+	ID string `json:"id"`
+}
+
+// CancelOut are the return values of Cancel.
+type CancelOut struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+// CancelResponse is the response to Cancel.
+type CancelResponse struct {
+	data CancelOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *CancelResponse) Get() (cancelled bool, err error) {
+	cancelled = _out.data.Cancelled
+	err = _out.err
+	return
+}
+
+/*
+Cancel cancels the delivery of a pending message.
+Only the microservice that scheduled the message may cancel it.
+*/
+func (_c *MulticastClient) Cancel(ctx context.Context, id string) <-chan *CancelResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:443/cancel`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`id`: id,
+	})
+	_in := CancelIn{
+		id,
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *CancelResponse, cap(_ch))
+	for _i := range _ch {
+		var _r CancelResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+Cancel cancels the delivery of a pending message.
+Only the microservice that scheduled the message may cancel it.
+*/
+func (_c *Client) Cancel(ctx context.Context, id string) (cancelled bool, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:443/cancel`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+		`id`: id,
+	})
+	_in := CancelIn{
+		id,
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out CancelOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	cancelled = _out.Cancelled
+	return
+}
+
+// DeliverDueIn are the input arguments of DeliverDue.
+type DeliverDueIn struct {
+}
+
+// DeliverDueOut are the return values of DeliverDue.
+type DeliverDueOut struct {
+	Delivered int `json:"delivered"`
+}
+
+// DeliverDueResponse is the response to DeliverDue.
+type DeliverDueResponse struct {
+	data DeliverDueOut
+	HTTPResponse *http.Response
+	err error
+}
+
+// Get retrieves the return values.
+func (_out *DeliverDueResponse) Get() (delivered int, err error) {
+	delivered = _out.data.Delivered
+	err = _out.err
+	return
+}
+
+/*
+DeliverDue forwards the pending messages that are due by the time of the context, which follows its clock shift.
+Pending messages are forwarded periodically regardless, so it is only necessary to call this endpoint in order to fast-forward time in tests.
+*/
+func (_c *MulticastClient) DeliverDue(ctx context.Context) <-chan *DeliverDueResponse {
+	_url := httpx.JoinHostAndPath(_c.host, `:443/deliver-due`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := DeliverDueIn{
+	}
+	var _query url.Values
+	_body := _in
+	_ch := _c.svc.Publish(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+
+	_res := make(chan *DeliverDueResponse, cap(_ch))
+	for _i := range _ch {
+		var _r DeliverDueResponse
+		_httpRes, _err := _i.Get()
+		_r.HTTPResponse = _httpRes
+		if _err != nil {
+			_r.err = _err // No trace
+		} else {
+			_err = json.NewDecoder(_httpRes.Body).Decode(&(_r.data))
+			if _err != nil {
+				_r.err = errors.Trace(_err)
+			}
+		}
+		_res <- &_r
+	}
+	close(_res)
+	return _res
+}
+
+/*
+DeliverDue forwards the pending messages that are due by the time of the context, which follows its clock shift.
+Pending messages are forwarded periodically regardless, so it is only necessary to call this endpoint in order to fast-forward time in tests.
+*/
+func (_c *Client) DeliverDue(ctx context.Context) (delivered int, err error) {
+	var _err error
+	_url := httpx.JoinHostAndPath(_c.host, `:443/deliver-due`)
+	_url = httpx.InsertPathArguments(_url, httpx.QArgs{
+	})
+	_in := DeliverDueIn{
+	}
+	var _query url.Values
+	_body := _in
+	_httpRes, _err := _c.svc.Request(
+		ctx,
+		pub.Method(`POST`),
+		pub.URL(_url),
+		pub.Query(_query),
+		pub.Body(_body),
+	)
+	if _err != nil {
+		err = _err // No trace
+		return
+	}
+	var _out DeliverDueOut
+	_err = json.NewDecoder(_httpRes.Body).Decode(&_out)
+	if _err != nil {
+		err = errors.Trace(_err)
+		return
+	}
+	delivered = _out.Delivered
+	return
+}
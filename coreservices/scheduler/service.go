@@ -0,0 +1,285 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/rand"
+
+	"github.com/microbus-io/fabric/coreservices/scheduler/intermediate"
+	"github.com/microbus-io/fabric/coreservices/scheduler/schedulerapi"
+)
+
+var (
+	_ context.Context
+	_ *http.Request
+	_ time.Duration
+	_ *errors.TracedError
+	_ *schedulerapi.Client
+)
+
+/*
+Service implements the scheduler.core microservice.
+
+The scheduler is a core microservice that holds on to messages published with pub.DeliverAt or pub.DeliverAfter
+and forwards them to their destination when they are due.
+*/
+type Service struct {
+	*intermediate.Intermediate // DO NOT REMOVE
+
+	repo *repository
+}
+
+const (
+	// claimDuration is how long a due message is claimed by the replica that is forwarding it
+	claimDuration = time.Minute
+	// batchSize is the maximum number of due messages that are forwarded at once
+	batchSize = 256
+)
+
+// OnStartup is called when the microservice is started up.
+func (svc *Service) OnStartup(ctx context.Context) (err error) {
+	// Pending messages must survive a restart
+	if svc.SQL() == "" && svc.Deployment() != connector.TESTING {
+		return errors.New("SQL is required")
+	}
+	svc.repo = &repository{}
+	err = svc.repo.Open(ctx, svc.SQL())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// OnShutdown is called when the microservice is shut down.
+func (svc *Service) OnShutdown(ctx context.Context) (err error) {
+	if svc.repo != nil {
+		err = svc.repo.Close()
+		svc.repo = nil
+	}
+	return errors.Trace(err)
+}
+
+/*
+Schedule holds on to a serialized HTTP request until it is due for delivery.
+The time of delivery follows the clock shift of the context.
+*/
+func (svc *Service) Schedule(ctx context.Context, deliverAt time.Time, request []byte, multicast bool) (id string, err error) {
+	if deliverAt.IsZero() {
+		return "", errors.Newc(http.StatusBadRequest, "time of delivery must be set")
+	}
+	httpReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(request)))
+	if err != nil {
+		return "", errors.Tracec(http.StatusBadRequest, err)
+	}
+	// The request is delivered on behalf of the caller of this endpoint
+	frame.Of(httpReq).SetFromHost(frame.Of(ctx).FromHost())
+	frame.Of(httpReq).SetActorVerified(false)
+	if token := frame.Of(httpReq).ActorToken(); token != "" && svc.ActorKeys() != "" {
+		_, err = svc.VerifyActor(token)
+		if err != nil {
+			return "", err // No trace
+		}
+		// Only actors that are validated now are signed again when the message is delivered
+		frame.Of(httpReq).SetActorVerified(true)
+	}
+	err = svc.preflight(ctx, httpReq, multicast)
+	if err != nil {
+		return "", err // No trace
+	}
+	var buf bytes.Buffer
+	err = httpReq.WriteProxy(&buf)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	request = buf.Bytes()
+	// Pending messages are stored by the real time of their delivery
+	p := &pending{
+		id:        rand.AlphaNum64(16),
+		deliverAt: deliverAt.Add(-frame.Of(ctx).ClockShift()),
+		request:   request,
+		multicast: multicast,
+	}
+	err = svc.repo.Insert(ctx, p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return p.id, nil
+}
+
+/*
+Cancel cancels the delivery of a pending message.
+Only the microservice that scheduled the message may cancel it.
+*/
+func (svc *Service) Cancel(ctx context.Context, id string) (cancelled bool, err error) {
+	p, err := svc.repo.Load(ctx, id)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if p == nil {
+		return false, nil
+	}
+	httpReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(p.request)))
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if frame.Of(httpReq).FromHost() != frame.Of(ctx).FromHost() {
+		return false, errors.Newc(http.StatusForbidden, "message was scheduled by another caller")
+	}
+	cancelled, err = svc.repo.Delete(ctx, id)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return cancelled, nil
+}
+
+/*
+DeliverDue forwards the pending messages that are due by the time of the context, which follows its clock shift.
+Pending messages are forwarded periodically regardless, so it is only necessary to call this endpoint in order to fast-forward time in tests.
+*/
+func (svc *Service) DeliverDue(ctx context.Context) (delivered int, err error) {
+	// The clock shift of the context fast-forwards the delivery of pending messages
+	now := svc.Now(ctx)
+	for {
+		due, err := svc.repo.ClaimDue(ctx, now, claimDuration, batchSize)
+		if err != nil {
+			return delivered, errors.Trace(err)
+		}
+		if len(due) == 0 {
+			break
+		}
+		jobs := []func() error{}
+		for _, p := range due {
+			p := p
+			jobs = append(jobs, func() error {
+				return svc.forward(ctx, p)
+			})
+		}
+		err = svc.Parallel(jobs...)
+		if err != nil {
+			return delivered, errors.Trace(err)
+		}
+		delivered += len(due)
+		if len(due) < batchSize {
+			break
+		}
+	}
+	return delivered, nil
+}
+
+// preflight verifies with the destination that the caller and the actor of the request are allowed,
+// failing with the 401 or 403 error of the destination if they are not.
+// Destinations that are not reachable at this time are not verified. They verify the request when it is delivered.
+func (svc *Service) preflight(ctx context.Context, httpReq *http.Request, multicast bool) error {
+	options := []pub.Option{
+		pub.Method(httpReq.Method),
+		pub.URL(httpReq.URL.String()),
+		pub.Header(frame.HeaderOnBehalfOf, frame.Of(httpReq).FromHost()),
+		pub.Header(frame.HeaderPreflight, "1"),
+	}
+	if token := frame.Of(httpReq).ActorToken(); token != "" {
+		options = append(options, pub.Actor(token))
+	}
+	if multicast {
+		options = append(options, pub.Multicast())
+	} else {
+		options = append(options, pub.Unicast())
+	}
+	for r := range svc.Publish(ctx, options...) {
+		_, err := r.Get()
+		statusCode := errors.StatusCode(err)
+		if err != nil && (statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden) {
+			return err // No trace
+		}
+	}
+	return nil
+}
+
+// forward delivers the pending message to its destination on behalf of the original caller, and removes it.
+// Errors returned by the destination are logged but do not cause the message to be delivered again.
+func (svc *Service) forward(ctx context.Context, p *pending) error {
+	httpReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(p.request)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	body, err := io.ReadAll(httpReq.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	frame.Of(httpReq).SetTimeBudget(0)
+	frame.Of(httpReq).SetOnBehalfOf(frame.Of(httpReq).FromHost())
+	if actor := frame.Of(httpReq).Actor(); actor != nil && svc.ActorKeys() != "" {
+		// The actor was validated when the request was scheduled, but it is not delivered past its expiration
+		if exp, ok := actor["exp"].(float64); ok && time.Now().Unix() >= int64(exp) {
+			svc.LogWarn(ctx, "Dropping scheduled message of expired actor",
+				"id", p.id,
+				"url", httpReq.URL.String(),
+			)
+			_, err = svc.repo.Delete(ctx, p.id)
+			return errors.Trace(err)
+		}
+		// The token is signed again, retaining its original expiration
+		delete(actor, "iat")
+		token, err := svc.SignActor(actor)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		frame.Of(httpReq).SetActorToken(token)
+	}
+	frame.Of(httpReq).SetActorVerified(false)
+	options := []pub.Option{
+		pub.Method(httpReq.Method),
+		pub.URL(httpReq.URL.String()),
+		pub.CopyHeaders(httpReq.Header),
+		pub.Body(body),
+	}
+	if p.multicast {
+		options = append(options, pub.Multicast())
+	} else {
+		options = append(options, pub.Unicast())
+	}
+	for r := range svc.Publish(ctx, options...) {
+		_, err := r.Get()
+		if err != nil {
+			svc.LogWarn(ctx, "Delivering scheduled message",
+				"error", err,
+				"id", p.id,
+				"url", httpReq.URL.String(),
+			)
+		}
+	}
+	_, err = svc.repo.Delete(ctx, p.id)
+	return errors.Trace(err)
+}
+
+/*
+PeriodicDelivery forwards the pending messages that are due.
+*/
+func (svc *Service) PeriodicDelivery(ctx context.Context) (err error) {
+	_, err = svc.DeliverDue(ctx)
+	return errors.Trace(err)
+}
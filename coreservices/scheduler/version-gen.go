@@ -0,0 +1,29 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by Microbus. DO NOT EDIT.
+
+package scheduler
+
+const Version = 8
+const SourceCodeSHA256 = "2a76283daea76f3eebb87da03865430e58b0014a476c3fe904aeaad3b62cdaa9"
+const Timestamp = "2026-10-17T10:57:22.918293533Z"
+
+/* {
+	"ver": 8,
+	"sha256": "2a76283daea76f3eebb87da03865430e58b0014a476c3fe904aeaad3b62cdaa9",
+	"ts": "2026-10-17T10:57:22.918293533Z"
+} */
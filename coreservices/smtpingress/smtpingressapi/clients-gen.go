@@ -208,7 +208,7 @@ func (_c *MulticastTrigger) OnIncomingEmail(ctx context.Context, mailMessage *Em
 /*
 OnIncomingEmail is triggered when a new email message is received.
 */
-func (_c *Hook) OnIncomingEmail(handler func(ctx context.Context, mailMessage *Email) (err error)) error {
+func (_c *Hook) OnIncomingEmail(handler func(ctx context.Context, mailMessage *Email) (err error), options ...sub.Option) error {
 	doOnIncomingEmail := func(w http.ResponseWriter, r *http.Request) error {
 		var i OnIncomingEmailIn
 		var o OnIncomingEmailOut
@@ -235,5 +235,5 @@ func (_c *Hook) OnIncomingEmail(handler func(ctx context.Context, mailMessage *E
 	if handler == nil {
 		return _c.svc.Unsubscribe(`POST`, path)
 	}
-	return _c.svc.Subscribe(`POST`, path, doOnIncomingEmail)
+	return _c.svc.Subscribe(`POST`, path, doOnIncomingEmail, options...)
 }
@@ -18,12 +18,12 @@ limitations under the License.
 
 package smtpingress
 
-const Version = 126
-const SourceCodeSHA256 = "a9f4809c316f7f53b6727e0ff982d1c9c03a135518e024c185d2f71102890fbb"
-const Timestamp = "2024-09-05T05:11:10.58323Z"
+const Version = 127
+const SourceCodeSHA256 = "17a7e6579ed398b20885554477cd4238c4b321041d1b3c76ec9e7825fc15b4d2"
+const Timestamp = "2026-10-17T01:54:10.063040463Z"
 
 /* {
-	"ver": 126,
-	"sha256": "a9f4809c316f7f53b6727e0ff982d1c9c03a135518e024c185d2f71102890fbb",
-	"ts": "2024-09-05T05:11:10.58323Z"
+	"ver": 127,
+	"sha256": "17a7e6579ed398b20885554477cd4238c4b321041d1b3c76ec9e7825fc15b4d2",
+	"ts": "2026-10-17T01:54:10.063040463Z"
 } */
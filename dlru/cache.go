@@ -544,7 +544,14 @@ func (c *Cache) Load(ctx context.Context, key string, options ...LoadOption) (va
 	value = nil
 	ok = false
 	u := fmt.Sprintf("%s/all?do=load&bump=%v&key=%s&ttl=%s", c.basePath, opts.Bump, url.QueryEscape(key), opts.MaxAge.String())
-	ch := c.svc.Publish(ctx, pub.GET(u))
+	pubOpts := []pub.Option{pub.GET(u)}
+	if !opts.ConsistencyCheck {
+		// Any peer that has the element will do
+		pubOpts = append(pubOpts, pub.UntilMatch(func(res *http.Response) bool {
+			return res.StatusCode == http.StatusOK
+		}))
+	}
+	ch := c.svc.Publish(ctx, pubOpts...)
 	for r := range ch {
 		res, err := r.Get()
 		if err != nil {
@@ -115,7 +115,7 @@ func NewService(impl ToDo, version int) *Intermediate {
 	svc.Subscribe("GET", `:0/openapi.json`, svc.doOpenAPI)	
 
 	// Functions
-	svc.Subscribe(`POST`, `:443/persons`, svc.doCreate)
+	svc.Subscribe(`POST`, `:443/persons`, svc.doCreate, sub.ClusterRateLimit(100, 100), sub.Idempotent())
 	svc.Subscribe(`GET`, `:443/persons/key/{key}`, svc.doLoad)
 	svc.Subscribe(`DELETE`, `:443/persons/key/{key}`, svc.doDelete)
 	svc.Subscribe(`PUT`, `:443/persons/key/{key}`, svc.doUpdate)
@@ -18,12 +18,12 @@ limitations under the License.
 
 package directory
 
-const Version = 217
-const SourceCodeSHA256 = "8e41f507358c64a6c7865872f4f956eab1000016abaf653f684df082d8fdd51b"
-const Timestamp = "2024-08-31T21:14:20.390917Z"
+const Version = 225
+const SourceCodeSHA256 = "aa4a75a5053758634f749d4ec8c1de9ee84f0c81870f9401a213340f4d7e55f8"
+const Timestamp = "2026-10-17T10:57:23.288920603Z"
 
 /* {
-	"ver": 217,
-	"sha256": "8e41f507358c64a6c7865872f4f956eab1000016abaf653f684df082d8fdd51b",
-	"ts": "2024-08-31T21:14:20.390917Z"
+	"ver": 225,
+	"sha256": "aa4a75a5053758634f749d4ec8c1de9ee84f0c81870f9401a213340f4d7e55f8",
+	"ts": "2026-10-17T10:57:23.288920603Z"
 } */
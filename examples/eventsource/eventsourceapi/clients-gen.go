@@ -312,7 +312,7 @@ func (_c *MulticastTrigger) OnAllowRegister(ctx context.Context, email string) <
 OnAllowRegister is called before a user is allowed to register.
 Event sinks are given the opportunity to block the registration.
 */
-func (_c *Hook) OnAllowRegister(handler func(ctx context.Context, email string) (allow bool, err error)) error {
+func (_c *Hook) OnAllowRegister(handler func(ctx context.Context, email string) (allow bool, err error), options ...sub.Option) error {
 	doOnAllowRegister := func(w http.ResponseWriter, r *http.Request) error {
 		var i OnAllowRegisterIn
 		var o OnAllowRegisterOut
@@ -339,7 +339,7 @@ func (_c *Hook) OnAllowRegister(handler func(ctx context.Context, email string)
 	if handler == nil {
 		return _c.svc.Unsubscribe(`POST`, path)
 	}
-	return _c.svc.Subscribe(`POST`, path, doOnAllowRegister)
+	return _c.svc.Subscribe(`POST`, path, doOnAllowRegister, options...)
 }
 
 // OnRegisteredIn are the input arguments of OnRegistered.
@@ -407,7 +407,7 @@ func (_c *MulticastTrigger) OnRegistered(ctx context.Context, email string) <-ch
 /*
 OnRegistered is called when a user is successfully registered.
 */
-func (_c *Hook) OnRegistered(handler func(ctx context.Context, email string) (err error)) error {
+func (_c *Hook) OnRegistered(handler func(ctx context.Context, email string) (err error), options ...sub.Option) error {
 	doOnRegistered := func(w http.ResponseWriter, r *http.Request) error {
 		var i OnRegisteredIn
 		var o OnRegisteredOut
@@ -434,5 +434,5 @@ func (_c *Hook) OnRegistered(handler func(ctx context.Context, email string) (er
 	if handler == nil {
 		return _c.svc.Unsubscribe(`POST`, path)
 	}
-	return _c.svc.Subscribe(`POST`, path, doOnRegistered)
+	return _c.svc.Subscribe(`POST`, path, doOnRegistered, options...)
 }
@@ -18,12 +18,12 @@ limitations under the License.
 
 package eventsource
 
-const Version = 205
-const SourceCodeSHA256 = "86953d2b2de8eca3aa63ab7bbec73018caace2803b17fe53d6945804215e1c94"
-const Timestamp = "2024-08-31T21:14:21.096672Z"
+const Version = 206
+const SourceCodeSHA256 = "42c7a04248a63fa3b8391bc8fc5090e5d672c77f2bc48827391bc8e8d4a6f158"
+const Timestamp = "2026-10-17T01:54:10.354992973Z"
 
 /* {
-	"ver": 205,
-	"sha256": "86953d2b2de8eca3aa63ab7bbec73018caace2803b17fe53d6945804215e1c94",
-	"ts": "2024-08-31T21:14:21.096672Z"
+	"ver": 206,
+	"sha256": "42c7a04248a63fa3b8391bc8fc5090e5d672c77f2bc48827391bc8e8d4a6f158",
+	"ts": "2026-10-17T01:54:10.354992973Z"
 } */
@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frame
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Actor is the set of claims of the actor on behalf of which a request is made.
+// Claims are typically the subject, issuer, expiration time, and roles or groups of a user.
+type Actor map[string]any
+
+// Subject is the "sub" claim that identifies the actor.
+func (a Actor) Subject() string {
+	sub, _ := a["sub"].(string)
+	return sub
+}
+
+// Issuer is the "iss" claim that identifies the issuer of the actor token.
+func (a Actor) Issuer() string {
+	iss, _ := a["iss"].(string)
+	return iss
+}
+
+// Has indicates whether the actor has the named claim with a truthy value.
+// A claim is truthy unless it is missing, false, zero, empty or null.
+func (a Actor) Has(claim string) bool {
+	switch v := a[claim].(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// HasValue indicates whether the named claim of the actor equals the value,
+// or if the claim is an array, whether it contains the value.
+// Non-string values are compared by their textual representation.
+func (a Actor) HasValue(claim string, value string) bool {
+	switch v := a[claim].(type) {
+	case nil:
+		return false
+	case []any:
+		for _, elem := range v {
+			if elem != nil && fmt.Sprintf("%v", elem) == value {
+				return true
+			}
+		}
+		return false
+	default:
+		return fmt.Sprintf("%v", v) == value
+	}
+}
+
+// parseActorToken decodes the claims of a JWT without validating its signature.
+func parseActorToken(token string) Actor {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var actor Actor
+	err = json.Unmarshal(payload, &actor)
+	if err != nil {
+		return nil
+	}
+	return actor
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frame
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestFrame_Actor(t *testing.T) {
+	t.Parallel()
+
+	f := Of(make(http.Header))
+	testarossa.Nil(t, f.Actor())
+	testarossa.Equal(t, "", f.ActorToken())
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"harry@hogwarts.edu","iss":"ingress","roles":["wizard","seeker"],"year":5,"prefect":false}`))
+	token := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." + payload + ".signature"
+	f.SetActorToken(token)
+	testarossa.Equal(t, token, f.ActorToken())
+	testarossa.False(t, f.ActorVerified())
+	testarossa.Nil(t, f.Actor()) // Not validated
+	f.SetActorVerified(true)
+	testarossa.True(t, f.ActorVerified())
+
+	actor := f.Actor()
+	testarossa.NotNil(t, actor)
+	testarossa.Equal(t, "harry@hogwarts.edu", actor.Subject())
+	testarossa.Equal(t, "ingress", actor.Issuer())
+	testarossa.True(t, actor.Has("roles"))
+	testarossa.True(t, actor.Has("year"))
+	testarossa.False(t, actor.Has("prefect"))
+	testarossa.False(t, actor.Has("house"))
+	testarossa.True(t, actor.HasValue("roles", "seeker"))
+	testarossa.False(t, actor.HasValue("roles", "prefect"))
+	testarossa.True(t, actor.HasValue("year", "5"))
+	testarossa.True(t, actor.HasValue("sub", "harry@hogwarts.edu"))
+	testarossa.False(t, actor.HasValue("house", "gryffindor"))
+
+	f.SetActorToken("malformed")
+	testarossa.Nil(t, f.Actor())
+	f.SetActorToken("")
+	testarossa.Equal(t, "", f.ActorToken())
+	f.SetActorVerified(false)
+	testarossa.False(t, f.ActorVerified())
+}
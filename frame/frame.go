@@ -26,19 +26,31 @@ import (
 )
 
 const (
-	HeaderPrefix        = "Microbus-"
-	HeaderBaggagePrefix = HeaderPrefix + "Baggage-"
-	HeaderMsgId         = HeaderPrefix + "Msg-Id"
-	HeaderFromHost      = HeaderPrefix + "From-Host"
-	HeaderFromId        = HeaderPrefix + "From-Id"
-	HeaderFromVersion   = HeaderPrefix + "From-Version"
-	HeaderTimeBudget    = HeaderPrefix + "Time-Budget"
-	HeaderCallDepth     = HeaderPrefix + "Call-Depth"
-	HeaderOpCode        = HeaderPrefix + "Op-Code"
-	HeaderQueue         = HeaderPrefix + "Queue"
-	HeaderFragment      = HeaderPrefix + "Fragment"
-	HeaderClockShift    = HeaderPrefix + "Clock-Shift"
-	HeaderLocality      = HeaderPrefix + "Locality"
+	HeaderPrefix            = "Microbus-"
+	HeaderBaggagePrefix     = HeaderPrefix + "Baggage-"
+	HeaderMsgId             = HeaderPrefix + "Msg-Id"
+	HeaderFromHost          = HeaderPrefix + "From-Host"
+	HeaderFromId            = HeaderPrefix + "From-Id"
+	HeaderFromVersion       = HeaderPrefix + "From-Version"
+	HeaderVersion           = HeaderPrefix + "Version"
+	HeaderTimeBudget        = HeaderPrefix + "Time-Budget"
+	HeaderCallDepth         = HeaderPrefix + "Call-Depth"
+	HeaderOpCode            = HeaderPrefix + "Op-Code"
+	HeaderQueue             = HeaderPrefix + "Queue"
+	HeaderFragment          = HeaderPrefix + "Fragment"
+	HeaderFragmentWindow    = HeaderPrefix + "Fragment-Window"
+	HeaderClockShift        = HeaderPrefix + "Clock-Shift"
+	HeaderLocality          = HeaderPrefix + "Locality"
+	HeaderIdempotency       = HeaderPrefix + "Idempotency-Key"
+	HeaderCompression       = HeaderPrefix + "Compression"
+	HeaderAcceptCompression = HeaderPrefix + "Accept-Compression"
+	HeaderAcceptCBOR        = HeaderPrefix + "Accept-CBOR"
+	HeaderActor             = HeaderPrefix + "Actor"
+	HeaderActorVerified     = HeaderPrefix + "Actor-Verified"
+	HeaderSignature         = HeaderPrefix + "Signature"
+	HeaderEncryption        = HeaderPrefix + "Encryption"
+	HeaderOnBehalfOf        = HeaderPrefix + "On-Behalf-Of"
+	HeaderPreflight         = HeaderPrefix + "Preflight"
 
 	OpCodeError    = "Err"
 	OpCodeAck      = "Ack"
@@ -218,6 +230,31 @@ func (f Frame) SetFromVersion(version int) {
 	}
 }
 
+// Version is the version number of the destination microservice that the request is pinned to.
+// A pinned request is routed only to the instances of the destination microservice with that version.
+// The pinned version is not propagated to downstream requests.
+func (f Frame) Version() int {
+	v := f.h.Get(HeaderVersion)
+	if v == "" {
+		return 0
+	}
+	ver, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return ver
+}
+
+// SetVersion pins the request to the instances of the destination microservice with the version number.
+// The pinned version is not propagated to downstream requests.
+func (f Frame) SetVersion(version int) {
+	if version == 0 {
+		f.h.Del(HeaderVersion)
+	} else {
+		f.h.Set(HeaderVersion, strconv.Itoa(version))
+	}
+}
+
 // MessageID is the unique ID given to each HTTP message and its response.
 func (f Frame) MessageID() string {
 	return f.h.Get(HeaderMsgId)
@@ -254,6 +291,30 @@ func (f Frame) SetCallDepth(depth int) {
 	}
 }
 
+// FragmentWindow is the number of fragments of a streamed request that the recipient allows the sender
+// to send ahead of their acknowledgement. A value of 0 indicates that fragments are not acknowledged.
+func (f Frame) FragmentWindow() int {
+	v := f.h.Get(HeaderFragmentWindow)
+	if v == "" {
+		return 0
+	}
+	window, err := strconv.Atoi(v)
+	if err != nil || window < 0 {
+		return 0
+	}
+	return window
+}
+
+// SetFragmentWindow sets the number of fragments of a streamed request that the recipient allows the sender
+// to send ahead of their acknowledgement.
+func (f Frame) SetFragmentWindow(window int) {
+	if window <= 0 {
+		f.h.Del(HeaderFragmentWindow)
+	} else {
+		f.h.Set(HeaderFragmentWindow, strconv.Itoa(window))
+	}
+}
+
 // TimeBudget is the duration budgeted for the request to complete.
 // A value of 0 indicates no time budget.
 func (f Frame) TimeBudget() time.Duration {
@@ -297,6 +358,7 @@ func (f Frame) SetQueue(queue string) {
 
 // Fragment returns the index of the fragment of large messages out of the total number of fragments.
 // Fragments are indexed starting at 1.
+// A max of 0 indicates a streamed message whose total number of fragments is not yet known.
 func (f Frame) Fragment() (index int, max int) {
 	v := f.h.Get(HeaderFragment)
 	if v == "" {
@@ -319,8 +381,9 @@ func (f Frame) Fragment() (index int, max int) {
 
 // Fragment sets the index of the fragment of large messages out of the total number of fragments.
 // Fragments are indexed starting at 1.
+// A max of 0 indicates a streamed message whose total number of fragments is not yet known.
 func (f Frame) SetFragment(index int, max int) {
-	if index < 1 || max < 1 || (index == 1 && max == 1) {
+	if index < 1 || max < 0 || (index == 1 && max == 1) {
 		f.h.Del(HeaderFragment)
 	} else {
 		f.h.Set(HeaderFragment, strconv.Itoa(index)+"/"+strconv.Itoa(max))
@@ -427,3 +490,147 @@ func (f Frame) SetLocality(locality string) {
 		f.h.Set(HeaderLocality, locality)
 	}
 }
+
+// IdempotencyKey is the key that identifies retries of the same request to an idempotent endpoint.
+// The idempotency key is not propagated to downstream requests.
+func (f Frame) IdempotencyKey() string {
+	return f.h.Get(HeaderIdempotency)
+}
+
+// SetIdempotencyKey sets the key that identifies retries of the same request to an idempotent endpoint.
+// The idempotency key is not propagated to downstream requests.
+func (f Frame) SetIdempotencyKey(key string) {
+	if key == "" {
+		f.h.Del(HeaderIdempotency)
+	} else {
+		f.h.Set(HeaderIdempotency, key)
+	}
+}
+
+// Compression is the algorithm with which the body of the message is compressed, either "zstd" or "br".
+// An empty value indicates that the body is not compressed.
+func (f Frame) Compression() string {
+	return f.h.Get(HeaderCompression)
+}
+
+// SetCompression sets the algorithm with which the body of the message is compressed, either "zstd" or "br".
+func (f Frame) SetCompression(compression string) {
+	if compression == "" {
+		f.h.Del(HeaderCompression)
+	} else {
+		f.h.Set(HeaderCompression, compression)
+	}
+}
+
+// AcceptCompression is the comma-separated list of compression algorithms that the sender of the message accepts.
+func (f Frame) AcceptCompression() string {
+	return f.h.Get(HeaderAcceptCompression)
+}
+
+// SetAcceptCompression sets the comma-separated list of compression algorithms that the sender of the message accepts.
+func (f Frame) SetAcceptCompression(compressions string) {
+	if compressions == "" {
+		f.h.Del(HeaderAcceptCompression)
+	} else {
+		f.h.Set(HeaderAcceptCompression, compressions)
+	}
+}
+
+// AcceptCBOR indicates that the sender of the message accepts CBOR-encoded request bodies.
+func (f Frame) AcceptCBOR() bool {
+	return f.h.Get(HeaderAcceptCBOR) == "1"
+}
+
+// SetAcceptCBOR sets whether the sender of the message accepts CBOR-encoded request bodies.
+func (f Frame) SetAcceptCBOR(accept bool) {
+	if !accept {
+		f.h.Del(HeaderAcceptCBOR)
+	} else {
+		f.h.Set(HeaderAcceptCBOR, "1")
+	}
+}
+
+// Actor returns the claims of the actor on behalf of which the request is made.
+// The claims are decoded from the actor token only if the connector validated its signature when the request arrived.
+// A nil map is returned if there is no actor token, if it is malformed, or if it was not validated.
+func (f Frame) Actor() Actor {
+	if !f.ActorVerified() {
+		return nil
+	}
+	return parseActorToken(f.h.Get(HeaderActor))
+}
+
+// ActorVerified indicates whether the connector validated the signature of the actor token when the request arrived.
+func (f Frame) ActorVerified() bool {
+	return f.h.Get(HeaderActorVerified) == "1"
+}
+
+// SetActorVerified sets whether the signature of the actor token was validated.
+// It is set by the connector when a request arrives and is not propagated to downstream requests.
+func (f Frame) SetActorVerified(verified bool) {
+	if !verified {
+		f.h.Del(HeaderActorVerified)
+	} else {
+		f.h.Set(HeaderActorVerified, "1")
+	}
+}
+
+// ActorToken is the signed JWT that carries the claims of the actor on behalf of which the request is made.
+// The actor token is propagated to downstream requests.
+func (f Frame) ActorToken() string {
+	return f.h.Get(HeaderActor)
+}
+
+// SetActorToken sets the signed JWT that carries the claims of the actor on behalf of which the request is made.
+// The actor token is propagated to downstream requests.
+func (f Frame) SetActorToken(token string) {
+	if token == "" {
+		f.h.Del(HeaderActor)
+	} else {
+		f.h.Set(HeaderActor, token)
+	}
+}
+
+// Encryption identifies the key with which the body of the message is encrypted.
+// An empty value indicates that the body is not encrypted.
+func (f Frame) Encryption() string {
+	return f.h.Get(HeaderEncryption)
+}
+
+// SetEncryption sets the identifier of the key with which the body of the message is encrypted.
+func (f Frame) SetEncryption(keyID string) {
+	if keyID == "" {
+		f.h.Del(HeaderEncryption)
+	} else {
+		f.h.Set(HeaderEncryption, keyID)
+	}
+}
+
+// OnBehalfOf is the hostname of the microservice on behalf of which the scheduler delivers a request.
+// It is honored by the recipient only if the request is from the scheduler.
+func (f Frame) OnBehalfOf() string {
+	return f.h.Get(HeaderOnBehalfOf)
+}
+
+// SetOnBehalfOf sets the hostname of the microservice on behalf of which the scheduler delivers a request.
+func (f Frame) SetOnBehalfOf(host string) {
+	if host == "" {
+		f.h.Del(HeaderOnBehalfOf)
+	} else {
+		f.h.Set(HeaderOnBehalfOf, host)
+	}
+}
+
+// Preflight indicates that the recipient should only verify that the request is allowed, without handling it.
+func (f Frame) Preflight() bool {
+	return f.h.Get(HeaderPreflight) == "1"
+}
+
+// SetPreflight sets whether the recipient should only verify that the request is allowed, without handling it.
+func (f Frame) SetPreflight(preflight bool) {
+	if !preflight {
+		f.h.Del(HeaderPreflight)
+	} else {
+		f.h.Set(HeaderPreflight, "1")
+	}
+}
@@ -73,6 +73,12 @@ func TestFrame_GetSet(t *testing.T) {
 	f.SetCallDepth(0)
 	testarossa.Zero(t, f.CallDepth())
 
+	testarossa.Zero(t, f.FragmentWindow())
+	f.SetFragmentWindow(4)
+	testarossa.Equal(t, 4, f.FragmentWindow())
+	f.SetFragmentWindow(0)
+	testarossa.Zero(t, f.FragmentWindow())
+
 	testarossa.Equal(t, "", f.FromHost())
 	f.SetFromHost("www.example.com")
 	testarossa.Equal(t, "www.example.com", f.FromHost())
@@ -91,6 +97,12 @@ func TestFrame_GetSet(t *testing.T) {
 	f.SetFromVersion(0)
 	testarossa.Zero(t, f.FromVersion())
 
+	testarossa.Zero(t, f.Version())
+	f.SetVersion(7)
+	testarossa.Equal(t, 7, f.Version())
+	f.SetVersion(0)
+	testarossa.Zero(t, f.Version())
+
 	testarossa.Equal(t, "", f.MessageID())
 	f.SetMessageID("1234567890")
 	testarossa.Equal(t, "1234567890", f.MessageID())
@@ -112,6 +124,48 @@ func TestFrame_GetSet(t *testing.T) {
 	f.SetQueue("")
 	testarossa.Equal(t, "", f.Queue())
 
+	testarossa.Equal(t, "", f.IdempotencyKey())
+	f.SetIdempotencyKey("1234567890")
+	testarossa.Equal(t, "1234567890", f.IdempotencyKey())
+	f.SetIdempotencyKey("")
+	testarossa.Equal(t, "", f.IdempotencyKey())
+
+	testarossa.Equal(t, "", f.Encryption())
+	f.SetEncryption("0a1b2c3d")
+	testarossa.Equal(t, "0a1b2c3d", f.Encryption())
+	f.SetEncryption("")
+	testarossa.Equal(t, "", f.Encryption())
+
+	testarossa.Equal(t, "", f.Compression())
+	f.SetCompression("zstd")
+	testarossa.Equal(t, "zstd", f.Compression())
+	f.SetCompression("")
+	testarossa.Equal(t, "", f.Compression())
+
+	testarossa.Equal(t, "", f.AcceptCompression())
+	f.SetAcceptCompression("zstd, br")
+	testarossa.Equal(t, "zstd, br", f.AcceptCompression())
+	f.SetAcceptCompression("")
+	testarossa.Equal(t, "", f.AcceptCompression())
+
+	testarossa.False(t, f.AcceptCBOR())
+	f.SetAcceptCBOR(true)
+	testarossa.True(t, f.AcceptCBOR())
+	f.SetAcceptCBOR(false)
+	testarossa.False(t, f.AcceptCBOR())
+
+	testarossa.Equal(t, "", f.OnBehalfOf())
+	f.SetOnBehalfOf("www.example.com")
+	testarossa.Equal(t, "www.example.com", f.OnBehalfOf())
+	f.SetOnBehalfOf("")
+	testarossa.Equal(t, "", f.OnBehalfOf())
+
+	testarossa.False(t, f.Preflight())
+	f.SetPreflight(true)
+	testarossa.True(t, f.Preflight())
+	f.SetPreflight(false)
+	testarossa.False(t, f.Preflight())
+
 	fi, fm := f.Fragment()
 	testarossa.Equal(t, 1, fi)
 	testarossa.Equal(t, 1, fm)
@@ -119,6 +173,10 @@ func TestFrame_GetSet(t *testing.T) {
 	fi, fm = f.Fragment()
 	testarossa.Equal(t, fi, 2)
 	testarossa.Equal(t, fm, 5)
+	f.SetFragment(3, 0)
+	fi, fm = f.Fragment()
+	testarossa.Equal(t, fi, 3)
+	testarossa.Equal(t, fm, 0)
 	f.SetFragment(0, 0)
 	fi, fm = f.Fragment()
 	testarossa.Equal(t, fi, 1)
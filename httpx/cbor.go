@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/microbus-io/fabric/errors"
+)
+
+var (
+	cborEncMode, _ = cbor.EncOptions{
+		Time: cbor.TimeRFC3339Nano,
+	}.EncMode()
+	cborDecMode, _ = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+	}.DecMode()
+)
+
+// MarshalCBOR encodes the data as CBOR.
+// Use json tags to designate the name of the argument to map to each field.
+func MarshalCBOR(data any) ([]byte, error) {
+	b, err := cborEncMode.Marshal(data)
+	return b, errors.Trace(err)
+}
+
+// UnmarshalCBOR decodes CBOR into the data object.
+// Use json tags to designate the name of the argument to map to each field.
+func UnmarshalCBOR(b []byte, data any) error {
+	err := cborDecMode.Unmarshal(b, data)
+	return errors.Trace(err)
+}
+
+// AcceptsCBOR indicates if the Accept header of the request explicitly includes the application/cbor content type.
+func AcceptsCBOR(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, contentType := range strings.Split(accept, ",") {
+			contentType, _, _ = strings.Cut(contentType, ";")
+			if strings.TrimSpace(contentType) == "application/cbor" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestHttpx_CBOR(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		S string `json:"s,omitempty"`
+	}
+	type Data struct {
+		I       int            `json:"i"`
+		F       float64        `json:"f"`
+		T       time.Time      `json:"t"`
+		Inner   *Inner         `json:"inner"`
+		M       map[string]any `json:"m"`
+		Ignored string         `json:"-"`
+	}
+	in := Data{
+		I:       1,
+		F:       1.5,
+		T:       time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Inner:   &Inner{S: "inner"},
+		M:       map[string]any{"k": "v"},
+		Ignored: "ignored",
+	}
+	b, err := MarshalCBOR(in)
+	testarossa.NoError(t, err)
+	j, _ := json.Marshal(in)
+	testarossa.True(t, len(b) < len(j))
+
+	var out Data
+	err = UnmarshalCBOR(b, &out)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, in.I, out.I)
+	testarossa.Equal(t, in.F, out.F)
+	testarossa.True(t, in.T.Equal(out.T))
+	testarossa.Equal(t, "inner", out.Inner.S)
+	testarossa.Equal(t, "v", out.M["k"])
+	testarossa.Equal(t, "", out.Ignored)
+
+	// Field names are taken from the json tags
+	var generic map[string]any
+	err = UnmarshalCBOR(b, &generic)
+	testarossa.NoError(t, err)
+	testarossa.NotNil(t, generic["inner"])
+	testarossa.Nil(t, generic["Ignored"])
+}
+
+func TestHttpx_AcceptsCBOR(t *testing.T) {
+	t.Parallel()
+
+	r, _ := http.NewRequest("GET", "/path", nil)
+	testarossa.False(t, AcceptsCBOR(r))
+	r.Header.Set("Accept", "*/*")
+	testarossa.False(t, AcceptsCBOR(r))
+	r.Header.Set("Accept", "application/json")
+	testarossa.False(t, AcceptsCBOR(r))
+	r.Header.Set("Accept", "application/cbor")
+	testarossa.True(t, AcceptsCBOR(r))
+	r.Header.Set("Accept", "application/json;q=0.5, application/cbor;q=1")
+	testarossa.True(t, AcceptsCBOR(r))
+}
+
+func TestHttpx_ParseResponseBody(t *testing.T) {
+	t.Parallel()
+
+	var data struct {
+		X int `json:"x"`
+	}
+
+	// JSON
+	rec := NewResponseRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.Write([]byte(`{"x":1}`))
+	err := ParseResponseBody(rec.Result(), &data)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 1, data.X)
+
+	// CBOR
+	b, _ := MarshalCBOR(map[string]int{"x": 2})
+	res := &http.Response{
+		Header: http.Header{"Content-Type": []string{"application/cbor"}},
+		Body:   io.NopCloser(bytes.NewReader(b)),
+	}
+	err = ParseResponseBody(res, &data)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 2, data.X)
+
+	// Malformed
+	res = &http.Response{
+		Header: http.Header{"Content-Type": []string{"application/cbor"}},
+		Body:   io.NopCloser(bytes.NewReader([]byte(`{"x":3}`))),
+	}
+	err = ParseResponseBody(res, &data)
+	testarossa.Error(t, err)
+}
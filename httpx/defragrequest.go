@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -28,12 +29,20 @@ import (
 	"github.com/microbus-io/fabric/utils"
 )
 
-// DefragRequest merges together multiple fragments back into a single HTTP request
+// DefragRequest merges together multiple fragments back into a single HTTP request.
+// Fragments of a streamed request, whose total number of fragments is not known in advance,
+// are delivered in order to the body of the integrated request as they arrive.
 type DefragRequest struct {
 	fragments    utils.SyncMap[int, *http.Request]
 	maxIndex     atomic.Int32
 	count        atomic.Int32
 	lastActivity atomic.Int64
+
+	streaming  atomic.Bool
+	streamMux  sync.Mutex
+	stream     *StreamReader
+	streamHead *http.Request
+	headDone   bool
 }
 
 // NewDefragRequest creates a new request integrator.
@@ -48,8 +57,37 @@ func (st *DefragRequest) LastActivity() time.Duration {
 	return time.Duration(time.Now().UnixMilli()-st.lastActivity.Load()) * time.Millisecond
 }
 
+// Streaming indicates if the fragments are of a streamed request.
+func (st *DefragRequest) Streaming() bool {
+	return st.streaming.Load()
+}
+
+// Done indicates if a streamed request has ended, either because all its fragments were delivered
+// or because its body was closed by the reader.
+func (st *DefragRequest) Done() bool {
+	st.streamMux.Lock()
+	defer st.streamMux.Unlock()
+	return st.stream != nil && st.stream.Done()
+}
+
 // Integrated indicates if all the fragments have been collected and if so returns them as a single HTTP request.
+// A streamed request is returned once, as soon as its first fragment arrives.
+// Its body is then fed by subsequent fragments.
 func (st *DefragRequest) Integrated() (integrated *http.Request, err error) {
+	if st.streaming.Load() {
+		st.streamMux.Lock()
+		defer st.streamMux.Unlock()
+		if st.streamHead == nil || st.headDone {
+			return nil, nil
+		}
+		st.headDone = true
+		integrated = st.streamHead
+		frame.Of(integrated).SetFragment(1, 1) // Clear the header
+		integrated.Header.Del("Content-Length")
+		integrated.ContentLength = -1
+		integrated.Body = st.stream
+		return integrated, nil
+	}
 	maxIndex := st.maxIndex.Load()
 	if maxIndex == 1 {
 		onlyFrag, _ := st.fragments.Load(1)
@@ -96,9 +134,46 @@ func (st *DefragRequest) Integrated() (integrated *http.Request, err error) {
 // Add a fragment to be integrated.
 func (st *DefragRequest) Add(r *http.Request) error {
 	index, max := frame.Of(r).Fragment()
+	if max == 0 || st.streaming.Load() {
+		return st.addStreamed(r, index, max)
+	}
 	st.maxIndex.Store(int32(max))
 	st.fragments.Store(index, r)
 	st.count.Add(1)
 	st.lastActivity.Store(time.Now().UnixMilli())
 	return nil
 }
+
+// addStreamed adds a fragment of a streamed request.
+// Fragments may arrive out of order but are written to the stream in order.
+// Writing a fragment other than the first blocks until the reader consumes the preceding fragments,
+// thereby holding back the acknowledgement of the fragment and in turn the sender.
+// The final fragment is indicated by a known max.
+func (st *DefragRequest) addStreamed(r *http.Request, index int, max int) error {
+	st.streamMux.Lock()
+	if st.stream == nil {
+		st.stream = NewBoundedStreamReader(1)
+		st.streaming.Store(true)
+	}
+	st.lastActivity.Store(time.Now().UnixMilli())
+	if index == 1 {
+		st.streamHead = r
+	}
+	stream := st.stream
+	st.streamMux.Unlock()
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// Content is discarded if the reader closed the stream
+	if stream.WriteFragment(index, body) == nil && max > 0 {
+		stream.CloseWithError(nil)
+	}
+	st.lastActivity.Store(time.Now().UnixMilli())
+	return nil
+}
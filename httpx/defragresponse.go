@@ -17,9 +17,11 @@ limitations under the License.
 package httpx
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -28,12 +30,22 @@ import (
 	"github.com/microbus-io/fabric/utils"
 )
 
-// DefragResponse merges together multiple fragments back into a single HTTP response
+// DefragResponse merges together multiple fragments back into a single HTTP response.
+// Fragments of a streamed response, whose total number of fragments is not known in advance,
+// are delivered in order to the body of the integrated response as they arrive.
 type DefragResponse struct {
 	fragments    utils.SyncMap[int, *http.Response]
 	maxIndex     atomic.Int32
 	count        atomic.Int32
 	lastActivity atomic.Int64
+
+	streaming  atomic.Bool
+	streamMux  sync.Mutex
+	stream     *StreamReader
+	streamNext int
+	streamLast int
+	streamHead *http.Response
+	headDone   bool
 }
 
 // NewDefragResponse creates a new response integrator.
@@ -48,8 +60,37 @@ func (st *DefragResponse) LastActivity() time.Duration {
 	return time.Duration(time.Now().UnixMilli()-st.lastActivity.Load()) * time.Millisecond
 }
 
+// Streaming indicates if the fragments are of a streamed response.
+func (st *DefragResponse) Streaming() bool {
+	return st.streaming.Load()
+}
+
+// Done indicates if a streamed response has ended, either because all its fragments were delivered
+// or because its body was closed by the reader.
+func (st *DefragResponse) Done() bool {
+	st.streamMux.Lock()
+	defer st.streamMux.Unlock()
+	return st.stream != nil && st.stream.Done()
+}
+
 // Integrated indicates if all the fragments have been collected and if so returns them as a single HTTP response.
+// A streamed response is returned once, as soon as its first fragment arrives.
+// Its body is then fed by subsequent fragments.
 func (st *DefragResponse) Integrated() (integrated *http.Response, err error) {
+	if st.streaming.Load() {
+		st.streamMux.Lock()
+		defer st.streamMux.Unlock()
+		if st.streamHead == nil || st.headDone {
+			return nil, nil
+		}
+		st.headDone = true
+		integrated = st.streamHead
+		frame.Of(integrated).SetFragment(1, 1) // Clear the header
+		integrated.Header.Del("Content-Length")
+		integrated.ContentLength = -1
+		integrated.Body = st.stream
+		return integrated, nil
+	}
 	maxIndex := st.maxIndex.Load()
 	if maxIndex == 1 {
 		onlyFrag, _ := st.fragments.Load(1)
@@ -96,9 +137,62 @@ func (st *DefragResponse) Integrated() (integrated *http.Response, err error) {
 // Add a fragment to be integrated.
 func (st *DefragResponse) Add(r *http.Response) error {
 	index, max := frame.Of(r).Fragment()
+	if max == 0 || st.streaming.Load() {
+		return st.addStreamed(r, index, max)
+	}
 	st.maxIndex.Store(int32(max))
 	st.fragments.Store(index, r)
 	st.count.Add(1)
 	st.lastActivity.Store(time.Now().UnixMilli())
 	return nil
 }
+
+// addStreamed adds a fragment of a streamed response.
+// Fragments are written to the stream in order. The final fragment is indicated by a known max.
+func (st *DefragResponse) addStreamed(r *http.Response, index int, max int) error {
+	st.streamMux.Lock()
+	defer st.streamMux.Unlock()
+	if st.stream == nil {
+		st.stream = NewStreamReader()
+		st.streamNext = 1
+		st.streaming.Store(true)
+	}
+	st.lastActivity.Store(time.Now().UnixMilli())
+	if max > 0 {
+		st.streamLast = max
+	}
+	st.fragments.Store(index, r)
+	for {
+		fragment, ok := st.fragments.Load(st.streamNext)
+		if !ok {
+			break
+		}
+		st.fragments.Delete(st.streamNext)
+		if st.streamNext == 1 {
+			st.streamHead = fragment
+		}
+		if st.streamNext == st.streamLast && frame.Of(fragment).OpCode() == frame.OpCodeError {
+			// The stream was terminated by an error
+			var reconstitutedError *errors.TracedError
+			if fragment.Body != nil {
+				body, err := io.ReadAll(fragment.Body)
+				if err == nil {
+					json.Unmarshal(body, &reconstitutedError)
+				}
+			}
+			if reconstitutedError == nil {
+				st.stream.CloseWithError(errors.New("unparsable error response"))
+			} else {
+				st.stream.CloseWithError(errors.Convert(reconstitutedError))
+			}
+		} else if fragment.Body != nil {
+			// Content is discarded if the reader closed the stream
+			io.Copy(st.stream, fragment.Body)
+		}
+		st.streamNext++
+	}
+	if st.streamLast > 0 && st.streamNext > st.streamLast {
+		st.stream.CloseWithError(nil)
+	}
+	return nil
+}
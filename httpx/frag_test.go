@@ -23,7 +23,9 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/microbus-io/fabric/frame"
 	"github.com/microbus-io/fabric/rand"
 	"github.com/microbus-io/testarossa"
 )
@@ -257,3 +259,147 @@ func TestHttpx_DefragResponseNoContentLen(t *testing.T) {
 	testarossa.NoError(t, err)
 	testarossa.SliceEqual(t, body, intBody)
 }
+
+func TestHttpx_DefragResponseStreamed(t *testing.T) {
+	t.Parallel()
+
+	fragment := func(index int, max int, body string) *http.Response {
+		rec := NewResponseRecorder()
+		rec.Header().Set("Content-Type", "text/plain")
+		rec.Write([]byte(body))
+		res := rec.Result()
+		frame.Of(res).SetFragment(index, max)
+		return res
+	}
+
+	defrag := NewDefragResponse()
+
+	// The response is integrated as soon as the first fragment arrives
+	err := defrag.Add(fragment(1, 0, "Hello"))
+	testarossa.NoError(t, err)
+	testarossa.True(t, defrag.Streaming())
+	intRes, err := defrag.Integrated()
+	testarossa.NoError(t, err)
+	testarossa.NotNil(t, intRes)
+	testarossa.Equal(t, -1, int(intRes.ContentLength))
+	testarossa.Equal(t, "", intRes.Header.Get("Content-Length"))
+	testarossa.Equal(t, "text/plain", intRes.Header.Get("Content-Type"))
+	fi, fm := frame.Of(intRes).Fragment()
+	testarossa.Equal(t, 1, fi)
+	testarossa.Equal(t, 1, fm)
+
+	// It is returned only once
+	again, err := defrag.Integrated()
+	testarossa.NoError(t, err)
+	testarossa.Nil(t, again)
+
+	// Subsequent fragments feed the body
+	err = defrag.Add(fragment(2, 0, " World"))
+	testarossa.NoError(t, err)
+	testarossa.False(t, defrag.Done())
+	err = defrag.Add(fragment(3, 3, "!"))
+	testarossa.NoError(t, err)
+	testarossa.True(t, defrag.Done())
+	intBody, err := io.ReadAll(intRes.Body)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "Hello World!", string(intBody))
+}
+
+func TestHttpx_DefragResponseStreamedError(t *testing.T) {
+	t.Parallel()
+
+	defrag := NewDefragResponse()
+
+	rec := NewResponseRecorder()
+	rec.Write([]byte("partial"))
+	res := rec.Result()
+	frame.Of(res).SetFragment(1, 0)
+	defrag.Add(res)
+
+	rec = NewResponseRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusInternalServerError)
+	rec.Write([]byte(`{"error":"oops","statusCode":500}`))
+	res = rec.Result()
+	frame.Of(res).SetFragment(2, 2)
+	frame.Of(res).SetOpCode(frame.OpCodeError)
+	defrag.Add(res)
+
+	intRes, err := defrag.Integrated()
+	testarossa.NoError(t, err)
+	intBody, err := io.ReadAll(intRes.Body)
+	testarossa.ErrorContains(t, err, "oops")
+	testarossa.Equal(t, "partial", string(intBody))
+}
+
+func TestHttpx_FragRequestStreamed(t *testing.T) {
+	t.Parallel()
+
+	bodySize := 3*1024 + 16
+	body := []byte(rand.AlphaNum64(bodySize))
+
+	// The body is read lazily
+	r, err := http.NewRequest("PUT", "https://example.com", io.NopCloser(bytes.NewReader(body)))
+	testarossa.NoError(t, err)
+	frag, err := NewStreamedFragRequest(r, 1024)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 0, frag.N())
+	var fragments []*http.Request
+	for i := 1; frag.N() == 0 || i <= frag.N(); i++ {
+		f, err := frag.Fragment(i)
+		testarossa.NoError(t, err)
+		fragments = append(fragments, f)
+	}
+	testarossa.Equal(t, 4, frag.N())
+	for i, f := range fragments {
+		fi, fm := frame.Of(f).Fragment()
+		testarossa.Equal(t, i+1, fi)
+		if i < 3 {
+			testarossa.Equal(t, 0, fm)
+		} else {
+			testarossa.Equal(t, 4, fm)
+		}
+	}
+
+	// Fragments must be obtained in order
+	_, err = frag.Fragment(2)
+	testarossa.Error(t, err)
+
+	// Defrag out of order
+	defrag := NewDefragRequest()
+	err = defrag.Add(fragments[0])
+	testarossa.NoError(t, err)
+	testarossa.True(t, defrag.Streaming())
+	intReq, err := defrag.Integrated()
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, -1, int(intReq.ContentLength))
+	added := make(chan error, 3)
+	for _, i := range []int{2, 1, 3} {
+		go func() {
+			// Adding blocks until the preceding fragments are consumed
+			added <- defrag.Add(fragments[i])
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	testarossa.Zero(t, len(added))
+	intBody, err := io.ReadAll(intReq.Body)
+	testarossa.NoError(t, err)
+	testarossa.SliceEqual(t, body, intBody)
+	for range 3 {
+		testarossa.NoError(t, <-added)
+	}
+	testarossa.True(t, defrag.Done())
+
+	// A small body is not fragmented
+	r, err = http.NewRequest("PUT", "https://example.com", io.NopCloser(bytes.NewReader(body[:1000])))
+	testarossa.NoError(t, err)
+	frag, err = NewStreamedFragRequest(r, 1024)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 1, frag.N())
+	f, err := frag.Fragment(1)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "1000", f.Header.Get("Content-Length"))
+	fi, fm := frame.Of(f).Fragment()
+	testarossa.Equal(t, 1, fi)
+	testarossa.Equal(t, 1, fm)
+}
@@ -32,6 +32,12 @@ type FragRequest struct {
 	origRequest   *http.Request
 	bodyFragments [][]byte
 	noFrags       bool
+
+	streamed     bool
+	fragmentSize int64
+	index        int
+	next         []byte
+	readErr      error
 }
 
 // NewFragRequest creates a new request fragmentor
@@ -75,11 +81,79 @@ func NewFragRequest(r *http.Request, fragmentSize int64) (*FragRequest, error) {
 	return result, nil
 }
 
-// N is the number of fragments
+// NewStreamedFragRequest creates a new request fragmentor that reads the body lazily, one fragment at a time.
+// Fragments of a streamed request must be obtained in order. The total number of fragments is
+// not known until the body is read to its end, so fragments are marked as 1/0, 2/0, etc. except
+// for the final fragment which is marked n/n.
+func NewStreamedFragRequest(r *http.Request, fragmentSize int64) (*FragRequest, error) {
+	if r.Body == nil {
+		return &FragRequest{origRequest: r, noFrags: true}, nil
+	}
+	if _, ok := (r.Body).(*BodyReader); ok {
+		// Already in memory
+		return NewFragRequest(r, fragmentSize)
+	}
+
+	result := &FragRequest{
+		origRequest:  r,
+		streamed:     true,
+		fragmentSize: fragmentSize,
+	}
+	first, err := result.readNext()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.next, err = result.readNext()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.next == nil {
+		// The body fits in a single fragment
+		r.Body = NewBodyReader(first)
+		r.ContentLength = int64(len(first))
+		r.Header.Set("Content-Length", strconv.Itoa(len(first)))
+		return &FragRequest{origRequest: r, noFrags: true}, nil
+	}
+	result.bodyFragments = [][]byte{first}
+	return result, nil
+}
+
+// readNext reads the next fragment of the body of a streamed request.
+// It returns nil if the body was read to its end.
+func (fr *FragRequest) readNext() ([]byte, error) {
+	if fr.readErr != nil {
+		return nil, nil
+	}
+	buf := make([]byte, fr.fragmentSize)
+	n, err := io.ReadFull(fr.origRequest.Body, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		fr.readErr = io.EOF
+		fr.origRequest.Body.Close()
+		err = nil
+		if n == 0 {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		fr.readErr = err
+		fr.origRequest.Body.Close()
+		return nil, errors.Trace(err)
+	}
+	return buf[:n], nil
+}
+
+// N is the number of fragments.
+// The number of fragments of a streamed request is 0 until its final fragment is obtained.
 func (fr *FragRequest) N() int {
 	if fr.noFrags {
 		return 1
 	}
+	if fr.streamed {
+		if fr.next == nil && fr.readErr == io.EOF {
+			return fr.index
+		}
+		return 0
+	}
 	return len(fr.bodyFragments)
 }
 
@@ -91,6 +165,9 @@ func (fr *FragRequest) Fragment(index int) (f *http.Request, err error) {
 		}
 		return nil, errors.New("index out of bounds")
 	}
+	if fr.streamed {
+		return fr.streamedFragment(index)
+	}
 
 	if index < 1 || index > len(fr.bodyFragments) {
 		return nil, errors.New("index out of bounds")
@@ -111,3 +188,41 @@ func (fr *FragRequest) Fragment(index int) (f *http.Request, err error) {
 
 	return fragment, nil
 }
+
+// streamedFragment returns the next fragment of a streamed request.
+func (fr *FragRequest) streamedFragment(index int) (f *http.Request, err error) {
+	if index != fr.index+1 {
+		return nil, errors.New("fragments of a streamed request must be obtained in order")
+	}
+	var body []byte
+	if index == 1 {
+		body = fr.bodyFragments[0]
+		fr.bodyFragments = nil
+	} else {
+		if fr.next == nil {
+			return nil, errors.New("index out of bounds")
+		}
+		body = fr.next
+		fr.next, err = fr.readNext()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	fr.index = index
+
+	// Prepare the HTTP request
+	fragment, err := http.NewRequest(fr.origRequest.Method, fr.origRequest.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, vv := range fr.origRequest.Header {
+		fragment.Header[k] = vv
+	}
+	fragment.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	if fr.next == nil && fr.readErr == io.EOF {
+		frame.Of(fragment).SetFragment(index, index)
+	} else {
+		frame.Of(fragment).SetFragment(index, 0)
+	}
+	return fragment, nil
+}
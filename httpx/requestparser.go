@@ -18,13 +18,14 @@ package httpx
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/microbus-io/fabric/errors"
 )
 
 // ParseRequestBody parses the body of an incoming request and populates the fields of a data object.
-// It supports JSON and URL-encoded form data content types.
+// It supports JSON, CBOR and URL-encoded form data content types.
 // Use json tags to designate the name of the argument to map to each field.
 func ParseRequestBody(r *http.Request, data any) error {
 	// Parse JSON in the body
@@ -35,6 +36,17 @@ func ParseRequestBody(r *http.Request, data any) error {
 			return errors.Trace(err)
 		}
 	}
+	// Parse CBOR in the body
+	if contentType == "application/cbor" {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = UnmarshalCBOR(b, data)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// Parse form in body
 	if contentType == "application/x-www-form-urlencoded" {
 		err := r.ParseForm()
@@ -75,3 +75,30 @@ func TestHttpx_RequestParserOverrideFormData(t *testing.T) {
 	testarossa.Equal(t, 2, data.X)
 	testarossa.Equal(t, 1, data.Y)
 }
+
+func TestHttpx_RequestParserOverrideCBOR(t *testing.T) {
+	t.Parallel()
+
+	var data struct {
+		X int
+		Y int
+	}
+	body, err := MarshalCBOR(map[string]int{"x": 1, "y": 1})
+	testarossa.NoError(t, err)
+
+	r, err := http.NewRequest("POST", `/path`, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/cbor")
+	testarossa.NoError(t, err)
+	err = ParseRequestData(r, &data)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 1, data.X)
+	testarossa.Equal(t, 1, data.Y)
+
+	r, err = http.NewRequest("POST", `/path?x=2`, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/cbor")
+	testarossa.NoError(t, err)
+	err = ParseRequestData(r, &data)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 2, data.X)
+	testarossa.Equal(t, 1, data.Y)
+}
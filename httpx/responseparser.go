@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/microbus-io/fabric/errors"
+)
+
+// ParseResponseBody parses the body of a response and populates the fields of a data object.
+// It supports CBOR content type, falling back to JSON for any other content type.
+// Use json tags to designate the name of the argument to map to each field.
+func ParseResponseBody(r *http.Response, data any) error {
+	if r.Header.Get("Content-Type") == "application/cbor" {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return UnmarshalCBOR(b, data)
+	}
+	err := json.NewDecoder(r.Body).Decode(data)
+	return errors.Trace(err)
+}
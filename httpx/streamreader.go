@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"io"
+	"sync"
+)
+
+// Ensure interfaces
+var (
+	_ = io.ReadCloser(&StreamReader{})
+	_ = io.Writer(&StreamReader{})
+)
+
+// StreamReader is a closer+reader whose content is written to it incrementally,
+// typically as the fragments of a streamed message arrive.
+// Writing to an unbounded stream never blocks. Writing to a bounded stream blocks while the pending content
+// exceeds the bound, until the reader catches up or the stream is ended.
+// Fragments that are written concurrently and possibly out of order are appended in order of their index.
+// Reading from the stream blocks until content is available or until the stream is ended.
+type StreamReader struct {
+	mux         sync.Mutex
+	cond        *sync.Cond
+	chunks      [][]byte
+	buffered    int
+	maxBuffered int
+	nextIndex   int
+	err         error
+}
+
+// NewStreamReader creates a new unbounded stream reader.
+func NewStreamReader() *StreamReader {
+	return NewBoundedStreamReader(0)
+}
+
+// NewBoundedStreamReader creates a new stream reader whose writers block while the content pending to be read
+// is over the indicated number of bytes. A write is always allowed into an empty stream, regardless of its size.
+func NewBoundedStreamReader(maxBuffered int) *StreamReader {
+	sr := &StreamReader{
+		maxBuffered: maxBuffered,
+		nextIndex:   1,
+	}
+	sr.cond = sync.NewCond(&sr.mux)
+	return sr
+}
+
+// Write appends a copy of the bytes to the stream.
+// It implements the io.Writer interface.
+func (sr *StreamReader) Write(b []byte) (n int, err error) {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	return sr.write(b)
+}
+
+// WriteFragment appends a copy of the bytes of the fragment to the stream,
+// blocking until all fragments of a lower index are appended.
+// Fragment indexes start at 1. Streams written to with WriteFragment should not also be written to with Write.
+func (sr *StreamReader) WriteFragment(index int, b []byte) error {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	for sr.err == nil && index > sr.nextIndex {
+		sr.cond.Wait()
+	}
+	if sr.err != nil {
+		return io.ErrClosedPipe
+	}
+	if index < sr.nextIndex {
+		return nil // Duplicate
+	}
+	_, err := sr.write(b)
+	if err != nil {
+		return err
+	}
+	sr.nextIndex++
+	sr.cond.Broadcast()
+	return nil
+}
+
+// write appends a copy of the bytes to the stream.
+// The lock must be held by the caller.
+func (sr *StreamReader) write(b []byte) (n int, err error) {
+	if sr.err != nil {
+		return 0, io.ErrClosedPipe
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	for sr.err == nil && sr.maxBuffered > 0 && sr.buffered > 0 && sr.buffered+len(b) > sr.maxBuffered {
+		sr.cond.Wait()
+	}
+	if sr.err != nil {
+		return 0, io.ErrClosedPipe
+	}
+	chunk := make([]byte, len(b))
+	copy(chunk, b)
+	sr.chunks = append(sr.chunks, chunk)
+	sr.buffered += len(chunk)
+	sr.cond.Broadcast()
+	return len(b), nil
+}
+
+// CloseWithError ends the stream.
+// Readers receive the error after consuming all pending content, or io.EOF if the error is nil.
+// Subsequent calls have no effect.
+func (sr *StreamReader) CloseWithError(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	sr.mux.Lock()
+	if sr.err == nil {
+		sr.err = err
+		sr.cond.Broadcast()
+	}
+	sr.mux.Unlock()
+}
+
+// Read reads the next available content, blocking until content is available or until the stream ends.
+// It implements the io.Reader interface.
+func (sr *StreamReader) Read(p []byte) (n int, err error) {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	for len(sr.chunks) == 0 && sr.err == nil {
+		sr.cond.Wait()
+	}
+	if len(sr.chunks) == 0 {
+		return 0, sr.err
+	}
+	n = copy(p, sr.chunks[0])
+	if n < len(sr.chunks[0]) {
+		sr.chunks[0] = sr.chunks[0][n:]
+	} else {
+		sr.chunks[0] = nil
+		sr.chunks = sr.chunks[1:]
+	}
+	sr.buffered -= n
+	sr.cond.Broadcast() // Release blocked writers
+	return n, nil
+}
+
+// Close discards any pending content and ends the stream.
+// It implements the io.Closer interface.
+func (sr *StreamReader) Close() error {
+	sr.mux.Lock()
+	sr.chunks = nil
+	sr.buffered = 0
+	if sr.err == nil {
+		sr.err = io.ErrClosedPipe
+	}
+	sr.cond.Broadcast()
+	sr.mux.Unlock()
+	return nil
+}
+
+// Done indicates if the stream was ended by the writer or closed by the reader.
+func (sr *StreamReader) Done() bool {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	return sr.err != nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestHttpx_StreamReader(t *testing.T) {
+	t.Parallel()
+
+	sr := NewStreamReader()
+	sr.Write([]byte("Hello"))
+	sr.Write([]byte(" World"))
+
+	// Reads return pending content
+	buf := make([]byte, 3)
+	n, err := sr.Read(buf)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "Hel", string(buf[:n]))
+	n, err = sr.Read(buf)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "lo", string(buf[:n]))
+
+	// Reads block until content arrives
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		sr.Write([]byte("!"))
+		sr.CloseWithError(nil)
+	}()
+	b, err := io.ReadAll(sr)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, " World!", string(b))
+	testarossa.True(t, sr.Done())
+
+	// Writing after the end fails
+	_, err = sr.Write([]byte("late"))
+	testarossa.Error(t, err)
+}
+
+func TestHttpx_StreamReaderError(t *testing.T) {
+	t.Parallel()
+
+	sr := NewStreamReader()
+	sr.Write([]byte("partial"))
+	sr.CloseWithError(errors.New("oops"))
+	b, err := io.ReadAll(sr)
+	testarossa.ErrorContains(t, err, "oops")
+	testarossa.Equal(t, "partial", string(b))
+
+	// Closing by the reader discards pending content
+	sr = NewStreamReader()
+	sr.Write([]byte("discarded"))
+	sr.Close()
+	testarossa.True(t, sr.Done())
+	n, err := sr.Read(make([]byte, 16))
+	testarossa.Zero(t, n)
+	testarossa.Equal(t, io.ErrClosedPipe, err)
+}
+
+func TestHttpx_BoundedStreamReader(t *testing.T) {
+	t.Parallel()
+
+	sr := NewBoundedStreamReader(8)
+
+	// A write larger than the bound is allowed into an empty stream
+	n, err := sr.Write([]byte("0123456789"))
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 10, n)
+
+	// Subsequent writes block until the reader catches up
+	written := make(chan bool)
+	go func() {
+		sr.Write([]byte("abc"))
+		close(written)
+	}()
+	select {
+	case <-written:
+		testarossa.FailIf(t, true, "write did not block")
+	case <-time.After(100 * time.Millisecond):
+	}
+	buf := make([]byte, 10)
+	n, err = sr.Read(buf)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "0123456789", string(buf[:n]))
+	<-written
+	n, err = sr.Read(buf)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "abc", string(buf[:n]))
+
+	// Closing by the reader releases blocked writers
+	sr.Write([]byte("01234567"))
+	released := make(chan error)
+	go func() {
+		_, err := sr.Write([]byte("more"))
+		released <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	sr.Close()
+	testarossa.Equal(t, io.ErrClosedPipe, <-released)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/microbus-io/fabric/coreservices/httpingress"
 	"github.com/microbus-io/fabric/coreservices/metrics"
 	"github.com/microbus-io/fabric/coreservices/openapiportal"
+	"github.com/microbus-io/fabric/coreservices/scheduler"
 	"github.com/microbus-io/fabric/examples/browser"
 	"github.com/microbus-io/fabric/examples/calculator"
 	"github.com/microbus-io/fabric/examples/directory"
@@ -46,6 +47,7 @@ func main() {
 		httpegress.NewService(),
 		openapiportal.NewService(),
 		metrics.NewService(),
+		scheduler.NewService(),
 	)
 	app.Add(
 		// Add solution microservices here
@@ -18,12 +18,13 @@ package openapi
 
 // Endpoint describes a single endpoint of a microservice, such as an RPC function.
 type Endpoint struct {
-	Type        string
-	Name        string
-	Path        string
-	Summary     string
-	Description string
-	InputArgs   interface{}
-	OutputArgs  interface{}
-	Method      string
+	Type           string
+	Name           string
+	Path           string
+	Summary        string
+	Description    string
+	InputArgs      interface{}
+	OutputArgs     interface{}
+	Method         string
+	AllowedCallers []string
 }
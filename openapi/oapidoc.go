@@ -51,6 +51,9 @@ type oapiOperation struct {
 	Parameters  []*oapiParameter         `json:"parameters,omitempty"`
 	RequestBody *oapiRequestBody         `json:"requestBody,omitempty"`
 	Responses   map[string]*oapiResponse `json:"responses,omitempty"`
+
+	// Extensions
+	AllowedCallers []string `json:"x-microbus-allowed-callers,omitempty"`
 }
 
 // oapiComponents holds a set of reusable objects for different aspects of the OpenAPI schema.
@@ -111,8 +111,9 @@ func (s *Service) MarshalJSON() ([]byte, error) {
 				ep.Method = "POST"
 			}
 			op = &oapiOperation{
-				Summary:     cleanEndpointSummary(ep.Summary),
-				Description: ep.Description,
+				Summary:        cleanEndpointSummary(ep.Summary),
+				Description:    ep.Description,
+				AllowedCallers: ep.AllowedCallers,
 				Responses: map[string]*oapiResponse{
 					"2XX": {
 						Description: "OK",
@@ -234,9 +235,10 @@ func (s *Service) MarshalJSON() ([]byte, error) {
 				ep.Method = "GET"
 			}
 			op = &oapiOperation{
-				Summary:     cleanEndpointSummary(ep.Summary),
-				Description: ep.Description,
-				Parameters:  []*oapiParameter{},
+				Summary:        cleanEndpointSummary(ep.Summary),
+				Description:    ep.Description,
+				AllowedCallers: ep.AllowedCallers,
+				Parameters:     []*oapiParameter{},
 				Responses: map[string]*oapiResponse{
 					"200": {
 						Description: "OK",
@@ -0,0 +1,32 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import "time"
+
+// BreakerPolicy determines when the circuit breaker of the destination of a unicast request trips.
+// The circuit breaker opens after a number of consecutive failures and fails requests fast for a cooldown period,
+// after which a single probe request is allowed through to determine if the destination recovered.
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures that trip the circuit breaker.
+	// A non-positive value disables the circuit breaker.
+	FailureThreshold int
+	// Cooldown is the duration the circuit breaker remains open before allowing a probe request through.
+	Cooldown time.Duration
+	// PerPath indicates to track the destination by its hostname and path rather than by its hostname alone.
+	PerPath bool
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestPub_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	req, err := NewRequest(GET("https://www.example.com"), CircuitBreaker(5, time.Second))
+	testarossa.NoError(t, err)
+	if testarossa.NotNil(t, req.BreakerPolicy) {
+		testarossa.Equal(t, 5, req.BreakerPolicy.FailureThreshold)
+		testarossa.Equal(t, time.Second, req.BreakerPolicy.Cooldown)
+		testarossa.False(t, req.BreakerPolicy.PerPath)
+	}
+
+	req, err = NewRequest(GET("https://www.example.com"), PathCircuitBreaker(3, time.Minute))
+	testarossa.NoError(t, err)
+	if testarossa.NotNil(t, req.BreakerPolicy) {
+		testarossa.Equal(t, 3, req.BreakerPolicy.FailureThreshold)
+		testarossa.True(t, req.BreakerPolicy.PerPath)
+	}
+
+	req, err = NewRequest(GET("https://www.example.com"), NoCircuitBreaker())
+	testarossa.NoError(t, err)
+	if testarossa.NotNil(t, req.BreakerPolicy) {
+		testarossa.Equal(t, 0, req.BreakerPolicy.FailureThreshold)
+	}
+
+	// Invalid arguments
+	_, err = NewRequest(GET("https://www.example.com"), CircuitBreaker(0, time.Second))
+	testarossa.Error(t, err)
+	_, err = NewRequest(GET("https://www.example.com"), PathCircuitBreaker(5, 0))
+	testarossa.Error(t, err)
+}
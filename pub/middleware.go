@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"context"
+
+	"github.com/microbus-io/fabric/utils"
+)
+
+// Handler makes the request and returns its responses.
+type Handler func(ctx context.Context, req *Request) (responses []*Response)
+
+// Middleware returns a function that can pre or post process an outgoing request and its responses.
+// The processor should generally call the next function in the chain.
+type Middleware func(next Handler) Handler
+
+// Chain is an ordered collection of named publish middleware.
+// The chain allows locating middleware by a name. It is advised to use a unique name for each middleware.
+type Chain struct {
+	utils.Chain[Middleware]
+}
+
+// Wrap wraps the handler with the middleware in the chain.
+// The first middleware in the chain is the first to process the request.
+func (ch *Chain) Wrap(handler Handler) Handler {
+	handlers := ch.Handlers()
+	for i := len(handlers) - 1; i >= 0; i-- {
+		handler = handlers[i](handler)
+	}
+	return handler
+}
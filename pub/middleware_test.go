@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestPub_ChainWrap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) []*Response {
+				req.Header.Add("Trail", name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	chain := &Chain{}
+	chain.Append("B", tag("B"))
+	chain.Prepend("A", tag("A"))
+	chain.Append("C", func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) []*Response {
+			// Short-circuit the request
+			return []*Response{NewErrorResponse(errors.New("injected"))}
+		}
+	})
+	called := false
+	handler := chain.Wrap(func(ctx context.Context, req *Request) []*Response {
+		called = true
+		return nil
+	})
+
+	req, err := NewRequest(GET("https://www.example.com"))
+	testarossa.NoError(t, err)
+	responses := handler(ctx, req)
+	testarossa.False(t, called)
+	testarossa.Equal(t, []string{"A", "B"}, req.Header.Values("Trail"))
+	if testarossa.Equal(t, 1, len(responses)) {
+		_, err := responses[0].Get()
+		testarossa.Error(t, err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/frame"
@@ -169,6 +170,45 @@ func Baggage(name string, value string) Option {
 	}
 }
 
+// IdempotencyKey sets the key that identifies retries of the same request.
+// An idempotent endpoint replays the response to the first request with the key to any subsequent request with the same key.
+func IdempotencyKey(key string) Option {
+	return func(req *Request) error {
+		frame.Of(req.Header).SetIdempotencyKey(key)
+		return nil
+	}
+}
+
+// Actor sets the signed JWT of the actor on behalf of which the request is made, overriding the actor of the context.
+// The token is typically obtained from the connector's SignActor.
+func Actor(token string) Option {
+	return func(req *Request) error {
+		frame.Of(req.Header).SetActorToken(token)
+		return nil
+	}
+}
+
+// Encrypted encrypts the body of the request and of its response with AES-GCM, using the keys in the PayloadKeys config.
+// Requests to subscriptions that require encryption are rejected unless encrypted.
+func Encrypted() Option {
+	return func(req *Request) error {
+		req.Encrypted = true
+		return nil
+	}
+}
+
+// Version pins the request to the instances of the destination microservice with the version number.
+// If no such instance responds, the request fails with a 404 ack timeout.
+func Version(version int) Option {
+	return func(req *Request) error {
+		if version < 0 {
+			return errors.New("version must not be negative")
+		}
+		frame.Of(req.Header).SetVersion(version)
+		return nil
+	}
+}
+
 // ContentLength sets the Content-Length header of the request.
 func ContentLength(len int) Option {
 	return func(req *Request) error {
@@ -262,6 +302,23 @@ func Body(body any) Option {
 	}
 }
 
+// CBORBody sets the body of the request to the data, encoded as CBOR if the destination is known to accept it, or as JSON otherwise.
+// The connector learns which destinations accept CBOR from their responses.
+// Use json tags to designate the name of the argument to map to each field.
+func CBORBody(body any) Option {
+	if body == nil {
+		return Noop()
+	}
+	return func(req *Request) error {
+		err := Body(body)(req)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req.CBORBody = body
+		return nil
+	}
+}
+
 // ContentType sets the Content-Type header.
 func ContentType(contentType string) Option {
 	return func(req *Request) error {
@@ -286,6 +343,161 @@ func Multicast() Option {
 	}
 }
 
+// FirstN returns the responses of a multicast request as soon as n responses, whether successful or errors, arrive.
+// Responses that arrive later are dropped.
+func FirstN(n int) Option {
+	return func(req *Request) error {
+		if n < 1 {
+			return errors.New("number of responses must be positive")
+		}
+		req.FirstN = n
+		return nil
+	}
+}
+
+// Quorum returns the responses of a multicast request as soon as n successful responses arrive.
+// Responses that arrive later are dropped.
+// If fewer than n successful responses arrive, a 503 error is returned first, followed by the responses that did arrive.
+func Quorum(n int) Option {
+	return func(req *Request) error {
+		if n < 1 {
+			return errors.New("quorum must be positive")
+		}
+		req.Quorum = n
+		return nil
+	}
+}
+
+// UntilMatch returns the responses of a multicast request as soon as a successful response satisfies the predicate.
+// Responses that arrive later are dropped.
+// The predicate should inspect the status code and headers of the response without consuming its body.
+func UntilMatch(predicate func(res *http.Response) bool) Option {
+	return func(req *Request) error {
+		if predicate == nil {
+			return errors.New("nil predicate")
+		}
+		req.UntilMatch = predicate
+		return nil
+	}
+}
+
+// Retry retries a unicast request that fails with a retriable status code, up to a max number of attempts.
+// The delay before the second attempt is the backoff duration and it doubles with each subsequent attempt,
+// randomized by up to 50% in either direction. The retryOn predicate determines which status codes are retriable.
+// If nil, requests that fail with an ack timeout or with a 503 are retried.
+// Attempts are not made if they would exceed the time budget of the context.
+// The body of the request is held in memory so that it can be sent again.
+func Retry(maxAttempts int, backoff time.Duration, retryOn func(statusCode int) bool) Option {
+	return func(req *Request) error {
+		if maxAttempts < 1 {
+			return errors.New("max attempts must be positive")
+		}
+		req.RetryPolicy = &RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			RetryOn:     retryOn,
+		}
+		return nil
+	}
+}
+
+// CircuitBreaker overrides the default circuit breaker of the connector for a unicast request.
+// The circuit breaker of the destination hostname opens after the threshold of consecutive failures is reached,
+// failing subsequent requests fast with a 503 until the cooldown period elapses.
+// Failures are ack timeouts and errors with a 5xx status code returned by the destination.
+func CircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(req *Request) error {
+		if failureThreshold < 1 {
+			return errors.New("failure threshold must be positive")
+		}
+		if cooldown <= 0 {
+			return errors.New("cooldown must be positive")
+		}
+		req.BreakerPolicy = &BreakerPolicy{
+			FailureThreshold: failureThreshold,
+			Cooldown:         cooldown,
+		}
+		return nil
+	}
+}
+
+// PathCircuitBreaker is similar to CircuitBreaker except that the destination is tracked
+// by its hostname and path rather than by its hostname alone.
+func PathCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(req *Request) error {
+		err := CircuitBreaker(failureThreshold, cooldown)(req)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req.BreakerPolicy.PerPath = true
+		return nil
+	}
+}
+
+// NoCircuitBreaker disables the circuit breaker for a unicast request.
+// The request is sent even if the circuit breaker of its destination is open, and its outcome is not tracked.
+func NoCircuitBreaker() Option {
+	return func(req *Request) error {
+		req.BreakerPolicy = &BreakerPolicy{}
+		return nil
+	}
+}
+
+// Hedge sends a second copy of a unicast request if no response arrives within the delay,
+// and returns whichever response arrives first. The response to the other copy is ignored.
+// The queue of the destination distributes the second copy, typically to another replica.
+// Hedging is limited to idempotent methods and to requests whose body fits in a single fragment.
+func Hedge(delay time.Duration) Option {
+	return func(req *Request) error {
+		if delay <= 0 {
+			return errors.New("hedge delay must be positive")
+		}
+		req.HedgeDelay = delay
+		return nil
+	}
+}
+
+// Durable stores the request in NATS JetStream for at-least-once delivery to the durable subscriptions of its URL.
+// The request is delivered even to subscribers that are not running at the time it is published, once they start.
+// No responses are returned, and the request is considered successful once it is stored.
+// The request must fit in a single fragment.
+func Durable() Option {
+	return func(req *Request) error {
+		req.Durable = true
+		return nil
+	}
+}
+
+// DeliverAt hands the request over to the scheduler core microservice to be delivered at the indicated time.
+// The time follows the clock shift of the context.
+// The response is that of the scheduler, whose JSON body contains the ID of the scheduled message.
+// The responses to the request itself are discarded when it is delivered.
+func DeliverAt(t time.Time) Option {
+	return func(req *Request) error {
+		if t.IsZero() {
+			return errors.New("time of delivery must be set")
+		}
+		req.DeliverAt = t
+		req.DeliverAfter = 0
+		return nil
+	}
+}
+
+// DeliverAfter hands the request over to the scheduler core microservice to be delivered after the indicated delay.
+// The delay is counted from the time of the context, which follows its clock shift.
+// The response is that of the scheduler, whose JSON body contains the ID of the scheduled message.
+// The responses to the request itself are discarded when it is delivered.
+func DeliverAfter(delay time.Duration) Option {
+	return func(req *Request) error {
+		if delay <= 0 {
+			return errors.New("delay must be positive")
+		}
+		req.DeliverAfter = delay
+		req.DeliverAt = time.Time{}
+		return nil
+	}
+}
+
 // Noop does nothing.
 func Noop() Option {
 	return func(r *Request) error {
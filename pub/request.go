@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/microbus-io/fabric/errors"
 )
@@ -27,11 +28,22 @@ import (
 // Request is used to construct an HTTP request that can be sent over the bus.
 // Although technically public, it is used internally and should not be constructed by microservices directly.
 type Request struct {
-	Method    string
-	URL       string
-	Header    http.Header
-	Body      io.Reader
-	Multicast bool
+	Method        string
+	URL           string
+	Header        http.Header
+	Body          io.Reader
+	Multicast     bool
+	RetryPolicy   *RetryPolicy
+	BreakerPolicy *BreakerPolicy
+	HedgeDelay    time.Duration
+	Durable       bool
+	DeliverAt     time.Time
+	DeliverAfter  time.Duration
+	Encrypted     bool
+	FirstN        int
+	Quorum        int
+	UntilMatch    func(res *http.Response) bool
+	CBORBody      any
 
 	queryArgs string
 }
@@ -47,6 +59,9 @@ func NewRequest(options ...Option) (*Request, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if !req.Multicast && (req.FirstN > 0 || req.Quorum > 0 || req.UntilMatch != nil) {
+		return nil, errors.New("first N, quorum and until match apply only to multicast requests")
+	}
 	return req, nil
 }
 
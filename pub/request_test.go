@@ -22,8 +22,10 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
 	"github.com/microbus-io/testarossa"
 )
 
@@ -70,12 +72,22 @@ func TestPub_Header(t *testing.T) {
 		GET("https://www.example.com"),
 		Header("Content-Type", "text/html"),
 		Header("X-SOMETHING", "Else"), // Uppercase
+		IdempotencyKey("1234567890"),
+		Version(7),
 	}...)
 	testarossa.NoError(t, err)
 	httpReq, err := toHTTP(req)
 	testarossa.NoError(t, err)
 	testarossa.Equal(t, "text/html", httpReq.Header.Get("Content-Type"))
 	testarossa.Equal(t, "Else", httpReq.Header.Get("X-Something"))
+	testarossa.Equal(t, "1234567890", frame.Of(httpReq).IdempotencyKey())
+	testarossa.Equal(t, 7, frame.Of(httpReq).Version())
+
+	_, err = NewRequest([]Option{
+		GET("https://www.example.com"),
+		Version(-1),
+	}...)
+	testarossa.Error(t, err)
 }
 
 func TestPub_Body(t *testing.T) {
@@ -132,6 +144,20 @@ func TestPub_Body(t *testing.T) {
 	body, err = io.ReadAll(httpReq.Body)
 	testarossa.NoError(t, err)
 	testarossa.Equal(t, `{"s":"ABC","i":123}`, string(body))
+
+	// CBOR is sent as JSON until the connector learns that the destination accepts it
+	req, err = NewRequest([]Option{
+		POST("https://www.example.com"),
+		CBORBody(j),
+	}...)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, j, req.CBORBody)
+	httpReq, err = toHTTP(req)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "application/json", httpReq.Header.Get("Content-Type"))
+	body, err = io.ReadAll(httpReq.Body)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, `{"s":"ABC","i":123}`, string(body))
 }
 
 func toHTTP(req *Request) (*http.Request, error) {
@@ -236,3 +262,31 @@ func TestPub_QueryArgs(t *testing.T) {
 	testarossa.NoError(t, err)
 	testarossa.Equal(t, "https://zzz.example.com:123/newpath?b=2&a=3&m=5&n=6&x=33&y=66", httpReq.URL.String())
 }
+
+func TestPub_Hedge(t *testing.T) {
+	t.Parallel()
+
+	req, err := NewRequest(GET("https://www.example.com"), Hedge(50*time.Millisecond))
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 50*time.Millisecond, req.HedgeDelay)
+
+	_, err = NewRequest(GET("https://www.example.com"), Hedge(0))
+	testarossa.Error(t, err)
+}
+
+func TestPub_MulticastOnly(t *testing.T) {
+	t.Parallel()
+
+	match := func(res *http.Response) bool { return true }
+	req, err := NewRequest(GET("https://www.example.com"), FirstN(2), Quorum(2), UntilMatch(match))
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, 2, req.FirstN)
+	testarossa.Equal(t, 2, req.Quorum)
+
+	_, err = NewRequest(GET("https://www.example.com"), FirstN(2), Unicast())
+	testarossa.Error(t, err)
+	_, err = NewRequest(GET("https://www.example.com"), Quorum(2), Unicast())
+	testarossa.Error(t, err)
+	_, err = NewRequest(GET("https://www.example.com"), Unicast(), UntilMatch(match))
+	testarossa.Error(t, err)
+}
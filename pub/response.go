@@ -21,8 +21,9 @@ import "net/http"
 // Response is a union of an http.Response and an error.
 // Only one or the other is valid
 type Response struct {
-	res *http.Response
-	err error
+	res    *http.Response
+	err    error
+	remote bool
 }
 
 // Get returns the http.Response or error stored in the composite Response
@@ -35,6 +36,17 @@ func NewErrorResponse(err error) *Response {
 	return &Response{err: err}
 }
 
+// NewRemoteErrorResponse creates a new response containing an error that was returned by the destination
+func NewRemoteErrorResponse(err error) *Response {
+	return &Response{err: err, remote: true}
+}
+
+// RemoteError indicates if the error was returned by the destination rather than generated by the caller,
+// e.g. because of a timeout
+func (r *Response) RemoteError() bool {
+	return r.err != nil && r.remote
+}
+
 // NewResponse creates a new response containing an http.Response
 func NewHTTPResponse(res *http.Response) *Response {
 	return &Response{res: res}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	stderrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/rand"
+)
+
+// ErrAckTimeout is the error of a unicast request that no responder acknowledged.
+// It is returned with a 404 status code.
+var ErrAckTimeout = stderrors.New("ack timeout")
+
+// IsAckTimeout indicates if the error is the result of no responder acknowledging a unicast request.
+func IsAckTimeout(err error) bool {
+	return errors.Is(err, ErrAckTimeout)
+}
+
+// RetryPolicy determines if and when a failed unicast request is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt. The delay doubles with each subsequent attempt.
+	Backoff time.Duration
+	// RetryOn determines if a request that failed with the status code should be retried.
+	// If nil, requests that fail with an ack timeout or with a 503 are retried.
+	RetryOn func(statusCode int) bool
+}
+
+// Retriable indicates if a request that failed with the status code and error should be retried.
+// Other than ack timeouts, 404 errors are not retried by default because they originate at the destination.
+func (rp *RetryPolicy) Retriable(statusCode int, err error) bool {
+	if rp.RetryOn != nil {
+		return rp.RetryOn(statusCode)
+	}
+	return IsAckTimeout(err) || statusCode == http.StatusServiceUnavailable
+}
+
+// Delay returns the delay before the indicated attempt.
+// The delay grows exponentially with the attempt number and is randomized by up to 50% in either direction.
+func (rp *RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 2 || rp.Backoff <= 0 {
+		return 0
+	}
+	shift := min(attempt-2, 16)
+	d := rp.Backoff << shift
+	return d/2 + time.Duration(rand.IntN(int(d)+1))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestPub_Retry(t *testing.T) {
+	t.Parallel()
+
+	req, err := NewRequest(GET("https://www.example.com"), Retry(3, 100*time.Millisecond, nil))
+	testarossa.NoError(t, err)
+	if testarossa.NotNil(t, req.RetryPolicy) {
+		testarossa.Equal(t, 3, req.RetryPolicy.MaxAttempts)
+		ackTimeout := errors.Tracec(http.StatusNotFound, ErrAckTimeout)
+		testarossa.True(t, IsAckTimeout(ackTimeout))
+		testarossa.True(t, req.RetryPolicy.Retriable(http.StatusNotFound, ackTimeout))
+		testarossa.False(t, req.RetryPolicy.Retriable(http.StatusNotFound, errors.Newc(http.StatusNotFound, "")))
+		testarossa.True(t, req.RetryPolicy.Retriable(http.StatusServiceUnavailable, errors.Newc(http.StatusServiceUnavailable, "")))
+		testarossa.False(t, req.RetryPolicy.Retriable(http.StatusInternalServerError, errors.New("")))
+		testarossa.False(t, req.RetryPolicy.Retriable(http.StatusOK, nil))
+	}
+
+	// Custom predicate
+	req, err = NewRequest(GET("https://www.example.com"), Retry(3, 100*time.Millisecond, func(statusCode int) bool {
+		return statusCode >= 500
+	}))
+	testarossa.NoError(t, err)
+	testarossa.True(t, req.RetryPolicy.Retriable(http.StatusInternalServerError, errors.New("")))
+	testarossa.False(t, req.RetryPolicy.Retriable(http.StatusNotFound, errors.Tracec(http.StatusNotFound, ErrAckTimeout)))
+
+	// Invalid number of attempts
+	_, err = NewRequest(GET("https://www.example.com"), Retry(0, 100*time.Millisecond, nil))
+	testarossa.Error(t, err)
+}
+
+func TestPub_RetryDelay(t *testing.T) {
+	t.Parallel()
+
+	rp := &RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     100 * time.Millisecond,
+	}
+	testarossa.Equal(t, time.Duration(0), rp.Delay(1))
+	for i := 0; i < 16; i++ {
+		// Exponential backoff with jitter
+		d := rp.Delay(2)
+		testarossa.True(t, d >= 50*time.Millisecond && d <= 150*time.Millisecond)
+		d = rp.Delay(3)
+		testarossa.True(t, d >= 100*time.Millisecond && d <= 300*time.Millisecond)
+		d = rp.Delay(4)
+		testarossa.True(t, d >= 200*time.Millisecond && d <= 600*time.Millisecond)
+	}
+}
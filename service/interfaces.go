@@ -72,12 +72,14 @@ type ShutdownHandler func(ctx context.Context) error
 type StarterStopper interface {
 	Startup() (err error)
 	Shutdown() error
+	Drain() error
 	IsStarted() bool
 	Lifetime() context.Context
 
 	SetHostname(hostname string) error
 	SetDeployment(deployment string) error
 	SetPlane(plane string) error
+	SetNATSURL(url string) error
 
 	SetOnStartup(handler StartupHandler) error
 	SetOnShutdown(handler ShutdownHandler) error
@@ -120,6 +122,9 @@ type Resourcer interface {
 	ResFS() FS
 }
 
+// HealthCheckHandler handles a health check of the microservice.
+type HealthCheckHandler func(ctx context.Context) error
+
 // TickerHandler handles the ticker callbacks.
 type TickerHandler func(ctx context.Context) error
 
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/frame"
+)
+
+var actorTermValidator = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_\-]*(\.[^\s]+)?$`)
+
+// ActorRequirement is a condition on the claims of the actor of a request.
+// Alternatives are separated by || and conditions that must all be met are separated by &&.
+// Each condition is either the name of a claim that must be truthy, e.g. "verified",
+// or a claim and a value separated by a dot, e.g. "roles.admin", which requires that the claim equals the value or
+// that the claim is an array that contains the value.
+//
+// Examples:
+//
+//	roles.admin
+//	roles.admin || roles.manager
+//	verified && iss.my.ingress.host
+type ActorRequirement struct {
+	expr  string
+	anyOf [][]actorCondition
+}
+
+// actorCondition is a single condition on a claim of the actor.
+type actorCondition struct {
+	claim    string
+	value    string
+	hasValue bool
+}
+
+// ParseActorRequirement parses a requirement on the claims of the actor of a request.
+func ParseActorRequirement(expr string) (*ActorRequirement, error) {
+	req := &ActorRequirement{
+		expr: strings.TrimSpace(expr),
+	}
+	if req.expr == "" {
+		return nil, errors.New("empty actor requirement")
+	}
+	for _, alternative := range strings.Split(req.expr, "||") {
+		var allOf []actorCondition
+		for _, term := range strings.Split(alternative, "&&") {
+			term = strings.TrimSpace(term)
+			if !actorTermValidator.MatchString(term) {
+				return nil, errors.Newf("invalid actor requirement '%s'", expr)
+			}
+			claim, value, hasValue := strings.Cut(term, ".")
+			allOf = append(allOf, actorCondition{
+				claim:    claim,
+				value:    value,
+				hasValue: hasValue,
+			})
+		}
+		req.anyOf = append(req.anyOf, allOf)
+	}
+	return req, nil
+}
+
+// String returns the expression of the requirement.
+func (req *ActorRequirement) String() string {
+	return req.expr
+}
+
+// SatisfiedBy indicates whether the claims of the actor satisfy the requirement.
+func (req *ActorRequirement) SatisfiedBy(actor frame.Actor) bool {
+	for _, allOf := range req.anyOf {
+		ok := true
+		for _, cond := range allOf {
+			if cond.hasValue {
+				ok = actor.HasValue(cond.claim, cond.value)
+			} else {
+				ok = actor.Has(cond.claim)
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"testing"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestSub_ActorRequirement(t *testing.T) {
+	t.Parallel()
+
+	harry := frame.Actor{
+		"sub":      "harry@hogwarts.edu",
+		"iss":      "my.ingress.host",
+		"roles":    []any{"wizard", "seeker"},
+		"verified": true,
+	}
+	draco := frame.Actor{
+		"sub":   "draco@hogwarts.edu",
+		"roles": []any{"wizard", "prefect"},
+	}
+
+	testCases := []struct {
+		expr  string
+		harry bool
+		draco bool
+	}{
+		{"roles.wizard", true, true},
+		{"roles.seeker", true, false},
+		{"roles.seeker || roles.prefect", true, true},
+		{"roles.wizard && verified", true, false},
+		{"verified && iss.my.ingress.host", true, false},
+		{"roles.muggle || roles.prefect && roles.wizard", false, true},
+		{"sub.draco@hogwarts.edu", false, true},
+		{"house", false, false},
+	}
+	for _, tc := range testCases {
+		req, err := ParseActorRequirement(tc.expr)
+		if testarossa.NoError(t, err) {
+			testarossa.Equal(t, tc.expr, req.String())
+			testarossa.Equal(t, tc.harry, req.SatisfiedBy(harry), "%s", tc.expr)
+			testarossa.Equal(t, tc.draco, req.SatisfiedBy(draco), "%s", tc.expr)
+			testarossa.False(t, req.SatisfiedBy(nil), "%s", tc.expr)
+		}
+	}
+
+	for _, expr := range []string{"", " ", "roles.admin ||", "&& verified", "roles admin", ".admin", "roles.", "9lives"} {
+		_, err := ParseActorRequirement(expr)
+		testarossa.Error(t, err, "%s", expr)
+	}
+
+	var s Subscription
+	err := Actor("roles.admin || roles.manager")(&s)
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, "roles.admin || roles.manager", s.Actor.String())
+	err = Actor("roles admin")(&s)
+	testarossa.Error(t, err)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"strings"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// ValidateCallerPattern validates a pattern of the hostnames of the callers allowed to call a subscription.
+// A pattern is either a hostname, e.g. "billing.core", a hostname prefixed by a wildcard that matches
+// one or more leading segments, e.g. "*.admin", or a lone wildcard that matches any caller.
+func ValidateCallerPattern(pattern string) error {
+	if pattern == "*" {
+		return nil
+	}
+	hostname := strings.TrimPrefix(pattern, "*.")
+	if strings.Contains(hostname, "*") || utils.ValidateHostname(hostname) != nil {
+		return errors.Newf("invalid caller pattern '%s'", pattern)
+	}
+	return nil
+}
+
+// AllowsCaller indicates if the hostname of the caller matches any of the allowed callers of the subscription.
+// All callers are allowed if the subscription does not restrict its callers.
+func (sub *Subscription) AllowsCaller(hostname string) bool {
+	if len(sub.AllowedCallers) == 0 {
+		return true
+	}
+	hostname = strings.ToLower(hostname)
+	for _, pattern := range sub.AllowedCallers {
+		if pattern == "*" || pattern == hostname {
+			return true
+		}
+		if strings.HasPrefix(pattern, "*.") && strings.HasSuffix(hostname, pattern[1:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestSub_AllowCallers(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) error { return nil }
+
+	s, err := NewSub("GET", "ledger.core", "/debit", handler)
+	testarossa.NoError(t, err)
+	testarossa.True(t, s.AllowsCaller("anyone.at.all"))
+
+	s, err = NewSub("GET", "ledger.core", "/debit", handler, AllowCallers("billing.core", "*.Admin"))
+	testarossa.NoError(t, err)
+	testarossa.True(t, s.AllowsCaller("billing.core"))
+	testarossa.True(t, s.AllowsCaller("BILLING.core"))
+	testarossa.True(t, s.AllowsCaller("users.admin"))
+	testarossa.True(t, s.AllowsCaller("super.users.admin"))
+	testarossa.False(t, s.AllowsCaller("admin"))
+	testarossa.False(t, s.AllowsCaller("superadmin"))
+	testarossa.False(t, s.AllowsCaller("billing.core.evil"))
+	testarossa.False(t, s.AllowsCaller(""))
+
+	s, err = NewSub("GET", "ledger.core", "/debit", handler, AllowCallers("*"))
+	testarossa.NoError(t, err)
+	testarossa.True(t, s.AllowsCaller("anyone.at.all"))
+
+	for _, pattern := range []string{"", "**", "billing.*", "*.*.admin", "bill ing.core", "*."} {
+		_, err = NewSub("GET", "ledger.core", "/debit", handler, AllowCallers(pattern))
+		testarossa.Error(t, err, "%s", pattern)
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import "time"
+
+// DurablePolicy determines how the messages of a durable subscription are redelivered when their handling fails.
+type DurablePolicy struct {
+	// MaxDeliveries is the number of times a message is delivered before it is moved to the dead-letter subject.
+	MaxDeliveries int
+	// Backoff is the delay before the first redelivery. It doubles with each subsequent redelivery.
+	Backoff time.Duration
+}
@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/microbus-io/fabric/utils"
+)
+
+// Middleware returns a function that can pre or post process the request or response of a subscription.
+// The processor should generally call the next function in the chain.
+type Middleware func(next HTTPHandler) HTTPHandler
+
+// Chain is an ordered collection of named subscription middleware.
+// The chain allows locating middleware by a name. It is advised to use a unique name for each middleware.
+type Chain struct {
+	utils.Chain[Middleware]
+}
+
+// Wrap wraps the handler with the middleware in the chain.
+// The first middleware in the chain is the first to process the request.
+func (ch *Chain) Wrap(handler HTTPHandler) HTTPHandler {
+	handlers := ch.Handlers()
+	for i := len(handlers) - 1; i >= 0; i-- {
+		handler = handlers[i](handler)
+	}
+	return handler
+}
+
+/*
+OnRoute returns a middleware that applies the conditional middleware only to requests that match the route.
+The route is made of an optional port and an optional path prefix.
+A route without a port matches all ports, and a route without a path matches all paths.
+
+Examples of valid routes:
+
+	:444
+	/admin/
+	:443/admin/
+*/
+func OnRoute(route string, conditional Middleware) Middleware {
+	port := ""
+	pathPrefix := route
+	if strings.HasPrefix(route, ":") {
+		port = strings.TrimPrefix(route, ":")
+		pathPrefix = ""
+		if slash := strings.Index(port, "/"); slash >= 0 {
+			pathPrefix = port[slash:]
+			port = port[:slash]
+		}
+	}
+	return func(next HTTPHandler) HTTPHandler {
+		nextOnRoute := conditional(next)
+		return func(w http.ResponseWriter, r *http.Request) (err error) {
+			if routeMatches(r, port, pathPrefix) {
+				return nextOnRoute(w, r) // No trace
+			}
+			return next(w, r) // No trace
+		}
+	}
+}
+
+// routeMatches indicates if the port and path of the request match the route.
+func routeMatches(r *http.Request, port string, pathPrefix string) bool {
+	if port != "" {
+		reqPort := r.URL.Port()
+		if reqPort == "" {
+			reqPort = "443"
+			if r.URL.Scheme == "http" {
+				reqPort = "80"
+			}
+		}
+		if reqPort != port {
+			return false
+		}
+	}
+	return strings.HasPrefix(r.URL.Path, pathPrefix)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestSub_ChainWrap(t *testing.T) {
+	t.Parallel()
+
+	tag := func(name string) Middleware {
+		return func(next HTTPHandler) HTTPHandler {
+			return func(w http.ResponseWriter, r *http.Request) error {
+				r.Header.Add("Trail", name)
+				return next(w, r)
+			}
+		}
+	}
+
+	chain := &Chain{}
+	chain.Append("B", tag("B"))
+	chain.Prepend("A", tag("A"))
+	chain.Append("C", func(next HTTPHandler) HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			// Short-circuit the request
+			return errors.New("injected")
+		}
+	})
+	called := false
+	handler := chain.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		called = true
+		return nil
+	})
+
+	r := httptest.NewRequest("GET", "https://www.example.com/", nil)
+	err := handler(httptest.NewRecorder(), r)
+	testarossa.Error(t, err)
+	testarossa.False(t, called)
+	testarossa.Equal(t, []string{"A", "B"}, r.Header.Values("Trail"))
+}
+
+func TestSub_OnRoute(t *testing.T) {
+	t.Parallel()
+
+	mark := func(next HTTPHandler) HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			r.Header.Set("Marked", "1")
+			return next(w, r)
+		}
+	}
+	final := func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}
+	testCases := []struct {
+		route string
+		url   string
+		match bool
+	}{
+		{":444", "https://www.example.com:444/path", true},
+		{":444", "https://www.example.com/path", false},
+		{":443", "https://www.example.com/path", true},
+		{":80", "http://www.example.com/path", true},
+		{"/admin/", "https://www.example.com:444/admin/users", true},
+		{"/admin/", "https://www.example.com/users", false},
+		{":443/admin/", "https://www.example.com/admin/users", true},
+		{":443/admin/", "https://www.example.com:444/admin/users", false},
+		{":443/admin/", "https://www.example.com/users", false},
+		{"", "https://www.example.com:1234/any", true},
+	}
+	for _, tc := range testCases {
+		handler := OnRoute(tc.route, mark)(final)
+		r := httptest.NewRequest("GET", tc.url, nil)
+		err := handler(httptest.NewRecorder(), r)
+		testarossa.NoError(t, err)
+		testarossa.Equal(t, tc.match, r.Header.Get("Marked") == "1", "%s %s", tc.route, tc.url)
+	}
+}
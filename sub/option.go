@@ -18,6 +18,8 @@ package sub
 
 import (
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/microbus-io/fabric/errors"
 )
@@ -70,3 +72,160 @@ func DefaultQueue() Option {
 func LoadBalanced() Option {
 	return DefaultQueue()
 }
+
+// Durable delivers requests to the subscription at least once, even if they were published while no instance of the microservice was running.
+// Durable requests are stored in NATS JetStream and acknowledged when the handler succeeds.
+// A request whose handler fails is redelivered up to 5 times with a backoff starting at 1 second,
+// after which it is moved to a dead-letter subject.
+// Durable subscriptions must be load-balanced in a queue and only receive requests published with pub.Durable.
+func Durable() Option {
+	return func(sub *Subscription) error {
+		if sub.Durable == nil {
+			sub.Durable = &DurablePolicy{
+				MaxDeliveries: 5,
+				Backoff:       time.Second,
+			}
+		}
+		return nil
+	}
+}
+
+// Redelivery makes the subscription durable and overrides the number of times a request is delivered before
+// it is moved to the dead-letter subject, and the delay before the first redelivery.
+// The delay doubles with each subsequent redelivery.
+func Redelivery(maxDeliveries int, backoff time.Duration) Option {
+	return func(sub *Subscription) error {
+		if maxDeliveries < 1 {
+			return errors.New("max deliveries must be positive")
+		}
+		if backoff <= 0 {
+			return errors.New("backoff must be positive")
+		}
+		sub.Durable = &DurablePolicy{
+			MaxDeliveries: maxDeliveries,
+			Backoff:       backoff,
+		}
+		return nil
+	}
+}
+
+// MaxConcurrency limits the number of requests to the subscription that are processed concurrently by this instance of the microservice.
+// Requests over the limit are queued if MaxPending allows, or rejected with a 503 status code otherwise.
+// A value of 0 indicates no limit.
+func MaxConcurrency(n int) Option {
+	return func(sub *Subscription) error {
+		if n < 0 {
+			return errors.Newf("negative max concurrency '%d'", n)
+		}
+		sub.MaxConcurrency = n
+		return nil
+	}
+}
+
+// MaxPending limits the number of requests to the subscription that wait in queue for their turn to be processed
+// when the limit set by MaxConcurrency is reached. Requests over this limit are rejected with a 503 status code.
+// A value of 0 indicates that requests are not queued.
+// MaxPending has no effect unless MaxConcurrency is also set.
+func MaxPending(n int) Option {
+	return func(sub *Subscription) error {
+		if n < 0 {
+			return errors.Newf("negative max pending '%d'", n)
+		}
+		sub.MaxPending = n
+		return nil
+	}
+}
+
+// RateLimit limits the rate of requests to the subscription that are processed by each replica of the microservice.
+// The rate is the number of requests per second allowed over time and the burst is the number of requests allowed at once.
+// Requests over the limit are rejected with a 429 status code.
+func RateLimit(rate float64, burst int) Option {
+	return func(sub *Subscription) error {
+		if rate <= 0 {
+			return errors.New("rate must be positive")
+		}
+		if burst < 1 {
+			return errors.New("burst must be positive")
+		}
+		sub.RateLimit = &RateLimitPolicy{
+			Rate:  rate,
+			Burst: burst,
+		}
+		return nil
+	}
+}
+
+// ClusterRateLimit approximates a limit on the rate of requests to the subscription that are processed by all replicas of the microservice combined.
+// The limit is not backed by shared state. The replicas count each other periodically and each enforces locally its share of the rate and the burst.
+// The combined limit is therefore off while the count is stale and when requests are not evenly load-balanced.
+// Requests over the limit are rejected with a 429 status code.
+func ClusterRateLimit(rate float64, burst int) Option {
+	return func(sub *Subscription) error {
+		if rate <= 0 {
+			return errors.New("rate must be positive")
+		}
+		if burst < 1 {
+			return errors.New("burst must be positive")
+		}
+		sub.RateLimit = &RateLimitPolicy{
+			Rate:    rate,
+			Burst:   burst,
+			Cluster: true,
+		}
+		return nil
+	}
+}
+
+// Idempotent replays the response to the first request that carries an idempotency key to any subsequent request with the same key.
+// The key is taken from the frame, as set by pub.IdempotencyKey, or else from the Idempotency-Key header.
+// A request whose key matches a request still in flight is rejected with a 409 status code.
+// Responses are stored in the distributed cache of the microservice and only successful responses are stored.
+// Idempotency applies only to subscriptions that are load-balanced in a queue.
+func Idempotent() Option {
+	return func(sub *Subscription) error {
+		sub.Idempotent = true
+		return nil
+	}
+}
+
+// Actor requires that requests to the subscription be made on behalf of an actor whose claims satisfy the requirement.
+// Requests without a valid actor token are rejected with a 401 status code,
+// and requests whose actor does not satisfy the requirement are rejected with a 403 status code.
+// See ActorRequirement for the syntax of the requirement.
+func Actor(requirement string) Option {
+	return func(sub *Subscription) error {
+		req, err := ParseActorRequirement(requirement)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sub.Actor = req
+		return nil
+	}
+}
+
+// Encrypted requires that the bodies of requests to the subscription, and of their responses, be encrypted with AES-GCM
+// using the keys in the PayloadKeys config. Requests that are not encrypted are rejected with a 400 status code.
+// Frame headers remain in cleartext.
+func Encrypted() Option {
+	return func(sub *Subscription) error {
+		sub.Encrypted = true
+		return nil
+	}
+}
+
+// AllowCallers restricts the callers of the subscription to the microservices whose hostnames match any of the patterns.
+// A pattern is either a hostname, e.g. "billing.core", or a hostname prefixed by a wildcard, e.g. "*.admin".
+// Requests from other callers are rejected with a 403 status code.
+// The hostname of the caller is taken from the control frame and should be protected by signing messages.
+func AllowCallers(patterns ...string) Option {
+	return func(sub *Subscription) error {
+		for _, pattern := range patterns {
+			err := ValidateCallerPattern(pattern)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			sub.AllowedCallers = append(sub.AllowedCallers, strings.ToLower(pattern))
+		}
+		return nil
+	}
+}
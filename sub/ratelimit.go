@@ -0,0 +1,28 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sub
+
+// RateLimitPolicy determines the rate at which requests to a subscription are allowed through.
+type RateLimitPolicy struct {
+	// Rate is the number of requests per second that are allowed through over time.
+	Rate float64
+	// Burst is the number of requests that are allowed through at once.
+	Burst int
+	// Cluster indicates that the limit is divided among the replicas of the microservice rather than applied to each replica.
+	// Each replica enforces its share locally, so the combined limit is approximate.
+	Cluster bool
+}
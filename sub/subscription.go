@@ -25,8 +25,8 @@ import (
 
 	"github.com/microbus-io/fabric/errors"
 	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/transport"
 	"github.com/microbus-io/fabric/utils"
-	"github.com/nats-io/nats.go"
 )
 
 var methodValidator = regexp.MustCompile(`^[A-Z]+$`)
@@ -37,14 +37,22 @@ type HTTPHandler func(w http.ResponseWriter, r *http.Request) (err error)
 // Subscription handles incoming requests.
 // Although technically public, it is used internally and should not be constructed by microservices directly.
 type Subscription struct {
-	Host     string
-	Port     string
-	Method   string
-	Path     string
-	Queue    string
-	Handler  any
-	Subs     []*nats.Subscription
-	specPath string
+	Host           string
+	Port           string
+	Method         string
+	Path           string
+	Queue          string
+	Handler        any
+	Durable        *DurablePolicy
+	MaxConcurrency int
+	MaxPending     int
+	RateLimit      *RateLimitPolicy
+	Idempotent     bool
+	Actor          *ActorRequirement
+	Encrypted      bool
+	AllowedCallers []string
+	Subs           []*transport.Subscription
+	specPath       string
 }
 
 /*
@@ -120,6 +120,29 @@ func TestSub_Apply(t *testing.T) {
 
 	err = s.Apply(Queue("$$$"))
 	testarossa.Error(t, err)
+
+	s.Apply(MaxConcurrency(4), MaxPending(16))
+	testarossa.Equal(t, 4, s.MaxConcurrency)
+	testarossa.Equal(t, 16, s.MaxPending)
+	err = s.Apply(MaxConcurrency(-1))
+	testarossa.Error(t, err)
+	err = s.Apply(MaxPending(-1))
+	testarossa.Error(t, err)
+
+	s.Apply(RateLimit(10, 5))
+	testarossa.Equal(t, 10.0, s.RateLimit.Rate)
+	testarossa.Equal(t, 5, s.RateLimit.Burst)
+	testarossa.False(t, s.RateLimit.Cluster)
+	s.Apply(ClusterRateLimit(100, 20))
+	testarossa.True(t, s.RateLimit.Cluster)
+	err = s.Apply(RateLimit(0, 5))
+	testarossa.Error(t, err)
+	err = s.Apply(ClusterRateLimit(10, 0))
+	testarossa.Error(t, err)
+
+	testarossa.False(t, s.Idempotent)
+	s.Apply(Idempotent())
+	testarossa.True(t, s.Idempotent)
 }
 
 func TestSub_Canonical(t *testing.T) {
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/microbus-io/fabric/rand"
+	"github.com/nats-io/nats.go"
+)
+
+// shortCircuit is the in-process bus shared by all connections in the process.
+var shortCircuit = &bus{
+	id: strings.ToLower(rand.AlphaNum32(12)),
+}
+
+// bus delivers messages directly to subscribers in the same process.
+type bus struct {
+	id   string
+	mux  sync.RWMutex
+	subs []*localSub
+}
+
+// subscribe adds a subscription to the bus.
+func (b *bus) subscribe(subject string, queue string, handler MsgHandler) *localSub {
+	ls := newLocalSub(subject, queue, handler)
+	b.mux.Lock()
+	b.subs = append(b.subs, ls)
+	b.mux.Unlock()
+	return ls
+}
+
+// unsubscribe removes a subscription from the bus.
+func (b *bus) unsubscribe(ls *localSub) {
+	b.mux.Lock()
+	for i := range b.subs {
+		if b.subs[i] == ls {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			break
+		}
+	}
+	b.mux.Unlock()
+	ls.close()
+}
+
+// publish delivers the message to all matching subscribers that are not in a queue,
+// and to one randomly chosen member of each matching queue.
+// It returns the names of the queues that were served.
+func (b *bus) publish(subject string, data []byte) (servedQueues []string) {
+	msg := &Msg{
+		Subject: subject,
+		Data:    append([]byte(nil), data...),
+	}
+	var queues map[string][]*localSub
+	b.mux.RLock()
+	for _, ls := range b.subs {
+		if !matchSubject(ls.subject, subject) {
+			continue
+		}
+		if ls.queue == "" {
+			ls.push(msg)
+			continue
+		}
+		if queues == nil {
+			queues = map[string][]*localSub{}
+		}
+		queues[ls.queue] = append(queues[ls.queue], ls)
+	}
+	b.mux.RUnlock()
+	for queue, members := range queues {
+		members[rand.IntN(len(members))].push(msg)
+		servedQueues = append(servedQueues, queue)
+	}
+	return servedQueues
+}
+
+// matchSubject indicates if the subject matches the subscription pattern.
+// The pattern may include the NATS wildcards * to match a single token and > to match one or more trailing tokens.
+func matchSubject(pattern string, subject string) bool {
+	for {
+		p, pRest, pMore := strings.Cut(pattern, ".")
+		if p == ">" {
+			return subject != ""
+		}
+		s, sRest, sMore := strings.Cut(subject, ".")
+		if p != "*" && p != s {
+			return false
+		}
+		if !pMore || !sMore {
+			return pMore == sMore
+		}
+		pattern, subject = pRest, sRest
+	}
+}
+
+// localSub is a subscription to the in-process bus.
+// Messages are delivered to the handler in order, one at a time, by a dedicated goroutine.
+// As with NATS, messages that arrive while the pending limits are reached are dropped.
+type localSub struct {
+	subject      string
+	queue        string
+	handler      MsgHandler
+	mux          sync.Mutex
+	pending      []*Msg
+	pendingBytes int
+	maxMsgs      int
+	maxBytes     int
+	dropped      int
+	signal       chan struct{}
+	closed       bool
+}
+
+// newLocalSub creates a new subscription and starts its delivery goroutine.
+func newLocalSub(subject string, queue string, handler MsgHandler) *localSub {
+	ls := &localSub{
+		subject:  subject,
+		queue:    queue,
+		handler:  handler,
+		maxMsgs:  nats.DefaultSubPendingMsgsLimit,
+		maxBytes: nats.DefaultSubPendingBytesLimit,
+		signal:   make(chan struct{}, 1),
+	}
+	go ls.deliver()
+	return ls
+}
+
+// push queues a message for delivery.
+// The message is dropped if the subscriber is too slow to keep up and the pending limits are reached.
+func (ls *localSub) push(msg *Msg) {
+	ls.mux.Lock()
+	if !ls.closed {
+		if len(ls.pending) >= ls.maxMsgs || ls.pendingBytes+len(msg.Data) > ls.maxBytes {
+			ls.dropped++
+		} else {
+			ls.pending = append(ls.pending, msg)
+			ls.pendingBytes += len(msg.Data)
+		}
+	}
+	ls.mux.Unlock()
+	select {
+	case ls.signal <- struct{}{}:
+	default:
+	}
+}
+
+// close stops the delivery of messages, discarding those still pending.
+func (ls *localSub) close() {
+	ls.mux.Lock()
+	ls.closed = true
+	ls.pending = nil
+	ls.pendingBytes = 0
+	ls.mux.Unlock()
+	select {
+	case ls.signal <- struct{}{}:
+	default:
+	}
+}
+
+// deliver delivers pending messages to the handler until the subscription is closed.
+func (ls *localSub) deliver() {
+	for range ls.signal {
+		for {
+			ls.mux.Lock()
+			if ls.closed {
+				ls.mux.Unlock()
+				return
+			}
+			if len(ls.pending) == 0 {
+				ls.mux.Unlock()
+				break
+			}
+			msg := ls.pending[0]
+			ls.pending[0] = nil
+			ls.pending = ls.pending[1:]
+			ls.pendingBytes -= len(msg.Data)
+			ls.mux.Unlock()
+			ls.handler(msg)
+		}
+	}
+}
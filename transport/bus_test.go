@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"testing"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestTransport_MatchSubject(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		subject string
+		match   bool
+	}{
+		{"a.b.c", "a.b.c", true},
+		{"a.b.c", "a.b", false},
+		{"a.b", "a.b.c", false},
+		{"a.b.c", "a.b.d", false},
+		{"a.*.c", "a.b.c", true},
+		{"a.*.c", "a.b.b.c", false},
+		{"a.*", "a", false},
+		{"*", "a", true},
+		{"*", "a.b", false},
+		{"a.>", "a.b", true},
+		{"a.>", "a.b.c.d", true},
+		{"a.>", "a", false},
+		{">", "a.b", true},
+		{"a.*.>", "a.b.c", true},
+		{"a.*.>", "a.b", false},
+	}
+	for _, tc := range testCases {
+		testarossa.Equal(t, tc.match, matchSubject(tc.pattern, tc.subject), "%s ~ %s", tc.pattern, tc.subject)
+	}
+}
+
+func TestTransport_LocalSubPendingLimits(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan bool, 16)
+	block := make(chan bool)
+	delivered := make(chan string, 16)
+	ls := newLocalSub("pending.limits", "", func(msg *Msg) {
+		started <- true
+		<-block
+		delivered <- string(msg.Data)
+	})
+	defer ls.close()
+	ls.mux.Lock()
+	ls.maxMsgs = 2
+	ls.maxBytes = 8
+	ls.mux.Unlock()
+
+	// The first message is taken by the blocked handler
+	ls.push(&Msg{Subject: "pending.limits", Data: []byte("0")})
+	<-started
+
+	// The message limit is reached after two messages
+	ls.push(&Msg{Subject: "pending.limits", Data: []byte("1")})
+	ls.push(&Msg{Subject: "pending.limits", Data: []byte("2")})
+	ls.push(&Msg{Subject: "pending.limits", Data: []byte("3")})
+	ls.mux.Lock()
+	testarossa.Equal(t, 2, len(ls.pending))
+	testarossa.Equal(t, 1, ls.dropped)
+	ls.mux.Unlock()
+
+	// Release the handler to drain the queue
+	close(block)
+	testarossa.Equal(t, "0", <-delivered)
+	testarossa.Equal(t, "1", <-delivered)
+	testarossa.Equal(t, "2", <-delivered)
+
+	// The byte limit is reached before the message limit
+	ls.push(&Msg{Subject: "pending.limits", Data: []byte("123456789")})
+	ls.mux.Lock()
+	testarossa.Equal(t, 2, ls.dropped)
+	ls.mux.Unlock()
+}
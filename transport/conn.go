@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/nats-io/nats.go"
+)
+
+// Headers of NATS messages that were also delivered to subscribers in the process of the publisher
+const (
+	headerOrigin       = "Microbus-Origin"
+	headerServedQueues = "Microbus-Served-Queues"
+)
+
+// Msg is a message delivered to a subscription.
+type Msg struct {
+	Subject string
+	Data    []byte
+}
+
+// MsgHandler handles the messages delivered to a subscription.
+type MsgHandler func(msg *Msg)
+
+// Conn is a connection to the messaging bus.
+// When short-circuiting is enabled, messages are delivered directly to subscribers in the same process,
+// and over NATS to subscribers elsewhere.
+// Either way, a message is delivered to all subscribers that are not in a queue and to one member of each queue.
+type Conn struct {
+	natsConn     *nats.Conn
+	shortCircuit bool
+	localSubs    map[*localSub]bool
+	mux          sync.Mutex
+}
+
+// Open creates a new connection over the NATS connection.
+// If short-circuiting is enabled, messages are also delivered directly to subscribers in the same process.
+// The NATS connection may be nil if short-circuiting is enabled,
+// in which case only subscribers in the same process are reachable.
+func Open(natsConn *nats.Conn, shortCircuit bool) (*Conn, error) {
+	if natsConn == nil && !shortCircuit {
+		return nil, errors.New("no transport")
+	}
+	return &Conn{
+		natsConn:     natsConn,
+		shortCircuit: shortCircuit,
+		localSubs:    map[*localSub]bool{},
+	}, nil
+}
+
+// NATS returns the underlying NATS connection, or nil if there is none.
+func (c *Conn) NATS() *nats.Conn {
+	return c.natsConn
+}
+
+// MaxPayload returns the maximum size of a message.
+func (c *Conn) MaxPayload() int64 {
+	if c.natsConn != nil {
+		return c.natsConn.MaxPayload()
+	}
+	return 1024 * 1024
+}
+
+// Publish publishes a message to the subject.
+// Publishing over a nil connection, such as one of a connector that shut down, fails rather than panics.
+func (c *Conn) Publish(subject string, data []byte) error {
+	if c == nil {
+		return errors.Trace(nats.ErrInvalidConnection)
+	}
+	if !c.shortCircuit {
+		return errors.Trace(c.natsConn.Publish(subject, data))
+	}
+	servedQueues := shortCircuit.publish(subject, data)
+	if c.natsConn == nil {
+		return nil
+	}
+	// Mark the message so that subscribers do not process it again when it arrives over NATS
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(headerOrigin, shortCircuit.id)
+	if len(servedQueues) > 0 {
+		msg.Header.Set(headerServedQueues, strings.Join(servedQueues, ","))
+	}
+	return errors.Trace(c.natsConn.PublishMsg(msg))
+}
+
+// Subscribe subscribes to messages published to the subject.
+// The subject may include the NATS wildcards * and >.
+func (c *Conn) Subscribe(subject string, handler MsgHandler) (*Subscription, error) {
+	return c.subscribe(subject, "", handler)
+}
+
+// QueueSubscribe subscribes to messages published to the subject as a member of the queue.
+// Each message is delivered to only one member of the queue.
+// The subject may include the NATS wildcards * and >.
+func (c *Conn) QueueSubscribe(subject string, queue string, handler MsgHandler) (*Subscription, error) {
+	return c.subscribe(subject, queue, handler)
+}
+
+// subscribe subscribes to messages published to the subject, optionally as a member of a queue.
+func (c *Conn) subscribe(subject string, queue string, handler MsgHandler) (s *Subscription, err error) {
+	if c == nil {
+		return nil, errors.Trace(nats.ErrInvalidConnection)
+	}
+	s = &Subscription{conn: c, subject: subject}
+	if c.shortCircuit {
+		s.localSub = shortCircuit.subscribe(subject, queue, handler)
+		c.mux.Lock()
+		c.localSubs[s.localSub] = true
+		c.mux.Unlock()
+	}
+	if c.natsConn != nil {
+		natsHandler := func(msg *nats.Msg) {
+			if s.skip(msg, queue) {
+				return
+			}
+			handler(&Msg{
+				Subject: msg.Subject,
+				Data:    msg.Data,
+			})
+		}
+		if queue != "" {
+			s.natsSub, err = c.natsConn.QueueSubscribe(subject, queue, natsHandler)
+		} else {
+			s.natsSub, err = c.natsConn.Subscribe(subject, natsHandler)
+		}
+		if err != nil {
+			s.Unsubscribe()
+			return nil, errors.Trace(err)
+		}
+	}
+	return s, nil
+}
+
+// Close unsubscribes all subscriptions made over this connection and closes the underlying NATS connection.
+func (c *Conn) Close() {
+	c.mux.Lock()
+	localSubs := c.localSubs
+	c.localSubs = map[*localSub]bool{}
+	c.mux.Unlock()
+	for ls := range localSubs {
+		shortCircuit.unsubscribe(ls)
+	}
+	if c.natsConn != nil {
+		c.natsConn.Close()
+	}
+}
+
+// Subscription is a subscription to messages published to a subject.
+type Subscription struct {
+	conn     *Conn
+	subject  string
+	natsSub  *nats.Subscription
+	localSub *localSub
+}
+
+// Subject is the subject of the subscription.
+func (s *Subscription) Subject() string {
+	return s.subject
+}
+
+// Unsubscribe stops the delivery of messages to the subscription.
+func (s *Subscription) Unsubscribe() error {
+	if s.localSub != nil {
+		shortCircuit.unsubscribe(s.localSub)
+		s.conn.mux.Lock()
+		delete(s.conn.localSubs, s.localSub)
+		s.conn.mux.Unlock()
+	}
+	if s.natsSub != nil {
+		return errors.Trace(s.natsSub.Unsubscribe())
+	}
+	return nil
+}
+
+// skip indicates if a message that arrived over NATS should be skipped
+// because it was already delivered to the subscription's queue, or to the subscription itself, in the process of the publisher.
+func (s *Subscription) skip(msg *nats.Msg, queue string) bool {
+	if msg.Header == nil {
+		return false
+	}
+	origin := msg.Header.Get(headerOrigin)
+	if origin == "" {
+		return false
+	}
+	if queue != "" {
+		for _, served := range strings.Split(msg.Header.Get(headerServedQueues), ",") {
+			if served == queue {
+				return true
+			}
+		}
+		return false
+	}
+	return origin == shortCircuit.id && s.localSub != nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/rand"
+	"github.com/microbus-io/testarossa"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestTransport_ShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	subject := "test." + rand.AlphaNum32(8) + ".subject"
+
+	alpha, err := Open(nil, true)
+	testarossa.NoError(t, err)
+	defer alpha.Close()
+	beta, err := Open(nil, true)
+	testarossa.NoError(t, err)
+	defer beta.Close()
+
+	// Subscribers that are not in a queue receive all messages, in order
+	var mux sync.Mutex
+	var received []string
+	sub, err := alpha.Subscribe(subject, func(msg *Msg) {
+		mux.Lock()
+		received = append(received, string(msg.Data))
+		mux.Unlock()
+	})
+	testarossa.NoError(t, err)
+	testarossa.Equal(t, subject, sub.Subject())
+	var wildcardCount atomic.Int32
+	_, err = beta.Subscribe("test.*.>", func(msg *Msg) {
+		if msg.Subject == subject {
+			wildcardCount.Add(1)
+		}
+	})
+	testarossa.NoError(t, err)
+
+	// Only one member of a queue receives each message
+	var queueCount atomic.Int32
+	queueSubs := []*Subscription{}
+	for _, conn := range []*Conn{alpha, beta, beta} {
+		s, err := conn.QueueSubscribe(subject, "queue", func(msg *Msg) {
+			queueCount.Add(1)
+		})
+		testarossa.NoError(t, err)
+		queueSubs = append(queueSubs, s)
+	}
+
+	const n = 64
+	for i := 0; i < n; i++ {
+		err = beta.Publish(subject, []byte(strconv.Itoa(i)))
+		testarossa.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	mux.Lock()
+	if testarossa.Equal(t, n, len(received)) {
+		for i := 0; i < n; i++ {
+			testarossa.Equal(t, strconv.Itoa(i), received[i])
+		}
+	}
+	mux.Unlock()
+	testarossa.Equal(t, int32(n), wildcardCount.Load())
+	testarossa.Equal(t, int32(n), queueCount.Load())
+
+	// Unsubscribed subscribers receive no more messages
+	for _, s := range queueSubs {
+		err = s.Unsubscribe()
+		testarossa.NoError(t, err)
+	}
+	err = alpha.Publish(subject, []byte("after"))
+	testarossa.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	testarossa.Equal(t, int32(n), queueCount.Load())
+	testarossa.Equal(t, int32(n+1), wildcardCount.Load())
+
+	// Closed connections receive no more messages
+	beta.Close()
+	err = alpha.Publish(subject, []byte("closed"))
+	testarossa.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	testarossa.Equal(t, int32(n+1), wildcardCount.Load())
+	mux.Lock()
+	testarossa.Equal(t, n+2, len(received))
+	mux.Unlock()
+}
+
+func TestTransport_NoTransport(t *testing.T) {
+	t.Parallel()
+
+	_, err := Open(nil, false)
+	testarossa.Error(t, err)
+
+	var closed *Conn
+	err = closed.Publish("subject", []byte("x"))
+	testarossa.Error(t, err)
+	_, err = closed.Subscribe("subject", func(msg *Msg) {})
+	testarossa.Error(t, err)
+}
+
+func TestTransport_ShortCircuitOverNATS(t *testing.T) {
+	t.Parallel()
+
+	// Start a NATS server
+	ns, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	testarossa.NoError(t, err)
+	ns.Start()
+	defer ns.Shutdown()
+	testarossa.True(t, ns.ReadyForConnections(10*time.Second))
+
+	subject := "test." + rand.AlphaNum32(8) + ".subject"
+	connect := func(shortCircuit bool) *Conn {
+		cn, err := nats.Connect(ns.ClientURL())
+		testarossa.NoError(t, err)
+		conn, err := Open(cn, shortCircuit)
+		testarossa.NoError(t, err)
+		return conn
+	}
+
+	// Alpha and beta are in the same process, gamma simulates a remote process
+	alpha := connect(true)
+	defer alpha.Close()
+	beta := connect(true)
+	defer beta.Close()
+	gamma := connect(false)
+	defer gamma.Close()
+
+	var alphaAll, gammaAll, alphaQueue, gammaQueue, betaOther atomic.Int32
+	count := func(counter *atomic.Int32) MsgHandler {
+		return func(msg *Msg) {
+			counter.Add(1)
+		}
+	}
+	_, err = alpha.Subscribe(subject, count(&alphaAll))
+	testarossa.NoError(t, err)
+	_, err = gamma.Subscribe(subject, count(&gammaAll))
+	testarossa.NoError(t, err)
+	_, err = alpha.QueueSubscribe(subject, "queue", count(&alphaQueue))
+	testarossa.NoError(t, err)
+	_, err = gamma.QueueSubscribe(subject, "queue", count(&gammaQueue))
+	testarossa.NoError(t, err)
+	_, err = beta.QueueSubscribe(subject, "other", count(&betaOther))
+	testarossa.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	// Messages published in the process are delivered exactly once, with local members of the queue preferred
+	const n = 32
+	for i := 0; i < n; i++ {
+		err = beta.Publish(subject, []byte("local"))
+		testarossa.NoError(t, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	testarossa.Equal(t, int32(n), alphaAll.Load())
+	testarossa.Equal(t, int32(n), gammaAll.Load())
+	testarossa.Equal(t, int32(n), alphaQueue.Load())
+	testarossa.Equal(t, int32(0), gammaQueue.Load())
+	testarossa.Equal(t, int32(n), betaOther.Load())
+
+	// Messages published remotely are delivered exactly once over NATS
+	alphaAll.Store(0)
+	gammaAll.Store(0)
+	alphaQueue.Store(0)
+	gammaQueue.Store(0)
+	betaOther.Store(0)
+	for i := 0; i < n; i++ {
+		err = gamma.Publish(subject, []byte("remote"))
+		testarossa.NoError(t, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	testarossa.Equal(t, int32(n), alphaAll.Load())
+	testarossa.Equal(t, int32(n), gammaAll.Load())
+	testarossa.Equal(t, int32(n), alphaQueue.Load()+gammaQueue.Load())
+	testarossa.Equal(t, int32(n), betaOther.Load())
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2023-2024 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/microbus-io/fabric/errors"
+	"github.com/nats-io/nats.go"
+)
+
+// Headers of messages that are moved to the dead-letter subject
+const (
+	HeaderDeadLetterError      = "Microbus-Dead-Letter-Error"
+	HeaderDeadLetterQueue      = "Microbus-Dead-Letter-Queue"
+	HeaderDeadLetterDeliveries = "Microbus-Dead-Letter-Deliveries"
+)
+
+// DurableHandler handles a message delivered to a durable subscription.
+// The message is acknowledged if the handler returns nil, otherwise it is redelivered.
+type DurableHandler func(msg *Msg) error
+
+// DurableOptions are the options of a durable subscription.
+type DurableOptions struct {
+	// Stream is the name of the JetStream stream that stores the messages
+	Stream string
+	// Consumer is the name of the durable consumer, shared by all members of the queue
+	Consumer string
+	// Queue is the queue among whose members the messages are distributed
+	Queue string
+	// MaxDeliveries is the number of times a message is delivered before it is moved to the dead-letter subject
+	MaxDeliveries int
+	// Backoff is the delay before the first redelivery. It doubles with each subsequent redelivery
+	Backoff time.Duration
+	// AckWait is the duration after which a message that was not acknowledged is redelivered
+	AckWait time.Duration
+	// DeadLetterSubject returns the subject to which a message published to the subject is moved after its deliveries are exhausted
+	DeadLetterSubject func(subject string) string
+}
+
+// jetStream returns the JetStream context of the NATS connection.
+func (c *Conn) jetStream() (nats.JetStreamContext, error) {
+	if c.natsConn == nil {
+		return nil, errors.New("durable delivery requires NATS")
+	}
+	js, err := c.natsConn.JetStream()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return js, nil
+}
+
+// EnsureStream creates a JetStream stream that stores the messages published to the subjects, if it does not already exist.
+// If interest is true, a message is removed once all the consumers of the stream acknowledged it.
+// Regardless, messages are removed after the max age.
+func (c *Conn) EnsureStream(name string, subjects []string, interest bool, maxAge time.Duration) error {
+	js, err := c.jetStream()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = js.StreamInfo(name)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return errors.Trace(err)
+	}
+	retention := nats.LimitsPolicy
+	if interest {
+		retention = nats.InterestPolicy
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:      name,
+		Subjects:  subjects,
+		Retention: retention,
+		MaxAge:    maxAge,
+		Storage:   nats.FileStorage,
+	})
+	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// PublishDurable stores a message in the JetStream stream that captures the subject.
+// It returns after the message is acknowledged by the stream.
+// Durable messages are always published over NATS.
+func (c *Conn) PublishDurable(subject string, data []byte) error {
+	js, err := c.jetStream()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = js.Publish(subject, data)
+	return errors.Trace(err)
+}
+
+// DurableSubscribe subscribes to messages stored in a JetStream stream.
+// Each message is delivered to one member of the queue and acknowledged when the handler succeeds.
+// A failed message is redelivered with an exponential backoff until its deliveries are exhausted,
+// at which point it is moved to the dead-letter subject.
+// The durable consumer outlives the subscription so that messages published while no member of the queue is subscribed are not lost.
+func (c *Conn) DurableSubscribe(subject string, opts DurableOptions, handler DurableHandler) (s *Subscription, err error) {
+	if opts.Queue == "" {
+		return nil, errors.New("durable subscription requires a queue")
+	}
+	js, err := c.jetStream()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	consumerConfig := &nats.ConsumerConfig{
+		Durable:        opts.Consumer,
+		DeliverSubject: "_durable." + opts.Stream + "." + opts.Consumer,
+		DeliverGroup:   opts.Queue,
+		DeliverPolicy:  nats.DeliverNewPolicy,
+		AckPolicy:      nats.AckExplicitPolicy,
+		AckWait:        opts.AckWait,
+		MaxDeliver:     opts.MaxDeliveries + 1, // One extra delivery in case moving to the dead-letter subject fails
+		FilterSubject:  subject,
+	}
+	_, err = js.AddConsumer(opts.Stream, consumerConfig)
+	if errors.Is(err, nats.ErrConsumerNameAlreadyInUse) {
+		_, err = js.UpdateConsumer(opts.Stream, consumerConfig)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	natsHandler := func(msg *nats.Msg) {
+		go c.handleDurable(js, msg, opts, handler)
+	}
+	// Binding to the consumer prevents it from being deleted when unsubscribing
+	s = &Subscription{conn: c}
+	s.natsSub, err = js.QueueSubscribe(subject, opts.Queue, natsHandler, nats.Bind(opts.Stream, opts.Consumer), nats.ManualAck())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+// handleDurable calls the handler of a durable subscription and acknowledges the message accordingly.
+func (c *Conn) handleDurable(js nats.JetStreamContext, msg *nats.Msg, opts DurableOptions, handler DurableHandler) {
+	handlerErr := handler(&Msg{
+		Subject: msg.Subject,
+		Data:    msg.Data,
+	})
+	if handlerErr == nil {
+		msg.Ack()
+		return
+	}
+	deliveries := 1
+	if meta, err := msg.Metadata(); err == nil {
+		deliveries = int(meta.NumDelivered)
+	}
+	if deliveries < opts.MaxDeliveries {
+		delay := opts.Backoff
+		for i := 1; i < deliveries && delay < time.Hour; i++ {
+			delay *= 2
+		}
+		msg.NakWithDelay(delay)
+		return
+	}
+	// Move to the dead-letter subject
+	deadLetter := nats.NewMsg(opts.DeadLetterSubject(msg.Subject))
+	deadLetter.Data = msg.Data
+	deadLetter.Header.Set(HeaderDeadLetterError, handlerErr.Error())
+	deadLetter.Header.Set(HeaderDeadLetterQueue, opts.Queue)
+	deadLetter.Header.Set(HeaderDeadLetterDeliveries, strconv.Itoa(deliveries))
+	_, err := js.PublishMsg(deadLetter)
+	if err != nil {
+		msg.NakWithDelay(opts.Backoff)
+		return
+	}
+	msg.Term()
+}
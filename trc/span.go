@@ -143,6 +143,21 @@ func (s Span) Log(severity string, msg string, args ...any) {
 	s.internal.AddEvent("log", trace.WithAttributes(attrs...))
 }
 
+// AddEvent records a named event on the span, with attributes given as alternating keys and values.
+func (s Span) AddEvent(name string, args ...any) {
+	if s.internal == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{}
+	slogRec := slog.NewRecord(time.Time{}, slog.LevelInfo, name, 0)
+	slogRec.Add(args...)
+	slogRec.Attrs(func(f slog.Attr) bool {
+		attrs = append(attrs, slogToTracingAttrs("", f)...)
+		return true
+	})
+	s.internal.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
 // SetString tags the span during its creation.
 func (s Span) SetString(k string, v string) {
 	if s.internal == nil {
@@ -27,6 +27,7 @@ var (
 	hostnameValidator = regexp.MustCompile(`^[a-z0-9_\-]+(\.[a-z0-9_\-]+)*$`)
 	configValidator   = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
 	tickerValidator   = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	healthValidator   = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
 )
 
 // ValidateHostname indicates if the hostname is a valid microservice hostname.
@@ -55,3 +56,12 @@ func ValidateTickerName(name string) error {
 	}
 	return nil
 }
+
+// ValidateHealthCheckName indicates if the name can be used for a health check.
+// Health check names must start with a letter and contain only alphanumeric characters.
+func ValidateHealthCheckName(name string) error {
+	if !healthValidator.MatchString(strings.ToLower(name)) {
+		return errors.Newf("invalid health check name '%s'", name)
+	}
+	return nil
+}
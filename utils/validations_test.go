@@ -96,3 +96,27 @@ func TestUtils_ValidateTickerName(t *testing.T) {
 		testarossa.Error(t, ValidateTickerName(x), "%s", x)
 	}
 }
+
+func TestUtils_ValidateHealthCheckName(t *testing.T) {
+	valid := []string{
+		"database",
+		"NATS",
+		"cache2",
+	}
+	invalid := []string{
+		"my database",
+		"my-database",
+		"my_database",
+		"my.database",
+		"2cache",
+		"_database",
+		"",
+	}
+
+	for _, x := range valid {
+		testarossa.NoError(t, ValidateHealthCheckName(x), "%s", x)
+	}
+	for _, x := range invalid {
+		testarossa.Error(t, ValidateHealthCheckName(x), "%s", x)
+	}
+}